@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE episodes (
+  id SERIAL PRIMARY KEY,
+  spotify_id TEXT NOT NULL,
+  show_id INTEGER NOT NULL REFERENCES shows (id) ON DELETE CASCADE,
+  name TEXT,
+  duration_ms INTEGER,
+  updated_at TIMESTAMPTZ,
+
+  UNIQUE(spotify_id)
+);
+
+CREATE TABLE episode_history (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  episode_id INTEGER NOT NULL REFERENCES episodes (id) ON DELETE CASCADE,
+  played_at TIMESTAMPTZ NOT NULL,
+  position_ms INTEGER NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE episode_history;
+
+DROP TABLE episodes;
+-- +goose StatementEnd
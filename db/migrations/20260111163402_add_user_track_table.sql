@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE user_tracks (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  track_id INTEGER NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
+  saved_at TIMESTAMPTZ NOT NULL,
+  deleted_at TIMESTAMPTZ
+);
+
+ALTER TABLE links
+ADD COLUMN source_user_id INTEGER REFERENCES users (id) ON DELETE CASCADE;
+
+ALTER TABLE links
+DROP CONSTRAINT source_exclusive;
+
+ALTER TABLE links
+ADD CONSTRAINT source_exclusive CHECK (
+  (
+    (source_directory_id IS NOT NULL)::int +
+    (source_playlist_id IS NOT NULL)::int +
+    (source_user_id IS NOT NULL)::int
+  ) = 1
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM links
+WHERE source_user_id IS NOT NULL;
+
+ALTER TABLE links
+DROP CONSTRAINT source_exclusive;
+
+ALTER TABLE links
+ADD CONSTRAINT source_exclusive CHECK (
+  (source_directory_id IS NOT NULL AND source_playlist_id IS NULL) OR
+  (source_directory_id IS NULL AND source_playlist_id IS NOT NULL)
+);
+
+ALTER TABLE links
+DROP COLUMN source_user_id;
+
+DROP TABLE user_tracks;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE artist_users (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  artist_id INTEGER NOT NULL REFERENCES artists (id) ON DELETE CASCADE,
+  deleted_at TIMESTAMPTZ
+);
+
+CREATE TABLE artist_releases (
+  id SERIAL PRIMARY KEY,
+  artist_id INTEGER NOT NULL REFERENCES artists (id) ON DELETE CASCADE,
+  album_id INTEGER NOT NULL REFERENCES albums (id) ON DELETE CASCADE,
+  album_type TEXT NOT NULL,
+  release_date DATE NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+  UNIQUE (artist_id, album_id)
+);
+
+ALTER TABLE artists
+ADD COLUMN releases_checked_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE artists
+DROP COLUMN releases_checked_at;
+
+DROP TABLE artist_releases;
+
+DROP TABLE artist_users;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE link_mode AS ENUM ('append', 'mirror', 'bidirectional');
+
+ALTER TABLE links
+ADD COLUMN mode LINK_MODE NOT NULL DEFAULT 'append';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE links
+DROP COLUMN mode;
+
+DROP TYPE link_mode;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE tracks
+ADD COLUMN explicit BOOLEAN;
+
+ALTER TABLE links
+ADD COLUMN filter JSONB NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE links
+DROP COLUMN filter;
+
+ALTER TABLE tracks
+DROP COLUMN explicit;
+-- +goose StatementEnd
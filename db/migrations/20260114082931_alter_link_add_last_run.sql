@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE links
+ADD COLUMN last_run_at TIMESTAMPTZ,
+ADD COLUMN last_run_added INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN last_run_deleted INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN last_run_error TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE links
+DROP COLUMN last_run_at,
+DROP COLUMN last_run_added,
+DROP COLUMN last_run_deleted,
+DROP COLUMN last_run_error;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE tracks
+ADD COLUMN album_id INTEGER REFERENCES albums (id) ON DELETE SET NULL;
+
+CREATE INDEX history_user_id_played_at_idx ON history (user_id, played_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX history_user_id_played_at_idx;
+
+ALTER TABLE tracks
+DROP COLUMN album_id;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
+
+-- Plays per local day and hour
+-- Refreshed by a recurring task
+CREATE MATERIALIZED VIEW history_rollups AS
+SELECT
+  h.user_id,
+  (h.played_at AT TIME ZONE u.timezone)::date AS day,
+  EXTRACT(HOUR FROM h.played_at AT TIME ZONE u.timezone)::int AS hour,
+  h.track_id,
+  COALESCE(h.playlist_id, 0)::int AS playlist_id, -- 0 if not played from a playlist
+  COUNT(*)::int AS plays
+FROM history h
+JOIN users u ON u.id = h.user_id
+GROUP BY 1, 2, 3, 4, 5;
+
+-- Required to refresh concurrently
+CREATE UNIQUE INDEX history_rollups_unique_idx ON history_rollups (user_id, day, hour, track_id, playlist_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP MATERIALIZED VIEW history_rollups;
+
+ALTER TABLE users
+DROP COLUMN timezone;
+-- +goose StatementEnd
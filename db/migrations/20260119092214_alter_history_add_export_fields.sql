@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE history
+ADD COLUMN reason_start TEXT,
+ADD COLUMN reason_end TEXT,
+ADD COLUMN shuffle BOOLEAN,
+ADD COLUMN offline BOOLEAN,
+ADD COLUMN platform TEXT,
+ADD COLUMN conn_country TEXT;
+
+ALTER TABLE episode_history
+ADD COLUMN reason_start TEXT,
+ADD COLUMN reason_end TEXT,
+ADD COLUMN shuffle BOOLEAN,
+ADD COLUMN offline BOOLEAN,
+ADD COLUMN platform TEXT,
+ADD COLUMN conn_country TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE episode_history
+DROP COLUMN conn_country,
+DROP COLUMN platform,
+DROP COLUMN offline,
+DROP COLUMN shuffle,
+DROP COLUMN reason_end,
+DROP COLUMN reason_start;
+
+ALTER TABLE history
+DROP COLUMN conn_country,
+DROP COLUMN platform,
+DROP COLUMN offline,
+DROP COLUMN shuffle,
+DROP COLUMN reason_end,
+DROP COLUMN reason_start;
+-- +goose StatementEnd
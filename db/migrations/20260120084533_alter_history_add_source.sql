@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE history
+ADD COLUMN source TEXT NOT NULL DEFAULT 'poll' CHECK (source IN ('poll', 'export', 'recently_played'));
+
+ALTER TABLE episode_history
+ADD COLUMN source TEXT NOT NULL DEFAULT 'poll' CHECK (source IN ('poll', 'export'));
+
+CREATE INDEX episode_history_user_id_played_at_idx ON episode_history (user_id, played_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX episode_history_user_id_played_at_idx;
+
+ALTER TABLE episode_history
+DROP COLUMN source;
+
+ALTER TABLE history
+DROP COLUMN source;
+-- +goose StatementEnd
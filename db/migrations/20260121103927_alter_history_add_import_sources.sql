@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE history
+DROP CONSTRAINT history_source_check;
+
+ALTER TABLE history
+ADD CONSTRAINT history_source_check CHECK (source IN ('poll', 'export', 'recently_played', 'lastfm', 'listenbrainz'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM history
+WHERE source IN ('lastfm', 'listenbrainz');
+
+ALTER TABLE history
+DROP CONSTRAINT history_source_check;
+
+ALTER TABLE history
+ADD CONSTRAINT history_source_check CHECK (source IN ('poll', 'export', 'recently_played'));
+-- +goose StatementEnd
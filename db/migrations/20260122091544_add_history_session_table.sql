@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE history_sessions (
+  id SERIAL PRIMARY KEY,
+  user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  start_at TIMESTAMPTZ NOT NULL,
+  end_at TIMESTAMPTZ NOT NULL,
+  -- Most played context, at most one is set
+  playlist_id INTEGER REFERENCES playlists (id) ON DELETE SET NULL,
+  album_id INTEGER REFERENCES albums (id) ON DELETE SET NULL,
+  artist_id INTEGER REFERENCES artists (id) ON DELETE SET NULL,
+  show_id INTEGER REFERENCES shows (id) ON DELETE SET NULL
+);
+
+CREATE INDEX history_sessions_user_id_start_at_idx ON history_sessions (user_id, start_at);
+
+ALTER TABLE history
+ADD COLUMN session_id INTEGER REFERENCES history_sessions (id) ON DELETE SET NULL;
+
+CREATE INDEX history_session_id_idx ON history (session_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX history_session_id_idx;
+
+ALTER TABLE history
+DROP COLUMN session_id;
+
+DROP TABLE history_sessions;
+-- +goose StatementEnd
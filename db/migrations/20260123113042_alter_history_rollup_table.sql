@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP MATERIALIZED VIEW history_rollups;
+
+-- Plays per local day and hour
+-- Only the days with new plays are calculated again by a recurring task
+CREATE TABLE history_rollups (
+  user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  day DATE NOT NULL,
+  hour INTEGER NOT NULL,
+  track_id INTEGER NOT NULL REFERENCES tracks (id) ON DELETE CASCADE,
+  playlist_id INTEGER NOT NULL DEFAULT 0, -- 0 if not played from a playlist
+  plays INTEGER NOT NULL,
+
+  PRIMARY KEY (user_id, day, hour, track_id, playlist_id)
+);
+
+-- Most recent play that is part of the rollup, 0 to calculate everything again
+ALTER TABLE users
+ADD COLUMN rollup_history_id INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+DROP COLUMN rollup_history_id;
+
+DROP TABLE history_rollups;
+
+CREATE MATERIALIZED VIEW history_rollups AS
+SELECT
+  h.user_id,
+  (h.played_at AT TIME ZONE u.timezone)::date AS day,
+  EXTRACT(HOUR FROM h.played_at AT TIME ZONE u.timezone)::int AS hour,
+  h.track_id,
+  COALESCE(h.playlist_id, 0)::int AS playlist_id,
+  COUNT(*)::int AS plays
+FROM history h
+JOIN users u ON u.id = h.user_id
+GROUP BY 1, 2, 3, 4, 5;
+
+CREATE UNIQUE INDEX history_rollups_unique_idx ON history_rollups (user_id, day, hour, track_id, playlist_id);
+-- +goose StatementEnd
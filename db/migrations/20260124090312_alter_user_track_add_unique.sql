@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Keep the oldest of the duplicate live links
+DELETE FROM user_tracks ut
+USING user_tracks o
+WHERE
+  o.user_id = ut.user_id AND
+  o.track_id = ut.track_id AND
+  o.deleted_at IS NULL AND
+  ut.deleted_at IS NULL AND
+  o.id < ut.id;
+
+CREATE UNIQUE INDEX user_tracks_user_id_track_id_idx ON user_tracks (user_id, track_id)
+WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX user_tracks_user_id_track_id_idx;
+-- +goose StatementEnd
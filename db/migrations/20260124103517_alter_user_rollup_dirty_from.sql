@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+DROP COLUMN rollup_history_id;
+
+-- Earliest play that changed since the last rollup refresh, NULL if the rollup is up to date
+-- '-infinity' calculates everything again
+ALTER TABLE users
+ADD COLUMN rollup_dirty_from TIMESTAMPTZ;
+
+UPDATE users
+SET rollup_dirty_from = '-infinity';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+DROP COLUMN rollup_dirty_from;
+
+ALTER TABLE users
+ADD COLUMN rollup_history_id INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
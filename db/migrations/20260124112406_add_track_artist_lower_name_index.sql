@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Imported plays are matched on the lowercase track and artist name
+CREATE INDEX tracks_lower_name_idx ON tracks (lower(name));
+CREATE INDEX artists_lower_name_idx ON artists (lower(name));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX artists_lower_name_idx;
+DROP INDEX tracks_lower_name_idx;
+-- +goose StatementEnd
@@ -7,6 +7,12 @@ SELECT *
 FROM artists
 WHERE spotify_id = $1;
 
+-- name: ArtistGetByUser :many
+SELECT a.*
+FROM artists a
+LEFT JOIN artist_users au ON au.artist_id = a.id
+WHERE au.user_id = $1 AND au.deleted_at IS NULL;
+
 -- name: ArtistGetByAlbum :many
 SELECT a.*
 FROM artists a
@@ -34,3 +40,8 @@ SET
   cover_url = coalesce(sqlc.narg('cover_url'), cover_url),
   updated_at = NOW()
 WHERE id = $1;
+
+-- name: ArtistUpdateReleasesCheckedAt :exec
+UPDATE artists
+SET releases_checked_at = NOW()
+WHERE id = $1;
@@ -0,0 +1,20 @@
+-- name: ArtistReleaseGetByUserPopulated :many
+SELECT sqlc.embed(ar), sqlc.embed(a), sqlc.embed(al)
+FROM artist_releases ar
+JOIN artist_users au ON au.artist_id = ar.artist_id
+LEFT JOIN artists a ON a.id = ar.artist_id
+LEFT JOIN albums al ON al.id = ar.album_id
+WHERE au.user_id = $1 AND au.deleted_at IS NULL
+ORDER BY ar.release_date DESC, ar.id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: ArtistReleaseGetByArtistPopulated :many
+SELECT sqlc.embed(ar), sqlc.embed(al)
+FROM artist_releases ar
+JOIN albums al ON al.id = ar.album_id
+WHERE ar.artist_id = $1;
+
+-- name: ArtistReleaseCreate :one
+INSERT INTO artist_releases (artist_id, album_id, album_type, release_date)
+VALUES ($1, $2, $3, $4)
+RETURNING id;
@@ -0,0 +1,9 @@
+-- name: ArtistUserCreate :one
+INSERT INTO artist_users (user_id, artist_id)
+VALUES ($1, $2)
+RETURNING id;
+
+-- name: ArtistUserDeleteByUserArtist :exec
+UPDATE artist_users
+SET deleted_at = NOW()
+WHERE user_id = $1 AND artist_id = $2 AND deleted_at IS NULL;
@@ -4,13 +4,17 @@ FROM directories d
 WHERE d.user_id = $1;
 
 -- name: DirectoryCreate :one
-INSERT INTO directories (user_id, name, parent_id)
-VALUES ($1, $2, $3)
+INSERT INTO directories (user_id, name, parent_id, rules)
+VALUES ($1, $2, $3, $4)
 RETURNING id;
 
 -- name: DirectoryUpdate :exec
 UPDATE directories
-SET name = $2, parent_id = $3
+SET name = $2, parent_id = $3, rules = $4
+WHERE id = $1;
+
+-- name: DirectoryDelete :exec
+DELETE FROM directories
 WHERE id = $1;
 
 -- name: DirectoryDeleteByUser :exec
@@ -0,0 +1,15 @@
+-- name: EpisodeGetBySpotify :one
+SELECT *
+FROM episodes
+WHERE spotify_id = $1;
+
+-- name: EpisodeGetAllBySpotify :many
+SELECT *
+FROM episodes
+WHERE spotify_id = ANY($1::text[]);
+
+-- name: EpisodeCreate :one
+INSERT INTO episodes (spotify_id, show_id, name, duration_ms)
+VALUES ($1, $2, $3, $4)
+RETURNING id;
+
@@ -0,0 +1,110 @@
+-- name: EpisodeHistoryGetLatestPopulated :one
+SELECT sqlc.embed(eh), sqlc.embed(e)
+FROM episode_history eh
+LEFT JOIN episodes e ON e.id = eh.episode_id
+WHERE eh.user_id = $1
+ORDER BY eh.updated_at DESC
+LIMIT 1;
+
+-- name: EpisodeHistoryGetPopulatedFilteredPaginated :many
+SELECT sqlc.embed(eh), sqlc.embed(e), sqlc.embed(s)
+FROM episode_history eh
+LEFT JOIN episodes e ON e.id = eh.episode_id
+LEFT JOIN shows s ON s.id = e.show_id
+WHERE
+  eh.user_id = $1::int AND
+  (e.show_id = $4::int OR NOT @filter_show_id) AND
+  (eh.played_at >= $5::timestamptz OR NOT @filter_start) AND
+  (eh.played_at <= $6::timestamptz OR NOT @filter_end)
+ORDER BY eh.played_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: EpisodeHistoryGetPopulatedAfter :many
+SELECT sqlc.embed(eh), sqlc.embed(e), sqlc.embed(s)
+FROM episode_history eh
+LEFT JOIN episodes e ON e.id = eh.episode_id
+LEFT JOIN shows s ON s.id = e.show_id
+WHERE eh.user_id = $1 AND eh.id > $2
+ORDER BY eh.id
+LIMIT $3;
+
+-- name: EpisodeHistoryCountExisting :one
+SELECT COUNT(*)
+FROM (
+  SELECT
+    UNNEST($2::text[]) AS spotify_id,
+    UNNEST($3::timestamptz[]) AS played_at
+) i
+WHERE EXISTS (
+  SELECT 1
+  FROM episode_history eh
+  LEFT JOIN episodes e ON e.id = eh.episode_id
+  WHERE
+    eh.user_id = $1::int AND
+    e.spotify_id = i.spotify_id AND
+    eh.played_at BETWEEN i.played_at - $4::int * INTERVAL '1 second' AND i.played_at + $4::int * INTERVAL '1 second'
+);
+
+-- name: EpisodeHistoryCreate :one
+INSERT INTO episode_history (user_id, episode_id, played_at, position_ms, source)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id;
+
+-- name: EpisodeHistoryMergeBatchUpdate :execrows
+UPDATE episode_history eh
+SET
+  reason_start = coalesce(eh.reason_start, NULLIF(i.reason_start, '')),
+  reason_end = coalesce(eh.reason_end, NULLIF(i.reason_end, '')),
+  shuffle = coalesce(eh.shuffle, NULLIF(i.shuffle, '')::boolean),
+  offline = coalesce(eh.offline, NULLIF(i.offline, '')::boolean),
+  platform = coalesce(eh.platform, NULLIF(i.platform, '')),
+  conn_country = coalesce(eh.conn_country, NULLIF(i.conn_country, ''))
+FROM (
+  SELECT
+    UNNEST($1::int[]) AS user_id,
+    UNNEST($2::int[]) AS episode_id,
+    UNNEST($3::timestamptz[]) AS played_at,
+    UNNEST($4::int[]) AS position_ms,
+    UNNEST($5::text[]) AS reason_start,
+    UNNEST($6::text[]) AS reason_end,
+    UNNEST($7::text[]) AS shuffle,
+    UNNEST($8::text[]) AS offline,
+    UNNEST($9::text[]) AS platform,
+    UNNEST($10::text[]) AS conn_country
+) i
+WHERE
+  eh.user_id = i.user_id AND
+  eh.episode_id = i.episode_id AND
+  eh.played_at BETWEEN i.played_at - $11::int * INTERVAL '1 second' AND i.played_at + $11::int * INTERVAL '1 second';
+
+-- name: EpisodeHistoryMergeBatchCreate :execrows
+INSERT INTO episode_history (user_id, episode_id, played_at, position_ms, reason_start, reason_end, shuffle, offline, platform, conn_country, source)
+SELECT i.user_id, i.episode_id, i.played_at, i.position_ms, NULLIF(i.reason_start, ''), NULLIF(i.reason_end, ''), NULLIF(i.shuffle, '')::boolean, NULLIF(i.offline, '')::boolean, NULLIF(i.platform, ''), NULLIF(i.conn_country, ''), $12::text
+FROM (
+  SELECT
+    UNNEST($1::int[]) AS user_id,
+    UNNEST($2::int[]) AS episode_id,
+    UNNEST($3::timestamptz[]) AS played_at,
+    UNNEST($4::int[]) AS position_ms,
+    UNNEST($5::text[]) AS reason_start,
+    UNNEST($6::text[]) AS reason_end,
+    UNNEST($7::text[]) AS shuffle,
+    UNNEST($8::text[]) AS offline,
+    UNNEST($9::text[]) AS platform,
+    UNNEST($10::text[]) AS conn_country
+) i
+WHERE NOT EXISTS (
+  SELECT 1
+  FROM episode_history eh
+  WHERE
+    eh.user_id = i.user_id AND
+    eh.episode_id = i.episode_id AND
+    eh.played_at BETWEEN i.played_at - $11::int * INTERVAL '1 second' AND i.played_at + $11::int * INTERVAL '1 second'
+);
+
+-- name: EpisodeHistoryUpdate :exec
+UPDATE episode_history
+SET
+  position_ms = $2,
+  updated_at = NOW()
+WHERE id = $1;
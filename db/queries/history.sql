@@ -14,7 +14,8 @@ WHERE
   h.user_id = $1::int AND
   (h.played_at >= $4::timestamptz OR NOT @filter_start) AND 
   (h.played_at <= $5::timestamptz OR NOT @filter_end) AND
-  (h.skipped = $6::boolean OR NOT @filter_skipped)
+  (h.skipped = $6::boolean OR NOT @filter_skipped) AND
+  (h.session_id = $8::int OR NOT @filter_session)
 ORDER BY h.played_at DESC
 LIMIT $2 OFFSET $3;
 
@@ -29,6 +30,32 @@ WHERE
   (h.skipped = $4::boolean OR NOT @filter_skipped)
 ORDER BY h.played_at DESC;
 
+-- name: HistoryGetPopulatedAfter :many
+SELECT sqlc.embed(h), sqlc.embed(t), COALESCE(array_agg(a.name ORDER BY ta.id) FILTER (WHERE a.name IS NOT NULL), '{}')::text[] AS artists
+FROM history h
+LEFT JOIN tracks t ON t.id = h.track_id
+LEFT JOIN track_artists ta ON ta.track_id = t.id
+LEFT JOIN artists a ON a.id = ta.artist_id
+WHERE h.user_id = $1 AND h.id > $2
+GROUP BY h.id, t.id
+ORDER BY h.id
+LIMIT $3;
+
+-- name: HistoryGetSessionNullFirst :one
+SELECT played_at
+FROM history
+WHERE user_id = $1 AND session_id IS NULL
+ORDER BY played_at
+LIMIT 1;
+
+-- name: HistoryGetSessionRebuildPopulated :many
+SELECT sqlc.embed(h), sqlc.embed(t)
+FROM history h
+LEFT JOIN tracks t ON t.id = h.track_id
+LEFT JOIN history_sessions s ON s.id = h.session_id
+WHERE h.user_id = $1 AND (h.session_id IS NULL OR s.end_at >= $2)
+ORDER BY h.played_at;
+
 -- name: HistoryGetSkippedNullPopulated :many
 SELECT sqlc.embed(h), sqlc.embed(t)
 FROM history h
@@ -36,18 +63,91 @@ LEFT JOIN tracks t ON t.id = h.track_id
 WHERE h.skipped IS NULL AND h.user_id = $1
 ORDER BY played_at ASC;
  
+-- name: HistoryGetPlayCountByUserTracks :many
+SELECT track_id, COUNT(*) AS play_count
+FROM history
+WHERE user_id = $1 AND track_id = ANY($2::int[])
+GROUP BY track_id;
+
+-- name: HistoryGetLastPlayedByPlaylist :many
+SELECT playlist_id, MAX(played_at)::timestamptz AS played_at
+FROM history
+WHERE user_id = $1 AND playlist_id IS NOT NULL
+GROUP BY playlist_id;
+
+-- name: HistoryCountExisting :one
+SELECT COUNT(*)
+FROM (
+  SELECT
+    UNNEST($2::text[]) AS spotify_id,
+    UNNEST($3::timestamptz[]) AS played_at
+) i
+WHERE EXISTS (
+  SELECT 1
+  FROM history h
+  LEFT JOIN tracks t ON t.id = h.track_id
+  WHERE
+    h.user_id = $1::int AND
+    t.spotify_id = i.spotify_id AND
+    h.played_at BETWEEN i.played_at - $4::int * INTERVAL '1 second' AND i.played_at + $4::int * INTERVAL '1 second'
+);
+
 -- name: HistoryCreate :one
-INSERT INTO history (user_id, track_id, played_at, album_id, artist_id, playlist_id, show_id, skipped)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO history (user_id, track_id, played_at, album_id, artist_id, playlist_id, show_id, skipped, source)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 RETURNING id;
 
--- name: HistoryCreateBatch :exec
-INSERT INTO history (user_id, track_id, played_at, skipped)
-VALUES (
-  UNNEST($1::int[]),
-  UNNEST($2::int[]),
-  UNNEST($3::timestamptz[]),
-  UNNEST($4::boolean[])
+-- name: HistoryMergeBatchUpdate :execrows
+UPDATE history h
+SET
+  skipped = coalesce(h.skipped, NULLIF(i.skipped, '')::boolean),
+  reason_start = coalesce(h.reason_start, NULLIF(i.reason_start, '')),
+  reason_end = coalesce(h.reason_end, NULLIF(i.reason_end, '')),
+  shuffle = coalesce(h.shuffle, NULLIF(i.shuffle, '')::boolean),
+  offline = coalesce(h.offline, NULLIF(i.offline, '')::boolean),
+  platform = coalesce(h.platform, NULLIF(i.platform, '')),
+  conn_country = coalesce(h.conn_country, NULLIF(i.conn_country, ''))
+FROM (
+  SELECT
+    UNNEST($1::int[]) AS user_id,
+    UNNEST($2::int[]) AS track_id,
+    UNNEST($3::timestamptz[]) AS played_at,
+    UNNEST($4::text[]) AS skipped,
+    UNNEST($5::text[]) AS reason_start,
+    UNNEST($6::text[]) AS reason_end,
+    UNNEST($7::text[]) AS shuffle,
+    UNNEST($8::text[]) AS offline,
+    UNNEST($9::text[]) AS platform,
+    UNNEST($10::text[]) AS conn_country
+) i
+WHERE
+  h.user_id = i.user_id AND
+  h.track_id = i.track_id AND
+  h.played_at BETWEEN i.played_at - $11::int * INTERVAL '1 second' AND i.played_at + $11::int * INTERVAL '1 second';
+
+-- name: HistoryMergeBatchCreate :execrows
+INSERT INTO history (user_id, track_id, played_at, skipped, reason_start, reason_end, shuffle, offline, platform, conn_country, source)
+SELECT i.user_id, i.track_id, i.played_at, NULLIF(i.skipped, '')::boolean, NULLIF(i.reason_start, ''), NULLIF(i.reason_end, ''), NULLIF(i.shuffle, '')::boolean, NULLIF(i.offline, '')::boolean, NULLIF(i.platform, ''), NULLIF(i.conn_country, ''), $12::text
+FROM (
+  SELECT
+    UNNEST($1::int[]) AS user_id,
+    UNNEST($2::int[]) AS track_id,
+    UNNEST($3::timestamptz[]) AS played_at,
+    UNNEST($4::text[]) AS skipped,
+    UNNEST($5::text[]) AS reason_start,
+    UNNEST($6::text[]) AS reason_end,
+    UNNEST($7::text[]) AS shuffle,
+    UNNEST($8::text[]) AS offline,
+    UNNEST($9::text[]) AS platform,
+    UNNEST($10::text[]) AS conn_country
+) i
+WHERE NOT EXISTS (
+  SELECT 1
+  FROM history h
+  WHERE
+    h.user_id = i.user_id AND
+    h.track_id = i.track_id AND
+    h.played_at BETWEEN i.played_at - $11::int * INTERVAL '1 second' AND i.played_at + $11::int * INTERVAL '1 second'
 );
 
 -- name: HistoryUpdate :exec
@@ -57,6 +157,7 @@ SET
   skipped = coalesce(sqlc.narg('skipped'), skipped)
 WHERE id = $1;
 
--- name: HistoryDeleteUserOlder :exec
-DELETE FROM history
-WHERE user_id = $1 AND played_at < $2;
+-- name: HistoryUpdateSessionBatch :exec
+UPDATE history
+SET session_id = $2
+WHERE id = ANY($1::int[]);
@@ -0,0 +1,49 @@
+-- name: HistoryRollupGetDirtyForUpdate :one
+SELECT (rollup_dirty_from AT TIME ZONE timezone)::date AS day
+FROM users
+WHERE id = $1 AND rollup_dirty_from IS NOT NULL
+FOR UPDATE;
+
+-- name: HistoryRollupDeleteByUserFrom :exec
+DELETE FROM history_rollups
+WHERE user_id = $1 AND day >= $2;
+
+-- name: HistoryRollupCreateByUserFrom :exec
+INSERT INTO history_rollups (user_id, day, hour, track_id, playlist_id, plays)
+SELECT
+  h.user_id,
+  (h.played_at AT TIME ZONE u.timezone)::date,
+  EXTRACT(HOUR FROM h.played_at AT TIME ZONE u.timezone)::int,
+  h.track_id,
+  COALESCE(h.playlist_id, 0),
+  COUNT(*)
+FROM history h
+JOIN users u ON u.id = h.user_id
+WHERE h.user_id = $1::int AND h.played_at >= $2::date::timestamp AT TIME ZONE u.timezone
+GROUP BY 1, 2, 3, 4, 5;
+
+-- name: HistoryRollupGetHeatmap :many
+SELECT EXTRACT(DOW FROM r.day)::int AS weekday, r.hour, SUM(r.plays)::int AS plays
+FROM history_rollups r
+WHERE
+  r.user_id = $1::int AND
+  (r.day >= $2::date OR NOT @filter_start) AND
+  (r.day <= $3::date OR NOT @filter_end) AND
+  (r.track_id = $4::int OR NOT @filter_track) AND
+  (r.playlist_id = $5::int OR NOT @filter_playlist) AND
+  (EXISTS (SELECT 1 FROM track_artists ta WHERE ta.track_id = r.track_id AND ta.artist_id = $6::int) OR NOT @filter_artist)
+GROUP BY weekday, r.hour
+ORDER BY weekday, r.hour;
+
+-- name: HistoryRollupGetDaily :many
+SELECT r.day, SUM(r.plays)::int AS plays
+FROM history_rollups r
+WHERE
+  r.user_id = $1::int AND
+  (r.day >= $2::date OR NOT @filter_start) AND
+  (r.day <= $3::date OR NOT @filter_end) AND
+  (r.track_id = $4::int OR NOT @filter_track) AND
+  (r.playlist_id = $5::int OR NOT @filter_playlist) AND
+  (EXISTS (SELECT 1 FROM track_artists ta WHERE ta.track_id = r.track_id AND ta.artist_id = $6::int) OR NOT @filter_artist)
+GROUP BY r.day
+ORDER BY r.day;
@@ -0,0 +1,29 @@
+-- name: HistorySessionGetFilteredPaginated :many
+SELECT
+  sqlc.embed(s),
+  count(h.id)::int AS plays,
+  count(h.id) FILTER (WHERE h.skipped)::int AS skips,
+  count(h.skipped)::int AS skips_known
+FROM history_sessions s
+LEFT JOIN history h ON h.session_id = s.id
+WHERE
+  s.user_id = $1::int AND
+  (s.end_at >= $4::timestamptz OR NOT @filter_start) AND
+  (s.start_at <= $5::timestamptz OR NOT @filter_end)
+GROUP BY s.id
+ORDER BY s.start_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: HistorySessionCreate :one
+INSERT INTO history_sessions (user_id, start_at, end_at, playlist_id, album_id, artist_id, show_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id;
+
+-- name: HistorySessionGetByUserEndAfter :many
+SELECT *
+FROM history_sessions
+WHERE user_id = $1 AND end_at >= $2;
+
+-- name: HistorySessionDeleteBatch :exec
+DELETE FROM history_sessions
+WHERE id = ANY($1::int[]);
@@ -4,18 +4,29 @@ FROM links l
 LEFT JOIN directories d ON d.id = l.source_directory_id
 LEFT JOIN playlists p ON p.id = l.source_playlist_id
 LEFT JOIN playlist_users pu ON pu.playlist_id = p.id
-WHERE d.user_id = $1 OR (pu.user_id = $1 AND pu.deleted_at IS NULL);
+WHERE d.user_id = $1 OR (pu.user_id = $1 AND pu.deleted_at IS NULL) OR l.source_user_id = $1;
 
 -- name: LinkCreate :one
-INSERT INTO links (source_directory_id, source_playlist_id, target_directory_id, target_playlist_id)
-VALUES ($1, $2, $3, $4)
+INSERT INTO links (source_directory_id, source_playlist_id, source_user_id, target_directory_id, target_playlist_id, mode, filter, include_subdirectories)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING id;
 
 -- name: LinkUpdate :exec
 UPDATE links
-SET source_directory_id = $2, source_playlist_id = $3, target_directory_id = $4, target_playlist_id = $5
+SET source_directory_id = $2, source_playlist_id = $3, source_user_id = $4, target_directory_id = $5, target_playlist_id = $6, mode = $7, filter = $8, include_subdirectories = $9
+WHERE id = $1;
+
+-- name: LinkUpdateRun :exec
+UPDATE links
+SET last_run_at = $2, last_run_added = $3, last_run_deleted = $4, last_run_error = $5, synced_at = $6
 WHERE id = $1;
 
 -- name: LinkDelete :exec
 DELETE FROM links
 WHERE id = $1;
+
+-- name: LinkDeleteBySourcePlaylistUser :exec
+DELETE FROM links l
+WHERE
+  EXISTS (SELECT 1 FROM playlist_users pu WHERE pu.playlist_id = l.source_playlist_id AND pu.user_id = $1) AND
+  NOT EXISTS (SELECT 1 FROM playlist_users pu WHERE pu.playlist_id = l.source_playlist_id AND pu.user_id != $1 AND pu.deleted_at IS NULL);
@@ -72,3 +72,18 @@ SET
   updated_at = NOW()
 WHERE spotify_id = $1;
 
+
+-- name: PlaylistUpdateOwnerClear :many
+UPDATE playlists
+SET owner_id = NULL
+WHERE owner_id = $1
+RETURNING id;
+
+-- name: PlaylistDeleteUnused :many
+DELETE FROM playlists p
+WHERE
+  p.id = ANY($1::int[]) AND
+  NOT EXISTS (SELECT 1 FROM playlist_users pu WHERE pu.playlist_id = p.id) AND
+  NOT EXISTS (SELECT 1 FROM history h WHERE h.playlist_id = p.id) AND
+  NOT EXISTS (SELECT 1 FROM generators g WHERE g.playlist_id = p.id)
+RETURNING cover_id;
@@ -3,6 +3,12 @@ SELECT *
 FROM playlist_tracks
 WHERE playlist_id = ANY($1::int[]);
 
+-- name: PlaylistTrackGetPlaylistIdsByArtist :many
+SELECT DISTINCT pt.playlist_id
+FROM playlist_tracks pt
+JOIN track_artists ta ON ta.track_id = pt.track_id
+WHERE ta.artist_id = $1 AND pt.deleted_at IS NULL;
+
 -- name: PlaylistTrackCreate :one
 INSERT INTO playlist_tracks (playlist_id, track_id)
 VALUES ($1, $2)
@@ -0,0 +1,24 @@
+-- name: RecapGetByUserPeriod :one
+SELECT *
+FROM recaps
+WHERE user_id = $1 AND year = $2 AND month = $3;
+
+-- name: RecapUpsert :one
+INSERT INTO recaps (user_id, year, month, data)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, year, month) DO UPDATE
+SET data = EXCLUDED.data, created_at = NOW()
+RETURNING id;
+
+-- name: RecapDeleteByUserOverlap :exec
+DELETE FROM recaps r
+USING users u
+WHERE
+  u.id = r.user_id AND
+  r.user_id = $1 AND
+  make_date(r.year, GREATEST(r.month, 1), 1)::timestamp AT TIME ZONE u.timezone <= $3::timestamptz AND
+  (make_date(r.year, GREATEST(r.month, 1), 1) + CASE WHEN r.month = 0 THEN INTERVAL '1 year' ELSE INTERVAL '1 month' END) AT TIME ZONE u.timezone > $2::timestamptz;
+
+-- name: RecapDeleteByUser :exec
+DELETE FROM recaps
+WHERE user_id = $1;
@@ -0,0 +1,119 @@
+-- name: StatGetSummary :one
+SELECT
+  COUNT(*) AS plays,
+  COALESCE(SUM(t.duration_ms) FILTER (WHERE h.skipped IS NOT TRUE), 0)::bigint AS duration_ms,
+  COUNT(DISTINCT h.track_id) AS distinct_tracks,
+  COUNT(*) FILTER (WHERE h.skipped) AS skips,
+  COUNT(*) FILTER (WHERE h.skipped IS NOT NULL) AS skips_known
+FROM history h
+JOIN tracks t ON t.id = h.track_id
+WHERE
+  h.user_id = $1::int AND
+  (h.played_at >= $2::timestamptz OR NOT @filter_start) AND
+  (h.played_at <= $3::timestamptz OR NOT @filter_end);
+
+-- name: StatGetTopTracks :many
+SELECT sqlc.embed(t), COUNT(*) AS plays
+FROM history h
+JOIN tracks t ON t.id = h.track_id
+WHERE
+  h.user_id = $1::int AND
+  (h.played_at >= $2::timestamptz OR NOT @filter_start) AND
+  (h.played_at <= $3::timestamptz OR NOT @filter_end)
+GROUP BY t.id
+ORDER BY plays DESC, t.id
+LIMIT $4;
+
+-- name: StatGetTopArtists :many
+SELECT sqlc.embed(a), COUNT(*) AS plays
+FROM history h
+JOIN track_artists ta ON ta.track_id = h.track_id
+JOIN artists a ON a.id = ta.artist_id
+WHERE
+  h.user_id = $1::int AND
+  (h.played_at >= $2::timestamptz OR NOT @filter_start) AND
+  (h.played_at <= $3::timestamptz OR NOT @filter_end)
+GROUP BY a.id
+ORDER BY plays DESC, a.id
+LIMIT $4;
+
+-- name: StatGetTopAlbums :many
+SELECT sqlc.embed(al), COUNT(*) AS plays
+FROM history h
+JOIN tracks t ON t.id = h.track_id
+JOIN albums al ON al.id = t.album_id
+WHERE
+  h.user_id = $1::int AND
+  (h.played_at >= $2::timestamptz OR NOT @filter_start) AND
+  (h.played_at <= $3::timestamptz OR NOT @filter_end)
+GROUP BY al.id
+ORDER BY plays DESC, al.id
+LIMIT $4;
+
+-- name: StatGetTopPlaylists :many
+SELECT sqlc.embed(p), COUNT(*) AS plays
+FROM history h
+JOIN playlists p ON p.id = h.playlist_id
+WHERE
+  h.user_id = $1::int AND
+  (h.played_at >= $2::timestamptz OR NOT @filter_start) AND
+  (h.played_at <= $3::timestamptz OR NOT @filter_end)
+GROUP BY p.id
+ORDER BY plays DESC, p.id
+LIMIT $4;
+
+-- name: StatGetLongestStreak :one
+WITH days AS (
+  SELECT DISTINCT (h.played_at AT TIME ZONE u.timezone)::date AS day
+  FROM history h
+  JOIN users u ON u.id = h.user_id
+  WHERE h.user_id = @user_id::int AND h.played_at >= @start::timestamptz AND h.played_at < @end_at::timestamptz
+), streaks AS (
+  SELECT COUNT(*) AS length
+  FROM (SELECT day - (ROW_NUMBER() OVER (ORDER BY day))::int AS streak FROM days) d
+  GROUP BY streak
+)
+SELECT COALESCE(MAX(length), 0)::int
+FROM streaks;
+
+-- name: StatGetTopHour :one
+SELECT EXTRACT(HOUR FROM h.played_at AT TIME ZONE u.timezone)::int AS hour, COUNT(*) AS plays
+FROM history h
+JOIN users u ON u.id = h.user_id
+WHERE h.user_id = @user_id::int AND h.played_at >= @start::timestamptz AND h.played_at < @end_at::timestamptz
+GROUP BY hour
+ORDER BY plays DESC, hour
+LIMIT 1;
+
+-- name: StatGetTopWeekday :one
+SELECT EXTRACT(DOW FROM h.played_at AT TIME ZONE u.timezone)::int AS weekday, COUNT(*) AS plays
+FROM history h
+JOIN users u ON u.id = h.user_id
+WHERE h.user_id = @user_id::int AND h.played_at >= @start::timestamptz AND h.played_at < @end_at::timestamptz
+GROUP BY weekday
+ORDER BY plays DESC, weekday
+LIMIT 1;
+
+-- name: StatGetTopDiscovery :one
+SELECT sqlc.embed(t), COUNT(*) AS plays
+FROM history h
+JOIN tracks t ON t.id = h.track_id
+WHERE
+  h.user_id = @user_id::int AND h.played_at >= @start::timestamptz AND h.played_at < @end_at::timestamptz AND
+  NOT EXISTS (
+    SELECT 1
+    FROM history h2
+    WHERE h2.user_id = h.user_id AND h2.track_id = h.track_id AND h2.played_at < @start::timestamptz
+  )
+GROUP BY t.id
+ORDER BY plays DESC, t.id
+LIMIT 1;
+
+-- name: StatGetTopSkipped :one
+SELECT sqlc.embed(t), COUNT(*) AS plays
+FROM history h
+JOIN tracks t ON t.id = h.track_id
+WHERE h.user_id = @user_id::int AND h.played_at >= @start::timestamptz AND h.played_at < @end_at::timestamptz AND h.skipped
+GROUP BY t.id
+ORDER BY plays DESC, t.id
+LIMIT 1;
@@ -17,17 +17,64 @@ SELECT *
 FROM tracks
 WHERE spotify_id = ANY($1::text[]);
 
+-- name: TrackGetMatchByArtistName :many
+SELECT DISTINCT ON (i.idx) i.idx::int AS idx, t.id
+FROM (
+  SELECT
+    UNNEST($1::text[]) AS artist,
+    UNNEST($2::text[]) AS name,
+    UNNEST($3::int[]) AS duration_ms,
+    generate_subscripts($1::text[], 1) AS idx
+) i
+JOIN tracks t ON lower(t.name) = lower(i.name)
+JOIN track_artists ta ON ta.track_id = t.id
+JOIN artists a ON a.id = ta.artist_id
+WHERE
+  lower(a.name) = lower(i.artist) AND
+  (i.duration_ms = 0 OR t.duration_ms IS NULL OR abs(t.duration_ms - i.duration_ms) <= $4::int)
+ORDER BY i.idx, abs(coalesce(t.duration_ms, i.duration_ms) - i.duration_ms), t.id;
+
 -- name: TrackGetByName :many
 SELECT *
 FROM tracks
 WHERE name = $1;
 
 -- name: TrackGetByPlaylist :many
-SELECT t.*
+SELECT sqlc.embed(t), pt.created_at AS added_at
 FROM tracks t
 LEFT JOIN playlist_tracks pt ON pt.track_id = t.id
 WHERE pt.playlist_id = $1 AND pt.deleted_at IS NULL;
 
+-- name: TrackGetSavedByUser :many
+SELECT sqlc.embed(t), ut.saved_at AS added_at
+FROM tracks t
+LEFT JOIN user_tracks ut ON ut.track_id = t.id
+WHERE ut.user_id = $1 AND ut.deleted_at IS NULL;
+
+-- name: TrackGetSavedInPlaylistByUser :many
+SELECT sqlc.embed(t), sqlc.embed(p)
+FROM user_tracks ut
+JOIN tracks t ON t.id = ut.track_id
+JOIN playlist_tracks pt ON pt.track_id = t.id
+JOIN playlists p ON p.id = pt.playlist_id
+JOIN playlist_users pu ON pu.playlist_id = p.id AND pu.user_id = ut.user_id
+WHERE
+  ut.user_id = $1 AND ut.deleted_at IS NULL AND
+  pt.deleted_at IS NULL AND pu.deleted_at IS NULL AND p.owner_id = ut.user_id
+ORDER BY t.id, p.id, pt.id;
+
+-- name: TrackGetDeletedByPlaylistAfter :many
+SELECT DISTINCT t.*
+FROM tracks t
+JOIN playlist_tracks pt ON pt.track_id = t.id
+WHERE pt.playlist_id = $1 AND pt.deleted_at > $2;
+
+-- name: TrackGetSavedDeletedByUserAfter :many
+SELECT DISTINCT t.*
+FROM tracks t
+JOIN user_tracks ut ON ut.track_id = t.id
+WHERE ut.user_id = $1 AND ut.deleted_at > $2;
+
 -- name: TrackGetByGenerator :many
 SELECT t.*
 FROM tracks t
@@ -63,9 +110,25 @@ WHERE
 ORDER BY pt.deleted_at DESC
 LIMIT $1 OFFSET $2;
 
+-- name: TrackGetSavedCreatedFiltered :many
+SELECT sqlc.embed(t), sqlc.embed(ut)
+FROM tracks t
+LEFT JOIN user_tracks ut ON ut.track_id = t.id
+WHERE ut.user_id = $3::int AND ut.deleted_at IS NULL
+ORDER BY ut.saved_at DESC
+LIMIT $1 OFFSET $2;
+
+-- name: TrackGetSavedDeletedFiltered :many
+SELECT sqlc.embed(t), sqlc.embed(ut)
+FROM tracks t
+LEFT JOIN user_tracks ut ON ut.track_id = t.id
+WHERE ut.user_id = $3::int AND ut.deleted_at IS NOT NULL
+ORDER BY ut.deleted_at DESC
+LIMIT $1 OFFSET $2;
+
 -- name: TrackCreate :one
-INSERT INTO tracks (spotify_id, name, popularity, duration_ms)
-VALUES ($1, $2, $3, $4)
+INSERT INTO tracks (spotify_id, name, popularity, duration_ms, explicit)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING id;
 
 -- name: TrackUpdate :exec
@@ -74,5 +137,7 @@ SET
   name = coalesce(sqlc.narg('name'), name),
   popularity = coalesce(sqlc.narg('popularity'), popularity),
   duration_ms = coalesce(sqlc.narg('duration_ms'), duration_ms),
+  explicit = coalesce(sqlc.narg('explicit'), explicit),
+  album_id = coalesce(sqlc.narg('album_id'), album_id),
   updated_at = NOW()
 WHERE id = $1;
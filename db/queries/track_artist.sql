@@ -1,3 +1,8 @@
+-- name: TrackArtistGetByTracks :many
+SELECT *
+FROM track_artists
+WHERE track_id = ANY($1::int[]);
+
 -- name: TrackArtistCreate :one
 INSERT INTO track_artists (track_id, artist_id)
 VALUES ($1, $2)
@@ -27,3 +27,33 @@ RETURNING id;
 UPDATE users
 SET name = $2, display_name = $3, email = $4
 WHERE id = $1;
+
+-- name: UserUpdateRecentlyPlayedAfter :exec
+UPDATE users
+SET recently_played_after = $2
+WHERE id = $1;
+
+-- name: UserUpdateTimezone :exec
+UPDATE users
+SET timezone = $2, rollup_dirty_from = '-infinity'
+WHERE id = $1;
+
+-- name: UserUpdateRollupDirtyFrom :exec
+UPDATE users
+SET rollup_dirty_from = LEAST(rollup_dirty_from, $2)
+WHERE id = $1;
+
+-- name: UserUpdateRollupDirtyFromHistory :exec
+UPDATE users u
+SET rollup_dirty_from = LEAST(u.rollup_dirty_from, h.played_at)
+FROM history h
+WHERE h.id = $1 AND u.id = h.user_id;
+
+-- name: UserUpdateRollupClean :exec
+UPDATE users
+SET rollup_dirty_from = NULL
+WHERE id = $1;
+
+-- name: UserDelete :exec
+DELETE FROM users
+WHERE id = $1;
@@ -0,0 +1,9 @@
+-- name: UserTrackCreate :one
+INSERT INTO user_tracks (user_id, track_id, saved_at)
+VALUES ($1, $2, $3)
+RETURNING id;
+
+-- name: UserTrackDeleteByUserTrack :exec
+UPDATE user_tracks
+SET deleted_at = NOW()
+WHERE user_id = $1 AND track_id = $2 AND deleted_at IS NULL;
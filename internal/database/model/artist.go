@@ -15,18 +15,21 @@ type Artist struct {
 	CoverID    string
 	CoverURL   string
 	UpdatedAt  time.Time
+	// ReleasesCheckedAt is the last time we looked for new releases
+	ReleasesCheckedAt time.Time
 }
 
 func ArtistModel(a sqlc.Artist) *Artist {
 	return &Artist{
-		ID:         int(a.ID),
-		SpotifyID:  a.SpotifyID,
-		Name:       fromString(a.Name),
-		Followers:  fromInt(a.Followers),
-		Popularity: fromInt(a.Popularity),
-		CoverID:    fromString(a.CoverID),
-		CoverURL:   fromString(a.CoverUrl),
-		UpdatedAt:  fromTime(a.UpdatedAt),
+		ID:                int(a.ID),
+		SpotifyID:         a.SpotifyID,
+		Name:              fromString(a.Name),
+		Followers:         fromInt(a.Followers),
+		Popularity:        fromInt(a.Popularity),
+		CoverID:           fromString(a.CoverID),
+		CoverURL:          fromString(a.CoverUrl),
+		UpdatedAt:         fromTime(a.UpdatedAt),
+		ReleasesCheckedAt: fromTime(a.ReleasesCheckedAt),
 	}
 }
 
@@ -37,3 +40,40 @@ func (a *Artist) Equal(a2 Artist) bool {
 func (a *Artist) EqualEntry(a2 Artist) bool {
 	return a.Name == a2.Name && a.Followers == a2.Followers && a.Popularity == a2.Popularity && a.CoverURL == a2.CoverURL
 }
+
+type ArtistUser struct {
+	ID        int
+	UserID    int
+	ArtistID  int
+	DeletedAt time.Time
+}
+
+type ArtistRelease struct {
+	ID          int
+	ArtistID    int
+	AlbumID     int
+	AlbumType   string
+	ReleaseDate time.Time
+	CreatedAt   time.Time
+
+	// Non db fields
+	Artist Artist
+	Album  Album
+}
+
+func ArtistReleaseModel(a sqlc.ArtistRelease) *ArtistRelease {
+	return &ArtistRelease{
+		ID:          int(a.ID),
+		ArtistID:    int(a.ArtistID),
+		AlbumID:     int(a.AlbumID),
+		AlbumType:   a.AlbumType,
+		ReleaseDate: a.ReleaseDate.Time,
+		CreatedAt:   a.CreatedAt.Time,
+	}
+}
+
+type ArtistReleaseFilter struct {
+	UserID int
+	Limit  int
+	Offset int
+}
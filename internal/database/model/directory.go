@@ -1,17 +1,40 @@
 package model
 
 import (
+	"encoding/json"
 	"slices"
 
 	"github.com/topvennie/sortifyr/pkg/sqlc"
 	"github.com/topvennie/sortifyr/pkg/utils"
 )
 
+// We need json tags because the rules are saved as jsonb
+
+// DirectoryRules turns a directory into a smart directory
+// A playlist is part of the directory when it matches every rule
+// A zero value field is ignored
+type DirectoryRules struct {
+	Owned          *bool  `json:"owned,omitempty"` // Owned by the user or not
+	NameRegex      string `json:"name_regex,omitzero"`
+	Collaborative  *bool  `json:"collaborative,omitempty"`
+	Public         *bool  `json:"public,omitempty"`
+	TrackAmountMin int    `json:"track_amount_min,omitzero"`
+	TrackAmountMax int    `json:"track_amount_max,omitzero"`
+	ArtistID       int    `json:"artist_id,omitzero"`       // Contains a track of the artist
+	NotPlayedDays  int    `json:"not_played_days,omitzero"` // Not played as context in the last n days
+}
+
+func (r *DirectoryRules) Equal(r2 DirectoryRules) bool {
+	return equalBool(r.Owned, r2.Owned) && r.NameRegex == r2.NameRegex && equalBool(r.Collaborative, r2.Collaborative) && equalBool(r.Public, r2.Public) &&
+		r.TrackAmountMin == r2.TrackAmountMin && r.TrackAmountMax == r2.TrackAmountMax && r.ArtistID == r2.ArtistID && r.NotPlayedDays == r2.NotPlayedDays
+}
+
 type Directory struct {
 	ID       int
 	UserID   int
 	Name     string
 	ParentID int
+	Rules    *DirectoryRules // Nil for a manual directory
 
 	// Non db fields
 	Playlists []Playlist
@@ -23,20 +46,36 @@ func DirectoryModel(d sqlc.Directory) *Directory {
 		parentID = int(d.ParentID.Int32)
 	}
 
+	var rules *DirectoryRules
+	if d.Rules != nil {
+		rules = &DirectoryRules{}
+		_ = json.Unmarshal(d.Rules, rules) // nolint:errcheck // Data controlled by us
+	}
+
 	return &Directory{
 		ID:       int(d.ID),
 		UserID:   int(d.UserID),
 		Name:     d.Name,
 		ParentID: parentID,
+		Rules:    rules,
 	}
 }
 
+// IsSmart returns true if the playlists are determined by rules
+func (d *Directory) IsSmart() bool {
+	return d.Rules != nil
+}
+
 func (d *Directory) Equal(d2 Directory) bool {
 	values := d.UserID == d2.UserID && d.Name == d2.Name && d.ParentID == d2.ParentID
 	if !values {
 		return false
 	}
 
+	if d.IsSmart() != d2.IsSmart() || (d.IsSmart() && !d.Rules.Equal(*d2.Rules)) {
+		return false
+	}
+
 	p := utils.SliceMap(d.Playlists, func(p Playlist) int { return p.ID })
 	p2 := utils.SliceMap(d2.Playlists, func(p Playlist) int { return p.ID })
 
@@ -46,6 +85,33 @@ func (d *Directory) Equal(d2 Directory) bool {
 	return slices.Equal(p, p2)
 }
 
+// DirectoryPlaylistsRecursive returns the playlists of a directory and of all its subdirectories
+func DirectoryPlaylistsRecursive(directories []*Directory, directoryID int) []Playlist {
+	playlists := make([]Playlist, 0)
+	visited := make(map[int]bool)
+
+	var expand func(id int)
+	expand = func(id int) {
+		if visited[id] {
+			return
+		}
+		visited[id] = true
+
+		for _, d := range directories {
+			if d.ID == id {
+				playlists = append(playlists, d.Playlists...)
+			}
+			if d.ParentID == id {
+				expand(d.ID)
+			}
+		}
+	}
+
+	expand(directoryID)
+
+	return utils.SliceUniqueFunc(playlists, func(p Playlist) int { return p.ID })
+}
+
 type DirectoryPlaylist struct {
 	ID          int
 	DirectoryID int
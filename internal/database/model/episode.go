@@ -0,0 +1,83 @@
+package model
+
+import (
+	"time"
+
+	"github.com/topvennie/sortifyr/pkg/sqlc"
+)
+
+type Episode struct {
+	ID         int
+	SpotifyID  string
+	ShowID     int
+	Name       string
+	DurationMs int
+	UpdatedAt  time.Time
+
+	// Non db fields
+	Show Show
+}
+
+func EpisodeModel(e sqlc.Episode) *Episode {
+	return &Episode{
+		ID:         int(e.ID),
+		SpotifyID:  e.SpotifyID,
+		ShowID:     int(e.ShowID),
+		Name:       fromString(e.Name),
+		DurationMs: fromInt(e.DurationMs),
+		UpdatedAt:  fromTime(e.UpdatedAt),
+	}
+}
+
+func (e *Episode) Equal(e2 Episode) bool {
+	return e.SpotifyID == e2.SpotifyID
+}
+
+type EpisodeHistory struct {
+	ID         int
+	UserID     int
+	EpisodeID  int
+	PlayedAt   time.Time
+	PositionMs int
+	UpdatedAt  time.Time
+	Source     HistorySource
+
+	// Only known for imported entries
+	ReasonStart string
+	ReasonEnd   string
+	Shuffle     *bool
+	Offline     *bool
+	Platform    string
+	ConnCountry string
+
+	// Non db fields
+	Episode Episode
+}
+
+func EpisodeHistoryModel(e sqlc.EpisodeHistory) *EpisodeHistory {
+	return &EpisodeHistory{
+		ID:         int(e.ID),
+		UserID:     int(e.UserID),
+		EpisodeID:  int(e.EpisodeID),
+		PlayedAt:   e.PlayedAt.Time,
+		PositionMs: int(e.PositionMs),
+		UpdatedAt:  e.UpdatedAt.Time,
+		Source:     HistorySource(e.Source),
+
+		ReasonStart: fromString(e.ReasonStart),
+		ReasonEnd:   fromString(e.ReasonEnd),
+		Shuffle:     fromBool(e.Shuffle),
+		Offline:     fromBool(e.Offline),
+		Platform:    fromString(e.Platform),
+		ConnCountry: fromString(e.ConnCountry),
+	}
+}
+
+type EpisodeHistoryFilter struct {
+	UserID int
+	ShowID int
+	Limit  int
+	Offset int
+	Start  time.Time
+	End    time.Time
+}
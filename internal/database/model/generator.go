@@ -34,9 +34,11 @@ type GeneratorPresetOldTopParams struct {
 }
 
 type GeneratorParams struct {
-	TrackAmount         int   `json:"track_amount"`
-	ExcludedPlaylistIDs []int `json:"excluded_playlist_ids"`
-	ExcludedTrackIDs    []int `json:"excluded_track_ids"`
+	TrackAmount          int   `json:"track_amount"`
+	ExcludedPlaylistIDs  []int `json:"excluded_playlist_ids"`
+	ExcludedDirectoryIDs []int `json:"excluded_directory_ids"` // Including subdirectories
+	ExcludedTrackIDs     []int `json:"excluded_track_ids"`
+	ExcludeLiked         bool  `json:"exclude_liked"`
 
 	Preset GeneratorPreset `json:"preset"`
 
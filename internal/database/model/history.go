@@ -6,6 +6,16 @@ import (
 	"github.com/topvennie/sortifyr/pkg/sqlc"
 )
 
+type HistorySource string
+
+const (
+	HistorySourcePoll           HistorySource = "poll"            // Currently playing endpoint
+	HistorySourceExport         HistorySource = "export"          // Imported from an export
+	HistorySourceRecentlyPlayed HistorySource = "recently_played" // Missed by the poll, found in the recently played endpoint
+	HistorySourceLastFM         HistorySource = "lastfm"          // Imported from a Last.fm scrobble export
+	HistorySourceListenBrainz   HistorySource = "listenbrainz"    // Imported from a ListenBrainz dump
+)
+
 type History struct {
 	ID         int
 	UserID     int
@@ -16,6 +26,16 @@ type History struct {
 	ArtistID   int
 	PlaylistID int
 	ShowID     int
+	SessionID  int // 0 until the session task ran
+	Source     HistorySource
+
+	// Only known for imported entries
+	ReasonStart string
+	ReasonEnd   string
+	Shuffle     *bool
+	Offline     *bool
+	Platform    string
+	ConnCountry string
 
 	// Non db fields
 	Track     Track
@@ -33,6 +53,15 @@ func HistoryModel(h sqlc.History) *History {
 		ArtistID:   fromInt(h.ArtistID),
 		PlaylistID: fromInt(h.PlaylistID),
 		ShowID:     fromInt(h.ShowID),
+		SessionID:  fromInt(h.SessionID),
+		Source:     HistorySource(h.Source),
+
+		ReasonStart: fromString(h.ReasonStart),
+		ReasonEnd:   fromString(h.ReasonEnd),
+		Shuffle:     fromBool(h.Shuffle),
+		Offline:     fromBool(h.Offline),
+		Platform:    fromString(h.Platform),
+		ConnCountry: fromString(h.ConnCountry),
 	}
 }
 
@@ -44,4 +73,5 @@ type HistoryFilter struct {
 	End              time.Time
 	Skipped          *bool
 	PlayCountSkipped *bool
+	SessionID        int
 }
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"github.com/topvennie/sortifyr/pkg/sqlc"
+)
+
+// HistorySession is a group of consecutive plays
+type HistorySession struct {
+	ID      int
+	UserID  int
+	StartAt time.Time
+	EndAt   time.Time
+
+	// Most played context, at most one is set
+	PlaylistID int
+	AlbumID    int
+	ArtistID   int
+	ShowID     int
+
+	// Non db fields
+	Plays      int
+	Skips      int
+	SkipsKnown int   // Plays of which we know if they were skipped
+	HistoryIDs []int // Plays that belong to the session
+}
+
+func HistorySessionModel(s sqlc.HistorySession) *HistorySession {
+	return &HistorySession{
+		ID:         int(s.ID),
+		UserID:     int(s.UserID),
+		StartAt:    s.StartAt.Time,
+		EndAt:      s.EndAt.Time,
+		PlaylistID: fromInt(s.PlaylistID),
+		AlbumID:    fromInt(s.AlbumID),
+		ArtistID:   fromInt(s.ArtistID),
+		ShowID:     fromInt(s.ShowID),
+	}
+}
+
+type HistorySessionFilter struct {
+	UserID int
+	Start  time.Time
+	End    time.Time
+	Limit  int
+	Offset int
+}
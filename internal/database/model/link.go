@@ -1,13 +1,63 @@
 package model
 
-import "github.com/topvennie/sortifyr/pkg/sqlc"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/topvennie/sortifyr/pkg/sqlc"
+	"github.com/topvennie/sortifyr/pkg/utils"
+)
+
+type LinkMode string
+
+const (
+	LinkAppend        LinkMode = "append"        // Add source tracks missing in the target
+	LinkMirror        LinkMode = "mirror"        // Target equals the union of its sources
+	LinkBidirectional LinkMode = "bidirectional" // Additions and removals are synced both ways
+)
+
+// We need json tags because the filter is saved as jsonb
+
+// LinkFilter limits which source tracks are synced to the target
+// A zero value field is ignored
+type LinkFilter struct {
+	AddedAfter        time.Time `json:"added_after,omitzero"` // Added to the source after
+	ArtistIDs         []int     `json:"artist_ids,omitempty"` // Any of the track artists
+	ExcludeExplicit   bool      `json:"exclude_explicit,omitzero"`
+	ExcludeUnplayable bool      `json:"exclude_unplayable,omitzero"`
+	MinPlays          int       `json:"min_plays,omitzero"` // Plays in the user's history
+}
+
+func (f *LinkFilter) Equal(f2 LinkFilter) bool {
+	return f.AddedAfter.Equal(f2.AddedAfter) && slices.Equal(f.ArtistIDs, f2.ArtistIDs) && f.ExcludeExplicit == f2.ExcludeExplicit && f.ExcludeUnplayable == f2.ExcludeUnplayable && f.MinPlays == f2.MinPlays
+}
+
+func (f *LinkFilter) IsZero() bool {
+	return f.Equal(LinkFilter{})
+}
 
 type Link struct {
 	ID                int
 	SourceDirectoryID int
 	SourcePlaylistID  int
+	SourceUserID      int // The user's liked songs
 	TargetDirectoryID int
 	TargetPlaylistID  int
+	Mode              LinkMode
+	Filter            LinkFilter
+	// Use the playlists of all subdirectories as well
+	IncludeSubdirectories bool
+
+	// Last synchronization
+	LastRunAt      time.Time
+	LastRunAdded   int
+	LastRunDeleted int
+	LastRunError   string
+	// Start of the last successful synchronization
+	SyncedAt time.Time
 }
 
 func LinkModel(l sqlc.Link) *Link {
@@ -19,6 +69,10 @@ func LinkModel(l sqlc.Link) *Link {
 	if l.SourcePlaylistID.Valid {
 		sourcePlaylistID = int(l.SourcePlaylistID.Int32)
 	}
+	sourceUserID := 0
+	if l.SourceUserID.Valid {
+		sourceUserID = int(l.SourceUserID.Int32)
+	}
 	targetDirectoryID := 0
 	if l.TargetDirectoryID.Valid {
 		targetDirectoryID = int(l.TargetDirectoryID.Int32)
@@ -28,15 +82,80 @@ func LinkModel(l sqlc.Link) *Link {
 		targetPlaylistID = int(l.TargetPlaylistID.Int32)
 	}
 
+	filter := LinkFilter{}
+	_ = json.Unmarshal(l.Filter, &filter) // nolint:errcheck // Data controlled by us
+
 	return &Link{
-		ID:                int(l.ID),
-		SourceDirectoryID: sourceDirectoryID,
-		SourcePlaylistID:  sourcePlaylistID,
-		TargetDirectoryID: targetDirectoryID,
-		TargetPlaylistID:  targetPlaylistID,
+		ID:                    int(l.ID),
+		SourceDirectoryID:     sourceDirectoryID,
+		SourcePlaylistID:      sourcePlaylistID,
+		SourceUserID:          sourceUserID,
+		TargetDirectoryID:     targetDirectoryID,
+		TargetPlaylistID:      targetPlaylistID,
+		Mode:                  LinkMode(l.Mode),
+		Filter:                filter,
+		IncludeSubdirectories: l.IncludeSubdirectories,
+		LastRunAt:             fromTime(l.LastRunAt),
+		LastRunAdded:          int(l.LastRunAdded),
+		LastRunDeleted:        int(l.LastRunDeleted),
+		LastRunError:          fromString(l.LastRunError),
+		SyncedAt:              fromTime(l.SyncedAt),
 	}
 }
 
 func (l *Link) Equal(l2 Link) bool {
-	return l.SourceDirectoryID == l2.SourceDirectoryID && l.SourcePlaylistID == l2.SourcePlaylistID && l.TargetDirectoryID == l2.TargetDirectoryID && l.TargetPlaylistID == l2.TargetDirectoryID
+	return l.SourceDirectoryID == l2.SourceDirectoryID && l.SourcePlaylistID == l2.SourcePlaylistID && l.SourceUserID == l2.SourceUserID && l.TargetDirectoryID == l2.TargetDirectoryID && l.TargetPlaylistID == l2.TargetPlaylistID && l.Mode == l2.Mode && l.Filter.Equal(l2.Filter) && l.IncludeSubdirectories == l2.IncludeSubdirectories
+}
+
+// LinkResolve returns the source and target playlists of a link
+// Directories are expanded recursively if the link includes subdirectories.
+// A link with the liked songs as source has no source playlists.
+func LinkResolve(link Link, directories []*Directory, playlists []*Playlist) ([]Playlist, []Playlist, error) {
+	resolve := func(directoryID, playlistID int) ([]Playlist, error) {
+		switch {
+		case directoryID != 0:
+			directory, ok := utils.SliceFind(directories, func(d *Directory) bool { return d.ID == directoryID })
+			if !ok {
+				return nil, fmt.Errorf("directory %d not found", directoryID)
+			}
+			if link.IncludeSubdirectories {
+				return DirectoryPlaylistsRecursive(directories, directoryID), nil
+			}
+			return append([]Playlist{}, (*directory).Playlists...), nil
+
+		case playlistID != 0:
+			playlist, ok := utils.SliceFind(playlists, func(p *Playlist) bool { return p.ID == playlistID })
+			if !ok {
+				return nil, fmt.Errorf("playlist %d not found", playlistID)
+			}
+			return []Playlist{**playlist}, nil
+		}
+
+		return nil, nil
+	}
+
+	sources, err := resolve(link.SourceDirectoryID, link.SourcePlaylistID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sources == nil && link.SourceUserID == 0 {
+		return nil, nil, errors.New("link has no source")
+	}
+
+	targets, err := resolve(link.TargetDirectoryID, link.TargetPlaylistID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if targets == nil {
+		return nil, nil, errors.New("link has no target")
+	}
+
+	return sources, targets, nil
+}
+
+// LinkChange contains the tracks a link adds to and deletes from a playlist
+type LinkChange struct {
+	Playlist      Playlist
+	TracksAdded   []Track
+	TracksDeleted []Track
 }
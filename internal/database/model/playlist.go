@@ -6,6 +6,14 @@ import (
 	"github.com/topvennie/sortifyr/pkg/sqlc"
 )
 
+type PlaylistSplitKey string
+
+const (
+	PlaylistSplitArtist PlaylistSplitKey = "artist" // First artist of each track
+	PlaylistSplitYear   PlaylistSplitKey = "year"   // Release year of the album
+	PlaylistSplitDecade PlaylistSplitKey = "decade"
+)
+
 type Playlist struct {
 	ID            int
 	SpotifyID     string
@@ -26,6 +34,11 @@ type Playlist struct {
 	Unplayables []Track
 }
 
+// Editable returns true if the user can change the tracks of the playlist
+func (p *Playlist) Editable(userID int) bool {
+	return p.OwnerID == userID || (p.Collaborative != nil && *p.Collaborative)
+}
+
 func PlaylistModel(p sqlc.Playlist) *Playlist {
 	return &Playlist{
 		ID:            int(p.ID),
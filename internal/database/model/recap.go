@@ -0,0 +1,64 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/topvennie/sortifyr/pkg/sqlc"
+)
+
+// We need json tags because the recap data is saved as jsonb
+
+// RecapItem is a snapshot of a track, artist or album at the time the recap was made
+type RecapItem struct {
+	ID        int    `json:"id"`
+	SpotifyID string `json:"spotify_id"`
+	Name      string `json:"name"`
+	Plays     int    `json:"plays"`
+}
+
+type RecapData struct {
+	TopTracks     []RecapItem   `json:"top_tracks"`
+	TopArtists    []RecapItem   `json:"top_artists"`
+	TopAlbums     []RecapItem   `json:"top_albums"`
+	Duration      time.Duration `json:"duration"`
+	LongestStreak int           `json:"longest_streak"` // Consecutive days with at least one play
+	TopHour       int           `json:"top_hour"`
+	TopWeekday    time.Weekday  `json:"top_weekday"`
+	Discovery     *RecapItem    `json:"discovery,omitempty"` // Most played track that was never played before the period
+	MostSkipped   *RecapItem    `json:"most_skipped,omitempty"`
+}
+
+type Recap struct {
+	ID        int
+	UserID    int
+	Year      int
+	Month     int // 0 for a yearly recap
+	Data      RecapData
+	CreatedAt time.Time
+}
+
+func RecapModel(r sqlc.Recap) *Recap {
+	data := RecapData{}
+	_ = json.Unmarshal(r.Data, &data) // nolint:errcheck // Data controlled by us
+
+	return &Recap{
+		ID:        int(r.ID),
+		UserID:    int(r.UserID),
+		Year:      int(r.Year),
+		Month:     int(r.Month),
+		Data:      data,
+		CreatedAt: fromTime(r.CreatedAt),
+	}
+}
+
+// Period returns the start (inclusive) and end (exclusive) of the recap in the given timezone
+func (r *Recap) Period(loc *time.Location) (time.Time, time.Time) {
+	if r.Month == 0 {
+		start := time.Date(r.Year, time.January, 1, 0, 0, 0, 0, loc)
+		return start, start.AddDate(1, 0, 0)
+	}
+
+	start := time.Date(r.Year, time.Month(r.Month), 1, 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 1, 0)
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"time"
+
+	"github.com/topvennie/sortifyr/pkg/sqlc"
+)
+
+type StatFilter struct {
+	UserID int
+	Start  time.Time // Zero value for no start
+	End    time.Time // Zero value for no end
+	Limit  int
+}
+
+// StatRollupFilter filters the daily rollup
+// Dates are in the user's timezone, a zero value field is ignored
+type StatRollupFilter struct {
+	UserID     int
+	Start      time.Time
+	End        time.Time
+	TrackID    int
+	PlaylistID int // Played as context
+	ArtistID   int
+}
+
+type StatHeatmap struct {
+	Weekday time.Weekday
+	Hour    int
+	Plays   int
+}
+
+type StatDaily struct {
+	Day   time.Time
+	Plays int
+}
+
+type StatSummary struct {
+	Plays          int
+	Duration       time.Duration // Skipped plays are not included
+	DistinctTracks int
+	Skips          int
+	SkipsKnown     int // Plays for which we know if they were skipped
+}
+
+func StatSummaryModel(s sqlc.StatGetSummaryRow) *StatSummary {
+	return &StatSummary{
+		Plays:          int(s.Plays),
+		Duration:       time.Duration(s.DurationMs) * time.Millisecond,
+		DistinctTracks: int(s.DistinctTracks),
+		Skips:          int(s.Skips),
+		SkipsKnown:     int(s.SkipsKnown),
+	}
+}
+
+// SkipRate returns the fraction of plays that were skipped
+func (s *StatSummary) SkipRate() float64 {
+	if s.SkipsKnown == 0 {
+		return 0
+	}
+
+	return float64(s.Skips) / float64(s.SkipsKnown)
+}
+
+type StatTrack struct {
+	Track Track
+	Plays int
+}
+
+type StatArtist struct {
+	Artist Artist
+	Plays  int
+}
+
+type StatAlbum struct {
+	Album Album
+	Plays int
+}
+
+type StatPlaylist struct {
+	Playlist Playlist
+	Plays    int
+}
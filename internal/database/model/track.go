@@ -14,6 +14,8 @@ type Track struct {
 	Name       string    `json:"name"`
 	Popularity int       `json:"popularity"`
 	DurationMs int       `json:"duration_ms"`
+	Explicit   *bool     `json:"explicit"`
+	AlbumID    int       `json:"album_id"`
 	UpdatedAt  time.Time `json:"updated_at"`
 
 	// Non db fields
@@ -30,6 +32,8 @@ func TrackModel(t sqlc.Track) *Track {
 		Name:       fromString(t.Name),
 		Popularity: fromInt(t.Popularity),
 		DurationMs: fromInt(t.DurationMs),
+		Explicit:   fromBool(t.Explicit),
+		AlbumID:    fromInt(t.AlbumID),
 		UpdatedAt:  fromTime(t.UpdatedAt),
 	}
 }
@@ -39,7 +43,7 @@ func (t *Track) Equal(t2 Track) bool {
 }
 
 func (t *Track) EqualEntry(t2 Track) bool {
-	return t.Name == t2.Name && t.Popularity == t2.Popularity && t.DurationMs == t2.DurationMs
+	return t.Name == t2.Name && t.Popularity == t2.Popularity && t.DurationMs == t2.DurationMs && equalBool(t.Explicit, t2.Explicit) && t.AlbumID == t2.AlbumID
 }
 
 type TrackArtist struct {
@@ -48,9 +52,34 @@ type TrackArtist struct {
 	ArtistID int
 }
 
+func TrackArtistModel(t sqlc.TrackArtist) *TrackArtist {
+	return &TrackArtist{
+		ID:       int(t.ID),
+		TrackID:  int(t.TrackID),
+		ArtistID: int(t.ArtistID),
+	}
+}
+
+type UserTrack struct {
+	ID        int
+	UserID    int
+	TrackID   int
+	SavedAt   time.Time
+	DeletedAt time.Time
+}
+
 type TrackFilter struct {
 	UserID     int
 	PlaylistID int
+	Liked      bool
 	Limit      int
 	Offset     int
 }
+
+// TrackMatch describes a track by its metadata
+// Used to find a track without knowing its spotify id
+type TrackMatch struct {
+	Artist     string
+	Name       string
+	DurationMs int // 0 if unknown
+}
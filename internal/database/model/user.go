@@ -1,7 +1,11 @@
 // Package model contains all databank models
 package model
 
-import "github.com/topvennie/sortifyr/pkg/sqlc"
+import (
+	"time"
+
+	"github.com/topvennie/sortifyr/pkg/sqlc"
+)
 
 type User struct {
 	ID          int
@@ -9,6 +13,10 @@ type User struct {
 	Name        string
 	DisplayName string
 	Email       string
+	Timezone    string // IANA name, used for time of day statistics
+
+	// RecentlyPlayedAfter is the cursor used for the recently played endpoint
+	RecentlyPlayedAfter time.Time
 }
 
 func UserModel(user sqlc.User) *User {
@@ -18,12 +26,24 @@ func UserModel(user sqlc.User) *User {
 	}
 
 	return &User{
-		ID:          int(user.ID),
-		UID:         user.Uid,
-		Name:        user.Name,
-		DisplayName: displayName,
-		Email:       user.Email,
+		ID:                  int(user.ID),
+		UID:                 user.Uid,
+		Name:                user.Name,
+		DisplayName:         displayName,
+		Email:               user.Email,
+		Timezone:            user.Timezone,
+		RecentlyPlayedAfter: fromTime(user.RecentlyPlayedAfter),
+	}
+}
+
+// Location returns the timezone of the user, UTC if it's unknown
+func (u *User) Location() *time.Location {
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
 	}
+
+	return loc
 }
 
 // Equal returns true if all non unique values are equal
@@ -30,6 +30,14 @@ func fromBool(b pgtype.Bool) *bool {
 	return nil
 }
 
+func equalBool(b1, b2 *bool) bool {
+	if b1 == nil || b2 == nil {
+		return b1 == b2
+	}
+
+	return *b1 == *b2
+}
+
 func fromTime(t pgtype.Timestamptz) time.Time {
 	if t.Valid {
 		return t.Time
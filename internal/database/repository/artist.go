@@ -45,6 +45,57 @@ func (a *Artist) GetBySpotify(ctx context.Context, spotifyID string) (*model.Art
 	return model.ArtistModel(artist), nil
 }
 
+func (a *Artist) GetByUser(ctx context.Context, userID int) ([]*model.Artist, error) {
+	artists, err := a.repo.queries(ctx).ArtistGetByUser(ctx, int32(userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get artists by user %d | %w", userID, err)
+	}
+
+	return utils.SliceMap(artists, model.ArtistModel), nil
+}
+
+func (a *Artist) GetReleaseByArtistPopulated(ctx context.Context, artistID int) ([]*model.ArtistRelease, error) {
+	releases, err := a.repo.queries(ctx).ArtistReleaseGetByArtistPopulated(ctx, int32(artistID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get populated artist releases by artist %d | %w", artistID, err)
+	}
+
+	return utils.SliceMap(releases, func(r sqlc.ArtistReleaseGetByArtistPopulatedRow) *model.ArtistRelease {
+		release := model.ArtistReleaseModel(r.ArtistRelease)
+		release.Album = *model.AlbumModel(r.Album)
+
+		return release
+	}), nil
+}
+
+func (a *Artist) GetReleaseByUserPopulated(ctx context.Context, filter model.ArtistReleaseFilter) ([]*model.ArtistRelease, error) {
+	releases, err := a.repo.queries(ctx).ArtistReleaseGetByUserPopulated(ctx, sqlc.ArtistReleaseGetByUserPopulatedParams{
+		UserID: int32(filter.UserID),
+		Limit:  int32(filter.Limit),
+		Offset: int32(filter.Offset),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get populated artist releases by user %+v | %w", filter, err)
+	}
+
+	return utils.SliceMap(releases, func(r sqlc.ArtistReleaseGetByUserPopulatedRow) *model.ArtistRelease {
+		release := model.ArtistReleaseModel(r.ArtistRelease)
+		release.Artist = *model.ArtistModel(r.Artist)
+		release.Album = *model.AlbumModel(r.Album)
+
+		return release
+	}), nil
+}
+
 func (a *Artist) GetByAlbum(ctx context.Context, albumID int) ([]*model.Artist, error) {
 	artists, err := a.repo.queries(ctx).ArtistGetByAlbum(ctx, int32(albumID))
 	if err != nil {
@@ -87,6 +138,36 @@ func (a *Artist) Create(ctx context.Context, artist *model.Artist) error {
 	return nil
 }
 
+func (a *Artist) CreateUser(ctx context.Context, user *model.ArtistUser) error {
+	id, err := a.repo.queries(ctx).ArtistUserCreate(ctx, sqlc.ArtistUserCreateParams{
+		UserID:   int32(user.UserID),
+		ArtistID: int32(user.ArtistID),
+	})
+	if err != nil {
+		return fmt.Errorf("create artist user %+v | %w", *user, err)
+	}
+
+	user.ID = int(id)
+
+	return nil
+}
+
+func (a *Artist) CreateRelease(ctx context.Context, release *model.ArtistRelease) error {
+	id, err := a.repo.queries(ctx).ArtistReleaseCreate(ctx, sqlc.ArtistReleaseCreateParams{
+		ArtistID:    int32(release.ArtistID),
+		AlbumID:     int32(release.AlbumID),
+		AlbumType:   release.AlbumType,
+		ReleaseDate: toDate(release.ReleaseDate),
+	})
+	if err != nil {
+		return fmt.Errorf("create artist release %+v | %w", *release, err)
+	}
+
+	release.ID = int(id)
+
+	return nil
+}
+
 func (a *Artist) Update(ctx context.Context, artist model.Artist) error {
 	if err := a.repo.queries(ctx).ArtistUpdate(ctx, sqlc.ArtistUpdateParams{
 		ID:         int32(artist.ID),
@@ -101,3 +182,22 @@ func (a *Artist) Update(ctx context.Context, artist model.Artist) error {
 
 	return nil
 }
+
+func (a *Artist) UpdateReleasesCheckedAt(ctx context.Context, artistID int) error {
+	if err := a.repo.queries(ctx).ArtistUpdateReleasesCheckedAt(ctx, int32(artistID)); err != nil {
+		return fmt.Errorf("update artist releases checked at %d | %w", artistID, err)
+	}
+
+	return nil
+}
+
+func (a *Artist) DeleteUserByUserArtist(ctx context.Context, user model.ArtistUser) error {
+	if err := a.repo.queries(ctx).ArtistUserDeleteByUserArtist(ctx, sqlc.ArtistUserDeleteByUserArtistParams{
+		UserID:   int32(user.UserID),
+		ArtistID: int32(user.ArtistID),
+	}); err != nil {
+		return fmt.Errorf("delete artist user %+v | %w", user, err)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -87,11 +88,17 @@ func (d *Directory) GetByUserPopulated(ctx context.Context, userID int) ([]*mode
 }
 
 func (d *Directory) Create(ctx context.Context, directory *model.Directory) error {
+	rules, err := directoryRules(directory.Rules)
+	if err != nil {
+		return err
+	}
+
 	return d.repo.WithRollback(ctx, func(ctx context.Context) error {
 		id, err := d.repo.queries(ctx).DirectoryCreate(ctx, sqlc.DirectoryCreateParams{
 			UserID:   int32(directory.UserID),
 			Name:     directory.Name,
 			ParentID: pgtype.Int4{Int32: int32(directory.ParentID), Valid: directory.ParentID != 0},
+			Rules:    rules,
 		})
 		if err != nil {
 			return fmt.Errorf("create directory %+v | %w", *directory, err)
@@ -127,10 +134,16 @@ func (d *Directory) CreatePlaylist(ctx context.Context, directory *model.Directo
 }
 
 func (d *Directory) Update(ctx context.Context, directory model.Directory) error {
+	rules, err := directoryRules(directory.Rules)
+	if err != nil {
+		return err
+	}
+
 	if err := d.repo.queries(ctx).DirectoryUpdate(ctx, sqlc.DirectoryUpdateParams{
 		ID:       int32(directory.ID),
 		Name:     directory.Name,
 		ParentID: pgtype.Int4{Int32: int32(directory.ParentID), Valid: directory.ParentID != 0},
+		Rules:    rules,
 	}); err != nil {
 		return fmt.Errorf("update directory %+v | %w", directory, err)
 	}
@@ -138,12 +151,48 @@ func (d *Directory) Update(ctx context.Context, directory model.Directory) error
 	return nil
 }
 
+func (d *Directory) Delete(ctx context.Context, directoryID int) error {
+	if err := d.repo.queries(ctx).DirectoryDelete(ctx, int32(directoryID)); err != nil {
+		return fmt.Errorf("delete directory %d | %w", directoryID, err)
+	}
+
+	// Subdirectories and directory playlists are deleted by cascade
+
+	return nil
+}
+
 func (d *Directory) DeleteByUser(ctx context.Context, userID int) error {
 	if err := d.repo.queries(ctx).DirectoryDeleteByUser(ctx, int32(userID)); err != nil {
 		return fmt.Errorf("delete directories by user %d | %w", userID, err)
 	}
 
-	// Directory playlists are deleted by cascade
+	// Subdirectories, directory playlists and their links are deleted by cascade
 
 	return nil
 }
+
+func (d *Directory) DeletePlaylistByDirectoryPlaylist(ctx context.Context, directory model.DirectoryPlaylist) error {
+	if err := d.repo.queries(ctx).DirectoryPlaylistDeleteByDirectoryPlaylist(ctx, sqlc.DirectoryPlaylistDeleteByDirectoryPlaylistParams{
+		DirectoryID: int32(directory.DirectoryID),
+		PlaylistID:  int32(directory.PlaylistID),
+	}); err != nil {
+		return fmt.Errorf("delete directory playlist %+v | %w", directory, err)
+	}
+
+	return nil
+}
+
+// directoryRules marshals the rules
+// A manual directory is saved as NULL
+func directoryRules(rules *model.DirectoryRules) ([]byte, error) {
+	if rules == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("marshal directory rules %+v | %w", *rules, err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/topvennie/sortifyr/internal/database/model"
+	"github.com/topvennie/sortifyr/pkg/sqlc"
+	"github.com/topvennie/sortifyr/pkg/utils"
+)
+
+type Episode struct {
+	repo Repository
+}
+
+func (r *Repository) NewEpisode() *Episode {
+	return &Episode{
+		repo: *r,
+	}
+}
+
+func (e *Episode) GetBySpotify(ctx context.Context, spotifyID string) (*model.Episode, error) {
+	episode, err := e.repo.queries(ctx).EpisodeGetBySpotify(ctx, spotifyID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get episode by spotify %s | %w", spotifyID, err)
+	}
+
+	return model.EpisodeModel(episode), nil
+}
+
+func (e *Episode) GetAllBySpotify(ctx context.Context, spotifyIDs []string) ([]*model.Episode, error) {
+	episodes, err := e.repo.queries(ctx).EpisodeGetAllBySpotify(ctx, spotifyIDs)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get all episodes by spotify id %w", err)
+	}
+
+	return utils.SliceMap(episodes, model.EpisodeModel), nil
+}
+
+func (e *Episode) GetHistoryLatestPopulated(ctx context.Context, userID int) (*model.EpisodeHistory, error) {
+	latest, err := e.repo.queries(ctx).EpisodeHistoryGetLatestPopulated(ctx, int32(userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get latest populated episode history %d | %w", userID, err)
+	}
+
+	history := model.EpisodeHistoryModel(latest.EpisodeHistory)
+	history.Episode = *model.EpisodeModel(latest.Episode)
+
+	return history, nil
+}
+
+func (e *Episode) GetHistoryPopulatedFilteredPaginated(ctx context.Context, filter model.EpisodeHistoryFilter) ([]*model.EpisodeHistory, error) {
+	histories, err := e.repo.queries(ctx).EpisodeHistoryGetPopulatedFilteredPaginated(ctx, sqlc.EpisodeHistoryGetPopulatedFilteredPaginatedParams{
+		Column1:      int32(filter.UserID),
+		Limit:        int32(filter.Limit),
+		Offset:       int32(filter.Offset),
+		Column4:      int32(filter.ShowID),
+		FilterShowID: filter.ShowID != 0,
+		Column5:      toTime(filter.Start),
+		FilterStart:  !filter.Start.IsZero(),
+		Column6:      toTime(filter.End),
+		FilterEnd:    !filter.End.IsZero(),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get filtered populated episode history %+v | %w", filter, err)
+	}
+
+	return utils.SliceMap(histories, func(h sqlc.EpisodeHistoryGetPopulatedFilteredPaginatedRow) *model.EpisodeHistory {
+		history := model.EpisodeHistoryModel(h.EpisodeHistory)
+		history.Episode = *model.EpisodeModel(h.Episode)
+		history.Episode.Show = *model.ShowModel(h.Show)
+
+		return history
+	}), nil
+}
+
+// GetHistoryPopulatedAfter returns the episode history of a user in insertion order, starting after the given id
+func (e *Episode) GetHistoryPopulatedAfter(ctx context.Context, userID, afterID, limit int) ([]*model.EpisodeHistory, error) {
+	histories, err := e.repo.queries(ctx).EpisodeHistoryGetPopulatedAfter(ctx, sqlc.EpisodeHistoryGetPopulatedAfterParams{
+		UserID: int32(userID),
+		ID:     int32(afterID),
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get populated episode history after %d | %d | %w", afterID, userID, err)
+	}
+
+	return utils.SliceMap(histories, func(h sqlc.EpisodeHistoryGetPopulatedAfterRow) *model.EpisodeHistory {
+		history := model.EpisodeHistoryModel(h.EpisodeHistory)
+		history.Episode = *model.EpisodeModel(h.Episode)
+		history.Episode.Show = *model.ShowModel(h.Show)
+
+		return history
+	}), nil
+}
+
+func (e *Episode) Create(ctx context.Context, episode *model.Episode) error {
+	id, err := e.repo.queries(ctx).EpisodeCreate(ctx, sqlc.EpisodeCreateParams{
+		SpotifyID:  episode.SpotifyID,
+		ShowID:     int32(episode.ShowID),
+		Name:       toString(episode.Name),
+		DurationMs: toInt(episode.DurationMs),
+	})
+	if err != nil {
+		return fmt.Errorf("create episode %+v | %w", *episode, err)
+	}
+
+	episode.ID = int(id)
+
+	return nil
+}
+
+// CountHistoryExisting returns how many of the given entries MergeHistoryBatch would consider already known
+// The entries are matched on the episode's spotify id so they don't need to exist yet.
+func (e *Episode) CountHistoryExisting(ctx context.Context, userID int, histories []model.EpisodeHistory, tolerance time.Duration) (int, error) {
+	count, err := e.repo.queries(ctx).EpisodeHistoryCountExisting(ctx, sqlc.EpisodeHistoryCountExistingParams{
+		Column1: int32(userID),
+		Column2: utils.SliceMap(histories, func(h model.EpisodeHistory) string { return h.Episode.SpotifyID }),
+		Column3: utils.SliceMap(histories, func(h model.EpisodeHistory) pgtype.Timestamptz { return toTime(h.PlayedAt) }),
+		Column4: int32(tolerance.Seconds()),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("count existing episode history for user %d | %w", userID, err)
+	}
+
+	return int(count), nil
+}
+
+func (e *Episode) CreateHistory(ctx context.Context, history *model.EpisodeHistory) error {
+	id, err := e.repo.queries(ctx).EpisodeHistoryCreate(ctx, sqlc.EpisodeHistoryCreateParams{
+		UserID:     int32(history.UserID),
+		EpisodeID:  int32(history.EpisodeID),
+		PlayedAt:   toTime(history.PlayedAt),
+		PositionMs: int32(history.PositionMs),
+		Source:     string(history.Source),
+	})
+	if err != nil {
+		return fmt.Errorf("create episode history %+v | %w", *history, err)
+	}
+
+	history.ID = int(id)
+
+	return nil
+}
+
+// MergeHistoryBatch merges a batch of episode history entries with the existing episode history
+// It follows the same logic as the history's MergeBatch and returns the amount of created entries.
+func (e *Episode) MergeHistoryBatch(ctx context.Context, histories []model.EpisodeHistory, source model.HistorySource, tolerance time.Duration) (int, error) {
+	userIDs := make([]int32, 0, len(histories))
+	episodeIDs := make([]int32, 0, len(histories))
+	playedAts := make([]pgtype.Timestamptz, 0, len(histories))
+	positionMss := make([]int32, 0, len(histories))
+	reasonStarts := make([]string, 0, len(histories))
+	reasonEnds := make([]string, 0, len(histories))
+	shuffles := make([]string, 0, len(histories))
+	offlines := make([]string, 0, len(histories))
+	platforms := make([]string, 0, len(histories))
+	connCountries := make([]string, 0, len(histories))
+
+	for i := range histories {
+		userIDs = append(userIDs, int32(histories[i].UserID))
+		episodeIDs = append(episodeIDs, int32(histories[i].EpisodeID))
+		playedAts = append(playedAts, toTime(histories[i].PlayedAt))
+		positionMss = append(positionMss, int32(histories[i].PositionMs))
+		reasonStarts = append(reasonStarts, histories[i].ReasonStart)
+		reasonEnds = append(reasonEnds, histories[i].ReasonEnd)
+		shuffles = append(shuffles, toBoolText(histories[i].Shuffle))
+		offlines = append(offlines, toBoolText(histories[i].Offline))
+		platforms = append(platforms, histories[i].Platform)
+		connCountries = append(connCountries, histories[i].ConnCountry)
+	}
+
+	var created int64
+
+	if err := e.repo.WithRollback(ctx, func(ctx context.Context) error {
+		if _, err := e.repo.queries(ctx).EpisodeHistoryMergeBatchUpdate(ctx, sqlc.EpisodeHistoryMergeBatchUpdateParams{
+			Column1:  userIDs,
+			Column2:  episodeIDs,
+			Column3:  playedAts,
+			Column4:  positionMss,
+			Column5:  reasonStarts,
+			Column6:  reasonEnds,
+			Column7:  shuffles,
+			Column8:  offlines,
+			Column9:  platforms,
+			Column10: connCountries,
+			Column11: int32(tolerance.Seconds()),
+		}); err != nil {
+			return fmt.Errorf("update existing episode history batch %w", err)
+		}
+
+		var err error
+		created, err = e.repo.queries(ctx).EpisodeHistoryMergeBatchCreate(ctx, sqlc.EpisodeHistoryMergeBatchCreateParams{
+			Column1:  userIDs,
+			Column2:  episodeIDs,
+			Column3:  playedAts,
+			Column4:  positionMss,
+			Column5:  reasonStarts,
+			Column6:  reasonEnds,
+			Column7:  shuffles,
+			Column8:  offlines,
+			Column9:  platforms,
+			Column10: connCountries,
+			Column11: int32(tolerance.Seconds()),
+			Column12: string(source),
+		})
+		if err != nil {
+			return fmt.Errorf("create new episode history batch %w", err)
+		}
+
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	return int(created), nil
+}
+
+func (e *Episode) UpdateHistory(ctx context.Context, history model.EpisodeHistory) error {
+	if err := e.repo.queries(ctx).EpisodeHistoryUpdate(ctx, sqlc.EpisodeHistoryUpdateParams{
+		ID:         int32(history.ID),
+		PositionMs: int32(history.PositionMs),
+	}); err != nil {
+		return fmt.Errorf("update episode history %+v | %w", history, err)
+	}
+
+	return nil
+}
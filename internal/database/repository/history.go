@@ -64,6 +64,8 @@ func (h *History) GetPopulatedFilteredPaginated(ctx context.Context, filter mode
 		FilterSkipped:   filter.Skipped != nil,
 		Column7:         playCountSkipped,
 		FilterPlayCount: filter.PlayCountSkipped != nil,
+		Column8:         int32(filter.SessionID),
+		FilterSession:   filter.SessionID != 0,
 	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -111,6 +113,65 @@ func (h *History) GetPopulatedFiltered(ctx context.Context, filter model.History
 	}), nil
 }
 
+// GetPopulatedAfter returns the history of a user in insertion order, starting after the given id
+// The artists of the track are populated with only their name
+func (h *History) GetPopulatedAfter(ctx context.Context, userID, afterID, limit int) ([]*model.History, error) {
+	histories, err := h.repo.queries(ctx).HistoryGetPopulatedAfter(ctx, sqlc.HistoryGetPopulatedAfterParams{
+		UserID: int32(userID),
+		ID:     int32(afterID),
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get populated history after %d | %d | %w", afterID, userID, err)
+	}
+
+	return utils.SliceMap(histories, func(h sqlc.HistoryGetPopulatedAfterRow) *model.History {
+		history := model.HistoryModel(h.History)
+		history.Track = *model.TrackModel(h.Track)
+		history.Track.Artists = utils.SliceMap(h.Artists, func(name string) model.Artist { return model.Artist{Name: name} })
+
+		return history
+	}), nil
+}
+
+// GetSessionUnknownFirst returns when the oldest play without a session was played
+// It returns a zero time if every play has a session
+func (h *History) GetSessionUnknownFirst(ctx context.Context, userID int) (time.Time, error) {
+	playedAt, err := h.repo.queries(ctx).HistoryGetSessionNullFirst(ctx, int32(userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("get first history without session %d | %w", userID, err)
+	}
+
+	return playedAt.Time, nil
+}
+
+// GetSessionRebuildPopulated returns the plays without a session or with a session that ends after the given time
+func (h *History) GetSessionRebuildPopulated(ctx context.Context, userID int, after time.Time) ([]*model.History, error) {
+	histories, err := h.repo.queries(ctx).HistoryGetSessionRebuildPopulated(ctx, sqlc.HistoryGetSessionRebuildPopulatedParams{
+		UserID: int32(userID),
+		EndAt:  toTime(after),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get history for session rebuild %s | %d | %w", after, userID, err)
+	}
+
+	return utils.SliceMap(histories, func(h sqlc.HistoryGetSessionRebuildPopulatedRow) *model.History {
+		history := model.HistoryModel(h.History)
+		history.Track = *model.TrackModel(h.Track)
+
+		return history
+	}), nil
+}
+
 func (h *History) GetSkippedUnknownPopulated(ctx context.Context, userID int) ([]*model.History, error) {
 	skippeds, err := h.repo.queries(ctx).HistoryGetSkippedNullPopulated(ctx, int32(userID))
 	if err != nil {
@@ -128,72 +189,244 @@ func (h *History) GetSkippedUnknownPopulated(ctx context.Context, userID int) ([
 	}), nil
 }
 
-func (h *History) Create(ctx context.Context, history *model.History) error {
-	id, err := h.repo.queries(ctx).HistoryCreate(ctx, sqlc.HistoryCreateParams{
-		UserID:     int32(history.UserID),
-		TrackID:    int32(history.TrackID),
-		PlayedAt:   toTime(history.PlayedAt),
-		Skipped:    toBool(history.Skipped),
-		AlbumID:    toInt(history.AlbumID),
-		ArtistID:   toInt(history.ArtistID),
-		PlaylistID: toInt(history.PlaylistID),
-		ShowID:     toInt(history.ShowID),
+// GetPlayCountByUserTracks returns the amount of plays for each track
+func (h *History) GetPlayCountByUserTracks(ctx context.Context, userID int, trackIDs []int) (map[int]int, error) {
+	counts, err := h.repo.queries(ctx).HistoryGetPlayCountByUserTracks(ctx, sqlc.HistoryGetPlayCountByUserTracksParams{
+		UserID:  int32(userID),
+		Column2: utils.SliceMap(trackIDs, func(id int) int32 { return int32(id) }),
 	})
 	if err != nil {
-		return fmt.Errorf("create history %+v | %w", *history, err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get play count by user %d and tracks %+v | %w", userID, trackIDs, err)
 	}
 
-	history.ID = int(id)
+	plays := make(map[int]int, len(counts))
+	for _, c := range counts {
+		plays[int(c.TrackID)] = int(c.PlayCount)
+	}
 
-	return nil
+	return plays, nil
+}
+
+// GetLastPlayedByPlaylist returns the last time each playlist was played as context
+func (h *History) GetLastPlayedByPlaylist(ctx context.Context, userID int) (map[int]time.Time, error) {
+	plays, err := h.repo.queries(ctx).HistoryGetLastPlayedByPlaylist(ctx, int32(userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get last played by playlist %d | %w", userID, err)
+	}
+
+	lastPlayed := make(map[int]time.Time, len(plays))
+	for _, p := range plays {
+		lastPlayed[int(p.PlaylistID.Int32)] = p.PlayedAt.Time
+	}
+
+	return lastPlayed, nil
+}
+
+// CountExisting returns how many of the given entries MergeBatch would consider already known
+// The entries are matched on the track's spotify id so they don't need to exist yet.
+func (h *History) CountExisting(ctx context.Context, userID int, histories []model.History, tolerance time.Duration) (int, error) {
+	count, err := h.repo.queries(ctx).HistoryCountExisting(ctx, sqlc.HistoryCountExistingParams{
+		Column1: int32(userID),
+		Column2: utils.SliceMap(histories, func(h model.History) string { return h.Track.SpotifyID }),
+		Column3: utils.SliceMap(histories, func(h model.History) pgtype.Timestamptz { return toTime(h.PlayedAt) }),
+		Column4: int32(tolerance.Seconds()),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("count existing history for user %d | %w", userID, err)
+	}
+
+	return int(count), nil
+}
+
+func (h *History) Create(ctx context.Context, history *model.History) error {
+	return h.repo.WithRollback(ctx, func(ctx context.Context) error {
+		id, err := h.repo.queries(ctx).HistoryCreate(ctx, sqlc.HistoryCreateParams{
+			UserID:     int32(history.UserID),
+			TrackID:    int32(history.TrackID),
+			PlayedAt:   toTime(history.PlayedAt),
+			Skipped:    toBool(history.Skipped),
+			AlbumID:    toInt(history.AlbumID),
+			ArtistID:   toInt(history.ArtistID),
+			PlaylistID: toInt(history.PlaylistID),
+			ShowID:     toInt(history.ShowID),
+			Source:     string(history.Source),
+		})
+		if err != nil {
+			return fmt.Errorf("create history %+v | %w", *history, err)
+		}
+
+		history.ID = int(id)
+
+		return h.rollupDirty(ctx, history.UserID, history.PlayedAt)
+	})
 }
 
-// CreateBatch let's you create a batch of history entries
-// It will panic if the skipped field is nil!!!
-func (h *History) CreateBatch(ctx context.Context, histories []model.History) error {
+// MergeBatch merges a batch of history entries with the existing history
+// An existing entry of the same track that started within the tolerance is considered the same play.
+// Those are completed with the new data, all others are created.
+// It returns the amount of created entries.
+func (h *History) MergeBatch(ctx context.Context, histories []model.History, source model.HistorySource, tolerance time.Duration) (int, error) {
 	userIDs := make([]int32, 0, len(histories))
 	trackIDs := make([]int32, 0, len(histories))
 	playedAts := make([]pgtype.Timestamptz, 0, len(histories))
-	skippeds := make([]bool, 0, len(histories))
+	skippeds := make([]string, 0, len(histories))
+	reasonStarts := make([]string, 0, len(histories))
+	reasonEnds := make([]string, 0, len(histories))
+	shuffles := make([]string, 0, len(histories))
+	offlines := make([]string, 0, len(histories))
+	platforms := make([]string, 0, len(histories))
+	connCountries := make([]string, 0, len(histories))
 
 	for i := range histories {
 		userIDs = append(userIDs, int32(histories[i].UserID))
 		trackIDs = append(trackIDs, int32(histories[i].TrackID))
 		playedAts = append(playedAts, toTime(histories[i].PlayedAt))
-		skippeds = append(skippeds, *histories[i].Skipped)
+		skippeds = append(skippeds, toBoolText(histories[i].Skipped))
+		reasonStarts = append(reasonStarts, histories[i].ReasonStart)
+		reasonEnds = append(reasonEnds, histories[i].ReasonEnd)
+		shuffles = append(shuffles, toBoolText(histories[i].Shuffle))
+		offlines = append(offlines, toBoolText(histories[i].Offline))
+		platforms = append(platforms, histories[i].Platform)
+		connCountries = append(connCountries, histories[i].ConnCountry)
 	}
 
-	if err := h.repo.queries(ctx).HistoryCreateBatch(ctx, sqlc.HistoryCreateBatchParams{
-		Column1: userIDs,
-		Column2: trackIDs,
-		Column3: playedAts,
-		Column4: skippeds,
+	var created int64
+
+	if err := h.repo.WithRollback(ctx, func(ctx context.Context) error {
+		if _, err := h.repo.queries(ctx).HistoryMergeBatchUpdate(ctx, sqlc.HistoryMergeBatchUpdateParams{
+			Column1:  userIDs,
+			Column2:  trackIDs,
+			Column3:  playedAts,
+			Column4:  skippeds,
+			Column5:  reasonStarts,
+			Column6:  reasonEnds,
+			Column7:  shuffles,
+			Column8:  offlines,
+			Column9:  platforms,
+			Column10: connCountries,
+			Column11: int32(tolerance.Seconds()),
+		}); err != nil {
+			return fmt.Errorf("update existing history batch %w", err)
+		}
+
+		var err error
+		created, err = h.repo.queries(ctx).HistoryMergeBatchCreate(ctx, sqlc.HistoryMergeBatchCreateParams{
+			Column1:  userIDs,
+			Column2:  trackIDs,
+			Column3:  playedAts,
+			Column4:  skippeds,
+			Column5:  reasonStarts,
+			Column6:  reasonEnds,
+			Column7:  shuffles,
+			Column8:  offlines,
+			Column9:  platforms,
+			Column10: connCountries,
+			Column11: int32(tolerance.Seconds()),
+			Column12: string(source),
+		})
+		if err != nil {
+			return fmt.Errorf("create new history batch %w", err)
+		}
+		if created == 0 {
+			return nil
+		}
+
+		dirtyFrom := make(map[int]time.Time)
+		for i := range histories {
+			if from, ok := dirtyFrom[histories[i].UserID]; !ok || histories[i].PlayedAt.Before(from) {
+				dirtyFrom[histories[i].UserID] = histories[i].PlayedAt
+			}
+		}
+		for userID, from := range dirtyFrom {
+			if err := h.rollupDirty(ctx, userID, from); err != nil {
+				return err
+			}
+		}
+
+		return nil
 	}); err != nil {
-		return fmt.Errorf("create history batch %w", err)
+		return 0, err
 	}
 
-	return nil
+	return int(created), nil
 }
 
+// Update changes a history entry
+// The rollup is marked dirty from both the old and the new time it was played.
 func (h *History) Update(ctx context.Context, history model.History) error {
-	if err := h.repo.queries(ctx).HistoryUpdate(ctx, sqlc.HistoryUpdateParams{
-		ID:       int32(history.ID),
-		PlayedAt: toTime(history.PlayedAt),
-		Skipped:  toBool(history.Skipped),
-	}); err != nil {
-		return fmt.Errorf("update history %+v | %w", history, err)
-	}
+	return h.repo.WithRollback(ctx, func(ctx context.Context) error {
+		if err := h.repo.queries(ctx).UserUpdateRollupDirtyFromHistory(ctx, int32(history.ID)); err != nil {
+			return fmt.Errorf("mark history rollup dirty for history %d | %w", history.ID, err)
+		}
 
-	return nil
+		if err := h.repo.queries(ctx).HistoryUpdate(ctx, sqlc.HistoryUpdateParams{
+			ID:       int32(history.ID),
+			PlayedAt: toTime(history.PlayedAt),
+			Skipped:  toBool(history.Skipped),
+		}); err != nil {
+			return fmt.Errorf("update history %+v | %w", history, err)
+		}
+
+		if err := h.repo.queries(ctx).UserUpdateRollupDirtyFromHistory(ctx, int32(history.ID)); err != nil {
+			return fmt.Errorf("mark history rollup dirty for history %d | %w", history.ID, err)
+		}
+
+		return nil
+	})
 }
 
-func (h *History) DeleteOlder(ctx context.Context, userID int, playedAt time.Time) error {
-	if err := h.repo.queries(ctx).HistoryDeleteUserOlder(ctx, sqlc.HistoryDeleteUserOlderParams{
-		UserID:   int32(userID),
-		PlayedAt: toTime(playedAt),
+// rollupDirty marks the rollup of a user as outdated from the given time on
+// It has to be called in the same transaction as the change to the history.
+func (h *History) rollupDirty(ctx context.Context, userID int, from time.Time) error {
+	if err := h.repo.queries(ctx).UserUpdateRollupDirtyFrom(ctx, sqlc.UserUpdateRollupDirtyFromParams{
+		ID:              int32(userID),
+		RollupDirtyFrom: toTime(from),
 	}); err != nil {
-		return fmt.Errorf("delete history for user %d by time %s", userID, playedAt)
+		return fmt.Errorf("mark history rollup dirty for user %d from %s | %w", userID, from, err)
 	}
 
 	return nil
 }
+
+// RefreshRollup brings the daily rollup of a user up to date
+// Only the days from the earliest changed play on are calculated again.
+// The user is locked for the duration of the refresh. A change to the history
+// that is still in progress marks the user while holding the same lock, so
+// the refresh either waits for it and includes it, or it marks the rollup dirty again after the refresh.
+func (h *History) RefreshRollup(ctx context.Context, userID int) error {
+	return h.repo.WithRollback(ctx, func(ctx context.Context) error {
+		day, err := h.repo.queries(ctx).HistoryRollupGetDirtyForUpdate(ctx, int32(userID))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return fmt.Errorf("get dirty history rollup for user %d | %w", userID, err)
+		}
+
+		if err := h.repo.queries(ctx).HistoryRollupDeleteByUserFrom(ctx, sqlc.HistoryRollupDeleteByUserFromParams{
+			UserID: int32(userID),
+			Day:    day,
+		}); err != nil {
+			return fmt.Errorf("delete history rollup for user %d from %+v | %w", userID, day, err)
+		}
+
+		if err := h.repo.queries(ctx).HistoryRollupCreateByUserFrom(ctx, sqlc.HistoryRollupCreateByUserFromParams{
+			Column1: int32(userID),
+			Column2: day,
+		}); err != nil {
+			return fmt.Errorf("create history rollup for user %d from %+v | %w", userID, day, err)
+		}
+
+		if err := h.repo.queries(ctx).UserUpdateRollupClean(ctx, int32(userID)); err != nil {
+			return fmt.Errorf("mark history rollup clean for user %d | %w", userID, err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/topvennie/sortifyr/internal/database/model"
+	"github.com/topvennie/sortifyr/pkg/sqlc"
+	"github.com/topvennie/sortifyr/pkg/utils"
+)
+
+type HistorySession struct {
+	repo Repository
+}
+
+func (r *Repository) NewHistorySession() *HistorySession {
+	return &HistorySession{
+		repo: *r,
+	}
+}
+
+func (h *HistorySession) GetFilteredPaginated(ctx context.Context, filter model.HistorySessionFilter) ([]*model.HistorySession, error) {
+	sessions, err := h.repo.queries(ctx).HistorySessionGetFilteredPaginated(ctx, sqlc.HistorySessionGetFilteredPaginatedParams{
+		Column1:     int32(filter.UserID),
+		Limit:       int32(filter.Limit),
+		Offset:      int32(filter.Offset),
+		Column4:     toTime(filter.Start),
+		FilterStart: !filter.Start.IsZero(),
+		Column5:     toTime(filter.End),
+		FilterEnd:   !filter.End.IsZero(),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get filtered history sessions %+v | %w", filter, err)
+	}
+
+	return utils.SliceMap(sessions, func(s sqlc.HistorySessionGetFilteredPaginatedRow) *model.HistorySession {
+		session := model.HistorySessionModel(s.HistorySession)
+		session.Plays = int(s.Plays)
+		session.Skips = int(s.Skips)
+		session.SkipsKnown = int(s.SkipsKnown)
+
+		return session
+	}), nil
+}
+
+// ReplaceAfter replaces the sessions of a user that end after the given time
+// with the new sessions, linking them to their plays.
+// Sessions that didn't change keep their id, the others are deleted.
+// The plays of a deleted session without a new session are left without one.
+func (h *HistorySession) ReplaceAfter(ctx context.Context, userID int, after time.Time, sessions []model.HistorySession) error {
+	return h.repo.WithRollback(ctx, func(ctx context.Context) error {
+		existing, err := h.repo.queries(ctx).HistorySessionGetByUserEndAfter(ctx, sqlc.HistorySessionGetByUserEndAfterParams{
+			UserID: int32(userID),
+			EndAt:  toTime(after),
+		})
+		if err != nil {
+			return fmt.Errorf("get history sessions after %s | %d | %w", after, userID, err)
+		}
+
+		existingMap := make(map[historySessionKey]int, len(existing))
+		for _, e := range existing {
+			existingMap[historySessionKeyOf(*model.HistorySessionModel(e))] = int(e.ID)
+		}
+
+		for i := range sessions {
+			key := historySessionKeyOf(sessions[i])
+			if id, ok := existingMap[key]; ok {
+				sessions[i].ID = id
+				delete(existingMap, key)
+			}
+		}
+
+		deleteIDs := make([]int32, 0, len(existingMap))
+		for _, id := range existingMap {
+			deleteIDs = append(deleteIDs, int32(id))
+		}
+		if len(deleteIDs) > 0 {
+			if err := h.repo.queries(ctx).HistorySessionDeleteBatch(ctx, deleteIDs); err != nil {
+				return fmt.Errorf("delete history sessions %v | %w", deleteIDs, err)
+			}
+		}
+
+		for i := range sessions {
+			session := &sessions[i]
+
+			if session.ID == 0 {
+				id, err := h.repo.queries(ctx).HistorySessionCreate(ctx, sqlc.HistorySessionCreateParams{
+					UserID:     int32(session.UserID),
+					StartAt:    toTime(session.StartAt),
+					EndAt:      toTime(session.EndAt),
+					PlaylistID: toInt(session.PlaylistID),
+					AlbumID:    toInt(session.AlbumID),
+					ArtistID:   toInt(session.ArtistID),
+					ShowID:     toInt(session.ShowID),
+				})
+				if err != nil {
+					return fmt.Errorf("create history session %+v | %w", *session, err)
+				}
+
+				session.ID = int(id)
+			}
+
+			if err := h.repo.queries(ctx).HistoryUpdateSessionBatch(ctx, sqlc.HistoryUpdateSessionBatchParams{
+				Column1:   utils.SliceMap(session.HistoryIDs, func(id int) int32 { return int32(id) }),
+				SessionID: toInt(session.ID),
+			}); err != nil {
+				return fmt.Errorf("link history to session %d | %w", session.ID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// historySessionKey identifies a session by its content
+type historySessionKey struct {
+	start      int64
+	end        int64
+	playlistID int
+	albumID    int
+	artistID   int
+	showID     int
+}
+
+// historySessionKeyOf uses microseconds, the precision of the database
+func historySessionKeyOf(s model.HistorySession) historySessionKey {
+	return historySessionKey{
+		start:      s.StartAt.UnixMicro(),
+		end:        s.EndAt.UnixMicro(),
+		playlistID: s.PlaylistID,
+		albumID:    s.AlbumID,
+		artistID:   s.ArtistID,
+		showID:     s.ShowID,
+	}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -34,11 +35,20 @@ func (l *Link) GetAllByUser(ctx context.Context, userID int) ([]*model.Link, err
 }
 
 func (l *Link) Create(ctx context.Context, link *model.Link) error {
+	filter, err := json.Marshal(link.Filter)
+	if err != nil {
+		return fmt.Errorf("marshal link filter %+v | %w", link.Filter, err)
+	}
+
 	id, err := l.repo.queries(ctx).LinkCreate(ctx, sqlc.LinkCreateParams{
-		SourceDirectoryID: toInt(link.SourceDirectoryID),
-		SourcePlaylistID:  toInt(link.SourcePlaylistID),
-		TargetDirectoryID: toInt(link.TargetDirectoryID),
-		TargetPlaylistID:  toInt(link.TargetPlaylistID),
+		SourceDirectoryID:     toInt(link.SourceDirectoryID),
+		SourcePlaylistID:      toInt(link.SourcePlaylistID),
+		SourceUserID:          toInt(link.SourceUserID),
+		TargetDirectoryID:     toInt(link.TargetDirectoryID),
+		TargetPlaylistID:      toInt(link.TargetPlaylistID),
+		Mode:                  sqlc.LinkMode(link.Mode),
+		Filter:                filter,
+		IncludeSubdirectories: link.IncludeSubdirectories,
 	})
 	if err != nil {
 		return fmt.Errorf("create link %+v | %w", *link, err)
@@ -50,12 +60,21 @@ func (l *Link) Create(ctx context.Context, link *model.Link) error {
 }
 
 func (l *Link) Update(ctx context.Context, link model.Link) error {
+	filter, err := json.Marshal(link.Filter)
+	if err != nil {
+		return fmt.Errorf("marshal link filter %+v | %w", link.Filter, err)
+	}
+
 	if err := l.repo.queries(ctx).LinkUpdate(ctx, sqlc.LinkUpdateParams{
-		ID:                int32(link.ID),
-		SourceDirectoryID: toInt(link.SourceDirectoryID),
-		SourcePlaylistID:  toInt(link.SourcePlaylistID),
-		TargetDirectoryID: toInt(link.TargetDirectoryID),
-		TargetPlaylistID:  toInt(link.TargetPlaylistID),
+		ID:                    int32(link.ID),
+		SourceDirectoryID:     toInt(link.SourceDirectoryID),
+		SourcePlaylistID:      toInt(link.SourcePlaylistID),
+		SourceUserID:          toInt(link.SourceUserID),
+		TargetDirectoryID:     toInt(link.TargetDirectoryID),
+		TargetPlaylistID:      toInt(link.TargetPlaylistID),
+		Mode:                  sqlc.LinkMode(link.Mode),
+		Filter:                filter,
+		IncludeSubdirectories: link.IncludeSubdirectories,
 	}); err != nil {
 		return fmt.Errorf("update link %+v | %w", link, err)
 	}
@@ -63,6 +82,21 @@ func (l *Link) Update(ctx context.Context, link model.Link) error {
 	return nil
 }
 
+func (l *Link) UpdateRun(ctx context.Context, link model.Link) error {
+	if err := l.repo.queries(ctx).LinkUpdateRun(ctx, sqlc.LinkUpdateRunParams{
+		ID:             int32(link.ID),
+		LastRunAt:      toTime(link.LastRunAt),
+		LastRunAdded:   int32(link.LastRunAdded),
+		LastRunDeleted: int32(link.LastRunDeleted),
+		LastRunError:   toString(link.LastRunError),
+		SyncedAt:       toTime(link.SyncedAt),
+	}); err != nil {
+		return fmt.Errorf("update link run %+v | %w", link, err)
+	}
+
+	return nil
+}
+
 func (l *Link) Delete(ctx context.Context, linkID int) error {
 	if err := l.repo.queries(ctx).LinkDelete(ctx, int32(linkID)); err != nil {
 		return fmt.Errorf("delete link %d | %w", linkID, err)
@@ -70,3 +104,12 @@ func (l *Link) Delete(ctx context.Context, linkID int) error {
 
 	return nil
 }
+
+// DeleteBySourcePlaylistUser deletes the links from playlists that no other user follows
+func (l *Link) DeleteBySourcePlaylistUser(ctx context.Context, userID int) error {
+	if err := l.repo.queries(ctx).LinkDeleteBySourcePlaylistUser(ctx, int32(userID)); err != nil {
+		return fmt.Errorf("delete links by source playlist user %d | %w", userID, err)
+	}
+
+	return nil
+}
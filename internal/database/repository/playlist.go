@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/topvennie/sortifyr/internal/database/model"
 	"github.com/topvennie/sortifyr/pkg/sqlc"
 	"github.com/topvennie/sortifyr/pkg/utils"
@@ -132,6 +133,19 @@ func (p *Playlist) GetTrackByPlaylistIDs(ctx context.Context, playlistIDs []int)
 	return utils.SliceMap(tracks, model.PlaylistTrackModel), nil
 }
 
+// GetIDsByArtist returns the ids of all playlists containing a track of the artist
+func (p *Playlist) GetIDsByArtist(ctx context.Context, artistID int) ([]int, error) {
+	ids, err := p.repo.queries(ctx).PlaylistTrackGetPlaylistIdsByArtist(ctx, int32(artistID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get playlist ids by artist %d | %w", artistID, err)
+	}
+
+	return utils.SliceMap(ids, func(id int32) int { return int(id) }), nil
+}
+
 func (p *Playlist) Create(ctx context.Context, playlist *model.Playlist) error {
 	id, err := p.repo.queries(ctx).PlaylistCreate(ctx, sqlc.PlaylistCreateParams{
 		SpotifyID:     playlist.SpotifyID,
@@ -201,6 +215,17 @@ func (p *Playlist) Update(ctx context.Context, playlist model.Playlist) error {
 	return nil
 }
 
+// UpdateOwnerClear removes the owner from all playlists owned by the user
+// It returns the ids of the affected playlists
+func (p *Playlist) UpdateOwnerClear(ctx context.Context, userID int) ([]int, error) {
+	ids, err := p.repo.queries(ctx).PlaylistUpdateOwnerClear(ctx, toInt(userID))
+	if err != nil {
+		return nil, fmt.Errorf("clear playlist owner %d | %w", userID, err)
+	}
+
+	return utils.SliceMap(ids, func(id int32) int { return int(id) }), nil
+}
+
 func (p *Playlist) DeleteTrackByPlaylistTrack(ctx context.Context, track model.PlaylistTrack) error {
 	if err := p.repo.queries(ctx).PlaylistTrackDeleteByPlaylistTrack(ctx, sqlc.PlaylistTrackDeleteByPlaylistTrackParams{
 		PlaylistID: int32(track.PlaylistID),
@@ -222,3 +247,16 @@ func (p *Playlist) DeleteUserByUserPlaylist(ctx context.Context, user model.Play
 
 	return nil
 }
+
+// DeleteUnused deletes the playlists that no user follows and that are not referenced anymore
+// It returns the cover ids of the deleted playlists
+func (p *Playlist) DeleteUnused(ctx context.Context, playlistIDs []int) ([]string, error) {
+	coverIDs, err := p.repo.queries(ctx).PlaylistDeleteUnused(ctx, utils.SliceMap(playlistIDs, func(id int) int32 { return int32(id) }))
+	if err != nil {
+		return nil, fmt.Errorf("delete unused playlists %+v | %w", playlistIDs, err)
+	}
+
+	coverIDs = utils.SliceFilter(coverIDs, func(c pgtype.Text) bool { return c.Valid && c.String != "" })
+
+	return utils.SliceMap(coverIDs, func(c pgtype.Text) string { return c.String }), nil
+}
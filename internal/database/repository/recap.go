@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/topvennie/sortifyr/internal/database/model"
+	"github.com/topvennie/sortifyr/pkg/sqlc"
+)
+
+type Recap struct {
+	repo Repository
+}
+
+func (r *Repository) NewRecap() *Recap {
+	return &Recap{
+		repo: *r,
+	}
+}
+
+func (r *Recap) GetByUserPeriod(ctx context.Context, userID, year, month int) (*model.Recap, error) {
+	recap, err := r.repo.queries(ctx).RecapGetByUserPeriod(ctx, sqlc.RecapGetByUserPeriodParams{
+		UserID: int32(userID),
+		Year:   int32(year),
+		Month:  int32(month),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get recap by user %d for %d-%d | %w", userID, year, month, err)
+	}
+
+	return model.RecapModel(recap), nil
+}
+
+// Save creates the recap or overwrites an existing one for the same period
+func (r *Recap) Save(ctx context.Context, recap *model.Recap) error {
+	data, err := json.Marshal(recap.Data)
+	if err != nil {
+		return fmt.Errorf("marshal recap data %+v | %w", recap.Data, err)
+	}
+
+	id, err := r.repo.queries(ctx).RecapUpsert(ctx, sqlc.RecapUpsertParams{
+		UserID: int32(recap.UserID),
+		Year:   int32(recap.Year),
+		Month:  int32(recap.Month),
+		Data:   data,
+	})
+	if err != nil {
+		return fmt.Errorf("save recap %+v | %w", *recap, err)
+	}
+
+	recap.ID = int(id)
+
+	return nil
+}
+
+func (r *Recap) DeleteByUser(ctx context.Context, userID int) error {
+	if err := r.repo.queries(ctx).RecapDeleteByUser(ctx, int32(userID)); err != nil {
+		return fmt.Errorf("delete recaps by user %d | %w", userID, err)
+	}
+
+	return nil
+}
+
+// DeleteByUserOverlap removes the recaps of a user whose period overlaps with the given time range
+func (r *Recap) DeleteByUserOverlap(ctx context.Context, userID int, start, end time.Time) error {
+	if err := r.repo.queries(ctx).RecapDeleteByUserOverlap(ctx, sqlc.RecapDeleteByUserOverlapParams{
+		UserID:  int32(userID),
+		Column2: toTime(start),
+		Column3: toTime(end),
+	}); err != nil {
+		return fmt.Errorf("delete recaps by user %d between %s and %s | %w", userID, start, end, err)
+	}
+
+	return nil
+}
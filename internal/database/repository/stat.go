@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/topvennie/sortifyr/internal/database/model"
+	"github.com/topvennie/sortifyr/pkg/sqlc"
+	"github.com/topvennie/sortifyr/pkg/utils"
+)
+
+type Stat struct {
+	repo Repository
+}
+
+func (r *Repository) NewStat() *Stat {
+	return &Stat{
+		repo: *r,
+	}
+}
+
+func (s *Stat) GetSummary(ctx context.Context, filter model.StatFilter) (*model.StatSummary, error) {
+	summary, err := s.repo.queries(ctx).StatGetSummary(ctx, sqlc.StatGetSummaryParams{
+		Column1:     int32(filter.UserID),
+		Column2:     toTime(filter.Start),
+		FilterStart: !filter.Start.IsZero(),
+		Column3:     toTime(filter.End),
+		FilterEnd:   !filter.End.IsZero(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get stat summary %+v | %w", filter, err)
+	}
+
+	return model.StatSummaryModel(summary), nil
+}
+
+func (s *Stat) GetTopTracks(ctx context.Context, filter model.StatFilter) ([]*model.StatTrack, error) {
+	tracks, err := s.repo.queries(ctx).StatGetTopTracks(ctx, sqlc.StatGetTopTracksParams{
+		Column1:     int32(filter.UserID),
+		Column2:     toTime(filter.Start),
+		FilterStart: !filter.Start.IsZero(),
+		Column3:     toTime(filter.End),
+		FilterEnd:   !filter.End.IsZero(),
+		Limit:       int32(filter.Limit),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get top tracks %+v | %w", filter, err)
+	}
+
+	return utils.SliceMap(tracks, func(t sqlc.StatGetTopTracksRow) *model.StatTrack {
+		return &model.StatTrack{Track: *model.TrackModel(t.Track), Plays: int(t.Plays)}
+	}), nil
+}
+
+func (s *Stat) GetTopArtists(ctx context.Context, filter model.StatFilter) ([]*model.StatArtist, error) {
+	artists, err := s.repo.queries(ctx).StatGetTopArtists(ctx, sqlc.StatGetTopArtistsParams{
+		Column1:     int32(filter.UserID),
+		Column2:     toTime(filter.Start),
+		FilterStart: !filter.Start.IsZero(),
+		Column3:     toTime(filter.End),
+		FilterEnd:   !filter.End.IsZero(),
+		Limit:       int32(filter.Limit),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get top artists %+v | %w", filter, err)
+	}
+
+	return utils.SliceMap(artists, func(a sqlc.StatGetTopArtistsRow) *model.StatArtist {
+		return &model.StatArtist{Artist: *model.ArtistModel(a.Artist), Plays: int(a.Plays)}
+	}), nil
+}
+
+func (s *Stat) GetTopAlbums(ctx context.Context, filter model.StatFilter) ([]*model.StatAlbum, error) {
+	albums, err := s.repo.queries(ctx).StatGetTopAlbums(ctx, sqlc.StatGetTopAlbumsParams{
+		Column1:     int32(filter.UserID),
+		Column2:     toTime(filter.Start),
+		FilterStart: !filter.Start.IsZero(),
+		Column3:     toTime(filter.End),
+		FilterEnd:   !filter.End.IsZero(),
+		Limit:       int32(filter.Limit),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get top albums %+v | %w", filter, err)
+	}
+
+	return utils.SliceMap(albums, func(a sqlc.StatGetTopAlbumsRow) *model.StatAlbum {
+		return &model.StatAlbum{Album: *model.AlbumModel(a.Album), Plays: int(a.Plays)}
+	}), nil
+}
+
+// GetTopPlaylists returns the playlists most often used as context
+func (s *Stat) GetTopPlaylists(ctx context.Context, filter model.StatFilter) ([]*model.StatPlaylist, error) {
+	playlists, err := s.repo.queries(ctx).StatGetTopPlaylists(ctx, sqlc.StatGetTopPlaylistsParams{
+		Column1:     int32(filter.UserID),
+		Column2:     toTime(filter.Start),
+		FilterStart: !filter.Start.IsZero(),
+		Column3:     toTime(filter.End),
+		FilterEnd:   !filter.End.IsZero(),
+		Limit:       int32(filter.Limit),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get top playlists %+v | %w", filter, err)
+	}
+
+	return utils.SliceMap(playlists, func(p sqlc.StatGetTopPlaylistsRow) *model.StatPlaylist {
+		return &model.StatPlaylist{Playlist: *model.PlaylistModel(p.Playlist), Plays: int(p.Plays)}
+	}), nil
+}
+
+// GetHeatmap returns the plays for each hour of each weekday
+func (s *Stat) GetHeatmap(ctx context.Context, filter model.StatRollupFilter) ([]*model.StatHeatmap, error) {
+	cells, err := s.repo.queries(ctx).HistoryRollupGetHeatmap(ctx, sqlc.HistoryRollupGetHeatmapParams{
+		Column1:        int32(filter.UserID),
+		Column2:        toDate(filter.Start),
+		FilterStart:    !filter.Start.IsZero(),
+		Column3:        toDate(filter.End),
+		FilterEnd:      !filter.End.IsZero(),
+		Column4:        int32(filter.TrackID),
+		FilterTrack:    filter.TrackID != 0,
+		Column5:        int32(filter.PlaylistID),
+		FilterPlaylist: filter.PlaylistID != 0,
+		Column6:        int32(filter.ArtistID),
+		FilterArtist:   filter.ArtistID != 0,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get heatmap %+v | %w", filter, err)
+	}
+
+	return utils.SliceMap(cells, func(c sqlc.HistoryRollupGetHeatmapRow) *model.StatHeatmap {
+		return &model.StatHeatmap{Weekday: time.Weekday(c.Weekday), Hour: int(c.Hour), Plays: int(c.Plays)}
+	}), nil
+}
+
+// GetDaily returns the plays for each day with at least one play
+func (s *Stat) GetDaily(ctx context.Context, filter model.StatRollupFilter) ([]*model.StatDaily, error) {
+	days, err := s.repo.queries(ctx).HistoryRollupGetDaily(ctx, sqlc.HistoryRollupGetDailyParams{
+		Column1:        int32(filter.UserID),
+		Column2:        toDate(filter.Start),
+		FilterStart:    !filter.Start.IsZero(),
+		Column3:        toDate(filter.End),
+		FilterEnd:      !filter.End.IsZero(),
+		Column4:        int32(filter.TrackID),
+		FilterTrack:    filter.TrackID != 0,
+		Column5:        int32(filter.PlaylistID),
+		FilterPlaylist: filter.PlaylistID != 0,
+		Column6:        int32(filter.ArtistID),
+		FilterArtist:   filter.ArtistID != 0,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get daily plays %+v | %w", filter, err)
+	}
+
+	return utils.SliceMap(days, func(d sqlc.HistoryRollupGetDailyRow) *model.StatDaily {
+		return &model.StatDaily{Day: d.Day.Time, Plays: int(d.Plays)}
+	}), nil
+}
+
+// The functions below use a fixed period where the end is exclusive
+
+// GetLongestStreak returns the most consecutive days with at least one play
+func (s *Stat) GetLongestStreak(ctx context.Context, userID int, start, end time.Time) (int, error) {
+	streak, err := s.repo.queries(ctx).StatGetLongestStreak(ctx, sqlc.StatGetLongestStreakParams{
+		UserID: int32(userID),
+		Start:  toTime(start),
+		EndAt:  toTime(end),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("get longest streak %d from %s to %s | %w", userID, start, end, err)
+	}
+
+	return int(streak), nil
+}
+
+// GetTopHour returns the hour of the day with the most plays
+func (s *Stat) GetTopHour(ctx context.Context, userID int, start, end time.Time) (int, error) {
+	hour, err := s.repo.queries(ctx).StatGetTopHour(ctx, sqlc.StatGetTopHourParams{
+		UserID: int32(userID),
+		Start:  toTime(start),
+		EndAt:  toTime(end),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("get top hour %d from %s to %s | %w", userID, start, end, err)
+	}
+
+	return int(hour.Hour), nil
+}
+
+// GetTopWeekday returns the day of the week with the most plays
+func (s *Stat) GetTopWeekday(ctx context.Context, userID int, start, end time.Time) (time.Weekday, error) {
+	weekday, err := s.repo.queries(ctx).StatGetTopWeekday(ctx, sqlc.StatGetTopWeekdayParams{
+		UserID: int32(userID),
+		Start:  toTime(start),
+		EndAt:  toTime(end),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("get top weekday %d from %s to %s | %w", userID, start, end, err)
+	}
+
+	return time.Weekday(weekday.Weekday), nil
+}
+
+// GetTopDiscovery returns the most played track that was never played before the start
+func (s *Stat) GetTopDiscovery(ctx context.Context, userID int, start, end time.Time) (*model.StatTrack, error) {
+	track, err := s.repo.queries(ctx).StatGetTopDiscovery(ctx, sqlc.StatGetTopDiscoveryParams{
+		UserID: int32(userID),
+		Start:  toTime(start),
+		EndAt:  toTime(end),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get top discovery %d from %s to %s | %w", userID, start, end, err)
+	}
+
+	return &model.StatTrack{Track: *model.TrackModel(track.Track), Plays: int(track.Plays)}, nil
+}
+
+// GetTopSkipped returns the most skipped track
+func (s *Stat) GetTopSkipped(ctx context.Context, userID int, start, end time.Time) (*model.StatTrack, error) {
+	track, err := s.repo.queries(ctx).StatGetTopSkipped(ctx, sqlc.StatGetTopSkippedParams{
+		UserID: int32(userID),
+		Start:  toTime(start),
+		EndAt:  toTime(end),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get top skipped %d from %s to %s | %w", userID, start, end, err)
+	}
+
+	return &model.StatTrack{Track: *model.TrackModel(track.Track), Plays: int(track.Plays)}, nil
+}
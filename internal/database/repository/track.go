@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/topvennie/sortifyr/internal/database/model"
 	"github.com/topvennie/sortifyr/pkg/sqlc"
@@ -69,6 +70,31 @@ func (t *Track) GetAllBySpotify(ctx context.Context, spotifyIDs []string) ([]*mo
 	return utils.SliceMap(tracks, model.TrackModel), nil
 }
 
+// GetMatches looks for the track of each match by artist, name and duration
+// It returns a map from the match index to the track id, unmatched indexes are left out.
+func (t *Track) GetMatches(ctx context.Context, matches []model.TrackMatch, tolerance time.Duration) (map[int]int, error) {
+	rows, err := t.repo.queries(ctx).TrackGetMatchByArtistName(ctx, sqlc.TrackGetMatchByArtistNameParams{
+		Column1: utils.SliceMap(matches, func(m model.TrackMatch) string { return m.Artist }),
+		Column2: utils.SliceMap(matches, func(m model.TrackMatch) string { return m.Name }),
+		Column3: utils.SliceMap(matches, func(m model.TrackMatch) int32 { return int32(m.DurationMs) }),
+		Column4: int32(tolerance.Milliseconds()),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get track matches %w", err)
+	}
+
+	trackIDs := make(map[int]int, len(rows))
+	for _, r := range rows {
+		// Postgres arrays start at 1
+		trackIDs[int(r.Idx)-1] = int(r.ID)
+	}
+
+	return trackIDs, nil
+}
+
 func (t *Track) GetByName(ctx context.Context, name string) ([]*model.Track, error) {
 	tracks, err := t.repo.queries(ctx).TrackGetByName(ctx, toString(name))
 	if err != nil {
@@ -90,6 +116,79 @@ func (t *Track) GetByPlaylist(ctx context.Context, playlistID int) ([]*model.Tra
 		return nil, fmt.Errorf("get tracks by playlist %d | %w", playlistID, err)
 	}
 
+	return utils.SliceMap(tracks, func(t sqlc.TrackGetByPlaylistRow) *model.Track {
+		track := model.TrackModel(t.Track)
+		track.CreatedAt = t.AddedAt.Time
+
+		return track
+	}), nil
+}
+
+func (t *Track) GetSavedByUser(ctx context.Context, userID int) ([]*model.Track, error) {
+	tracks, err := t.repo.queries(ctx).TrackGetSavedByUser(ctx, int32(userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get saved tracks by user %d | %w", userID, err)
+	}
+
+	return utils.SliceMap(tracks, func(t sqlc.TrackGetSavedByUserRow) *model.Track {
+		track := model.TrackModel(t.Track)
+		track.CreatedAt = t.AddedAt.Time
+
+		return track
+	}), nil
+}
+
+// GetSavedInPlaylistByUser returns the saved tracks of a user that are also in one of their own playlists
+// A track is returned once for every time it's in a playlist
+func (t *Track) GetSavedInPlaylistByUser(ctx context.Context, userID int) ([]*model.Track, error) {
+	tracks, err := t.repo.queries(ctx).TrackGetSavedInPlaylistByUser(ctx, int32(userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get saved tracks in playlists by user %d | %w", userID, err)
+	}
+
+	return utils.SliceMap(tracks, func(t sqlc.TrackGetSavedInPlaylistByUserRow) *model.Track {
+		track := model.TrackModel(t.Track)
+		track.Playlist = *model.PlaylistModel(t.Playlist)
+
+		return track
+	}), nil
+}
+
+// GetDeletedByPlaylistAfter returns the tracks removed from a playlist after a given time
+func (t *Track) GetDeletedByPlaylistAfter(ctx context.Context, playlistID int, after time.Time) ([]*model.Track, error) {
+	tracks, err := t.repo.queries(ctx).TrackGetDeletedByPlaylistAfter(ctx, sqlc.TrackGetDeletedByPlaylistAfterParams{
+		PlaylistID: int32(playlistID),
+		DeletedAt:  toTime(after),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get tracks deleted by playlist %d after %s | %w", playlistID, after, err)
+	}
+
+	return utils.SliceMap(tracks, model.TrackModel), nil
+}
+
+// GetSavedDeletedByUserAfter returns the tracks removed from the user's liked songs after a given time
+func (t *Track) GetSavedDeletedByUserAfter(ctx context.Context, userID int, after time.Time) ([]*model.Track, error) {
+	tracks, err := t.repo.queries(ctx).TrackGetSavedDeletedByUserAfter(ctx, sqlc.TrackGetSavedDeletedByUserAfterParams{
+		UserID:    int32(userID),
+		DeletedAt: toTime(after),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get saved tracks deleted by user %d after %s | %w", userID, after, err)
+	}
+
 	return utils.SliceMap(tracks, model.TrackModel), nil
 }
 
@@ -159,12 +258,68 @@ func (t *Track) GetDeletedFiltered(ctx context.Context, filter model.TrackFilter
 	}), nil
 }
 
+func (t *Track) GetSavedCreatedFiltered(ctx context.Context, filter model.TrackFilter) ([]*model.Track, error) {
+	tracks, err := t.repo.queries(ctx).TrackGetSavedCreatedFiltered(ctx, sqlc.TrackGetSavedCreatedFilteredParams{
+		Column3: int32(filter.UserID),
+		Limit:   int32(filter.Limit),
+		Offset:  int32(filter.Offset),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get filtered created saved tracks %+v | %w", filter, err)
+	}
+
+	return utils.SliceMap(tracks, func(t sqlc.TrackGetSavedCreatedFilteredRow) *model.Track {
+		track := model.TrackModel(t.Track)
+		track.CreatedAt = t.UserTrack.SavedAt.Time
+
+		return track
+	}), nil
+}
+
+func (t *Track) GetSavedDeletedFiltered(ctx context.Context, filter model.TrackFilter) ([]*model.Track, error) {
+	tracks, err := t.repo.queries(ctx).TrackGetSavedDeletedFiltered(ctx, sqlc.TrackGetSavedDeletedFilteredParams{
+		Column3: int32(filter.UserID),
+		Limit:   int32(filter.Limit),
+		Offset:  int32(filter.Offset),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get filtered deleted saved tracks %+v | %w", filter, err)
+	}
+
+	return utils.SliceMap(tracks, func(t sqlc.TrackGetSavedDeletedFilteredRow) *model.Track {
+		track := model.TrackModel(t.Track)
+		track.CreatedAt = t.UserTrack.SavedAt.Time
+		track.DeletedAt = t.UserTrack.DeletedAt.Time
+
+		return track
+	}), nil
+}
+
+func (t *Track) GetArtistByTracks(ctx context.Context, trackIDs []int) ([]*model.TrackArtist, error) {
+	artists, err := t.repo.queries(ctx).TrackArtistGetByTracks(ctx, utils.SliceMap(trackIDs, func(id int) int32 { return int32(id) }))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get track artists by tracks %+v | %w", trackIDs, err)
+	}
+
+	return utils.SliceMap(artists, model.TrackArtistModel), nil
+}
+
 func (t *Track) Create(ctx context.Context, track *model.Track) error {
 	id, err := t.repo.queries(ctx).TrackCreate(ctx, sqlc.TrackCreateParams{
 		SpotifyID:  track.SpotifyID,
 		Name:       toString(track.Name),
 		Popularity: toInt(track.Popularity),
 		DurationMs: toInt(track.DurationMs),
+		Explicit:   toBool(track.Explicit),
 	})
 	if err != nil {
 		return fmt.Errorf("create track %+v | %w", *track, err)
@@ -189,12 +344,29 @@ func (t *Track) CreateArtist(ctx context.Context, artist *model.TrackArtist) err
 	return nil
 }
 
+func (t *Track) CreateUser(ctx context.Context, user *model.UserTrack) error {
+	id, err := t.repo.queries(ctx).UserTrackCreate(ctx, sqlc.UserTrackCreateParams{
+		UserID:  int32(user.UserID),
+		TrackID: int32(user.TrackID),
+		SavedAt: toTime(user.SavedAt),
+	})
+	if err != nil {
+		return fmt.Errorf("create user track %+v | %w", *user, err)
+	}
+
+	user.ID = int(id)
+
+	return nil
+}
+
 func (t *Track) Update(ctx context.Context, track model.Track) error {
 	if err := t.repo.queries(ctx).TrackUpdate(ctx, sqlc.TrackUpdateParams{
 		ID:         int32(track.ID),
 		Name:       toString(track.Name),
 		Popularity: toInt(track.Popularity),
 		DurationMs: toInt(track.DurationMs),
+		Explicit:   toBool(track.Explicit),
+		AlbumID:    toInt(track.AlbumID),
 	}); err != nil {
 		return fmt.Errorf("update track %+v | %w", track, err)
 	}
@@ -212,3 +384,14 @@ func (t *Track) DeleteArtistByArtistTrack(ctx context.Context, artist model.Trac
 
 	return nil
 }
+
+func (t *Track) DeleteUserByUserTrack(ctx context.Context, user model.UserTrack) error {
+	if err := t.repo.queries(ctx).UserTrackDeleteByUserTrack(ctx, sqlc.UserTrackDeleteByUserTrackParams{
+		UserID:  int32(user.UserID),
+		TrackID: int32(user.TrackID),
+	}); err != nil {
+		return fmt.Errorf("delete user track %+v | %w", user, err)
+	}
+
+	return nil
+}
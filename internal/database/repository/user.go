@@ -97,3 +97,35 @@ func (u *User) Update(ctx context.Context, user model.User) error {
 
 	return nil
 }
+
+func (u *User) UpdateRecentlyPlayedAfter(ctx context.Context, user model.User) error {
+	if err := u.repo.queries(ctx).UserUpdateRecentlyPlayedAfter(ctx, sqlc.UserUpdateRecentlyPlayedAfterParams{
+		ID:                  int32(user.ID),
+		RecentlyPlayedAfter: toTime(user.RecentlyPlayedAfter),
+	}); err != nil {
+		return fmt.Errorf("update user recently played after %+v | %w", user, err)
+	}
+
+	return nil
+}
+
+func (u *User) UpdateTimezone(ctx context.Context, user model.User) error {
+	if err := u.repo.queries(ctx).UserUpdateTimezone(ctx, sqlc.UserUpdateTimezoneParams{
+		ID:       int32(user.ID),
+		Timezone: user.Timezone,
+	}); err != nil {
+		return fmt.Errorf("update user timezone %+v | %w", user, err)
+	}
+
+	return nil
+}
+
+// Delete deletes a user
+// Everything that references the user is deleted by cascade
+func (u *User) Delete(ctx context.Context, userID int) error {
+	if err := u.repo.queries(ctx).UserDelete(ctx, int32(userID)); err != nil {
+		return fmt.Errorf("delete user %d | %w", userID, err)
+	}
+
+	return nil
+}
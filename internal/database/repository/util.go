@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
@@ -23,10 +24,24 @@ func toBool(b *bool) pgtype.Bool {
 	return pgtype.Bool{Bool: bb, Valid: b != nil}
 }
 
+// toBoolText is used for nullable booleans in batch queries
+// An empty string is stored as NULL
+func toBoolText(b *bool) string {
+	if b == nil {
+		return ""
+	}
+
+	return strconv.FormatBool(*b)
+}
+
 func toTime(t time.Time) pgtype.Timestamptz {
 	return pgtype.Timestamptz{Time: t, Valid: !t.IsZero()}
 }
 
+func toDate(t time.Time) pgtype.Date {
+	return pgtype.Date{Time: t, Valid: !t.IsZero()}
+}
+
 func toDuration(d time.Duration) pgtype.Int8 {
 	return pgtype.Int8{Int64: d.Nanoseconds(), Valid: d.Nanoseconds() > 0}
 }
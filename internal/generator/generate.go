@@ -30,19 +30,25 @@ func (g *generator) Generate(ctx context.Context, gen *model.Generator) ([]model
 	return tracks, nil
 }
 
-type trackPlayCount struct {
-	track     model.Track
-	playCount int
-}
+// excluded returns all track ids that are excluded
+// Can be from the excluded tracks list,
+// the excluded playlists list, the excluded directories list or the user's liked songs
+func (g *generator) excluded(ctx context.Context, gen model.Generator) (map[int]bool, error) {
+	excludedPlaylistIDs := slices.Clone(gen.Params.ExcludedPlaylistIDs)
+	if len(gen.Params.ExcludedDirectoryIDs) > 0 {
+		directories, err := g.directory.GetByUserPopulated(ctx, gen.UserID)
+		if err != nil {
+			return nil, err
+		}
 
-func (g *generator) top(ctx context.Context, gen model.Generator) ([]model.Track, error) {
-	params := gen.Params.ParamsTop
-	window := dynamicWindow(params.Window)
+		for _, directoryID := range gen.Params.ExcludedDirectoryIDs {
+			for _, p := range model.DirectoryPlaylistsRecursive(directories, directoryID) {
+				excludedPlaylistIDs = append(excludedPlaylistIDs, p.ID)
+			}
+		}
+	}
 
-	// Get all excluded tracks
-	// Can be from the excluded tracks list
-	// Or the excluded playlists list
-	excludedPlaylistTracks, err := g.playlist.GetTrackByPlaylistIDs(ctx, gen.Params.ExcludedPlaylistIDs)
+	excludedPlaylistTracks, err := g.playlist.GetTrackByPlaylistIDs(ctx, excludedPlaylistIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -54,6 +60,33 @@ func (g *generator) top(ctx context.Context, gen model.Generator) ([]model.Track
 		excludedTracksMap[t] = true
 	}
 
+	if gen.Params.ExcludeLiked {
+		likedTracks, err := g.track.GetSavedByUser(ctx, gen.UserID)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range likedTracks {
+			excludedTracksMap[t.ID] = true
+		}
+	}
+
+	return excludedTracksMap, nil
+}
+
+type trackPlayCount struct {
+	track     model.Track
+	playCount int
+}
+
+func (g *generator) top(ctx context.Context, gen model.Generator) ([]model.Track, error) {
+	params := gen.Params.ParamsTop
+	window := dynamicWindow(params.Window)
+
+	excludedTracksMap, err := g.excluded(ctx, gen)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get history for last 14 days
 	skipped := false
 	history, err := g.history.GetPopulatedFiltered(ctx, model.HistoryFilter{
@@ -115,20 +148,10 @@ func (g *generator) oldTop(ctx context.Context, gen model.Generator) ([]model.Tr
 	recentWindow := dynamicWindow(params.RecentWindow)
 	peakWindow := dynamicWindow(params.PeakWindow)
 
-	// Get all excluded tracks
-	// Can be from the excluded tracks list
-	// Or the excluded playlists list
-	excludedPlaylistTracks, err := g.playlist.GetTrackByPlaylistIDs(ctx, gen.Params.ExcludedPlaylistIDs)
+	excludedTracksMap, err := g.excluded(ctx, gen)
 	if err != nil {
 		return nil, err
 	}
-	excludedTracksMap := make(map[int]bool)
-	for _, t := range excludedPlaylistTracks {
-		excludedTracksMap[t.TrackID] = true
-	}
-	for _, t := range gen.Params.ExcludedTrackIDs {
-		excludedTracksMap[t] = true
-	}
 
 	// Get the relevant recent history
 	skipped := false
@@ -16,6 +16,7 @@ import (
 )
 
 type generator struct {
+	directory repository.Directory
 	generator repository.Generator
 	history   repository.History
 	playlist  repository.Playlist
@@ -27,6 +28,7 @@ var G *generator
 
 func Init(repo repository.Repository) error {
 	G = &generator{
+		directory: *repo.NewDirectory(),
 		generator: *repo.NewGenerator(),
 		history:   *repo.NewHistory(),
 		playlist:  *repo.NewPlaylist(),
@@ -307,3 +309,14 @@ func (g *generator) Delete(ctx context.Context, user model.User, gen model.Gener
 
 	return nil
 }
+
+// RemoveTasks unregisters the scheduled tasks of generators
+func (g *generator) RemoveTasks(ctx context.Context, gens []*model.Generator) error {
+	for _, gen := range gens {
+		if err := task.Manager.Remove(ctx, getTaskUID(gen)); err != nil && !errors.Is(err, task.ErrTaskNotExists) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/topvennie/sortifyr/internal/server/dto"
+	"github.com/topvennie/sortifyr/internal/server/service"
+)
+
+type Artist struct {
+	router fiber.Router
+
+	artist service.Artist
+}
+
+func NewArtist(router fiber.Router, service service.Service) *Artist {
+	api := &Artist{
+		router: router.Group("/artist"),
+		artist: *service.NewArtist(),
+	}
+
+	api.createRoutes()
+
+	return api
+}
+
+func (r *Artist) createRoutes() {
+	r.router.Get("/", r.getAll)
+	r.router.Get("/release", r.getReleases)
+}
+
+func (r *Artist) getAll(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	artists, err := r.artist.GetByUser(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(artists)
+}
+
+func (r *Artist) getReleases(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	limit := c.QueryInt("limit", 10)
+	page := c.QueryInt("page", 1)
+	if limit < 1 || page < 1 {
+		return fiber.ErrBadRequest
+	}
+
+	releases, err := r.artist.GetReleases(c.Context(), dto.ArtistReleaseFilter{
+		UserID: userID,
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(releases)
+}
@@ -36,6 +36,7 @@ func NewAuth(router fiber.Router, service service.Service) *Auth {
 			auth_spotify.ScopeUserReadRecentlyPlayed,
 			auth_spotify.ScopeUserLibraryRead,
 			auth_spotify.ScopeUserReadCurrentlyPlaying,
+			auth_spotify.ScopeUserFollowRead,
 		),
 	)
 
@@ -105,6 +106,9 @@ func (r *Auth) loginCallback(c *fiber.Ctx) error {
 		zap.S().Errorf("Failed to store spotify id in session %v", err)
 		return fiber.ErrInternalServerError
 	}
+	if err := trackSession(c, dtoUser.UID); err != nil {
+		zap.S().Error(err)
+	}
 
 	return c.Redirect(r.redirectURL)
 }
@@ -119,6 +123,11 @@ func (r *Auth) logout(c *fiber.Ctx) error {
 		zap.S().Errorf("Failed to get session %v", err)
 		return fiber.ErrInternalServerError
 	}
+	if uid, ok := session.Get("spotifyID").(string); ok {
+		if err := untrackSession(c, uid, session.ID()); err != nil {
+			zap.S().Error(err)
+		}
+	}
 	if err := session.Destroy(); err != nil {
 		zap.S().Errorf("Failed to destroy %v", err)
 		return fiber.ErrInternalServerError
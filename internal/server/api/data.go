@@ -0,0 +1,59 @@
+package api
+
+import (
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/topvennie/sortifyr/internal/server/service"
+)
+
+type Data struct {
+	router fiber.Router
+
+	data service.Data
+}
+
+func NewData(router fiber.Router, service service.Service) *Data {
+	api := &Data{
+		router: router.Group("/data"),
+		data:   *service.NewData(),
+	}
+
+	api.routes()
+
+	return api
+}
+
+func (d *Data) routes() {
+	d.router.Post("/export", d.export)
+	d.router.Get("/export", d.download)
+}
+
+func (d *Data) export(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	if err := d.data.Export(c.Context(), userID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (d *Data) download(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	file, err := d.data.GetExport(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	c.Attachment("sortifyr-export.zip")
+
+	return c.SendStream(io.NewSectionReader(file, 0, file.Size()), int(file.Size()))
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"strconv"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/topvennie/sortifyr/internal/server/dto"
 	"github.com/topvennie/sortifyr/internal/server/service"
@@ -25,7 +27,14 @@ func NewDirectory(router fiber.Router, service service.Service) *Directory {
 
 func (d *Directory) createRoutes() {
 	d.router.Get("/", d.getAll)
+	d.router.Put("/", d.create)
 	d.router.Post("/sync", d.sync)
+	d.router.Post("/:id/rename", d.rename)
+	d.router.Post("/:id/move", d.move)
+	d.router.Get("/:id/track", d.getTracks)
+	d.router.Get("/:id/duplicate", d.getDuplicates)
+	d.router.Post("/:id/duplicate", d.removeDuplicate)
+	d.router.Delete("/:id", d.delete)
 }
 
 func (d *Directory) getAll(c *fiber.Ctx) error {
@@ -42,6 +51,183 @@ func (d *Directory) getAll(c *fiber.Ctx) error {
 	return c.JSON(directories)
 }
 
+func (d *Directory) create(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	var directory dto.DirectorySave
+	if err := c.BodyParser(&directory); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err := dto.Validate.Struct(directory); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	newDirectory, err := d.directory.Create(c.Context(), userID, directory)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(newDirectory)
+}
+
+func (d *Directory) rename(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var rename dto.DirectoryRename
+	if err := c.BodyParser(&rename); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err := dto.Validate.Struct(rename); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := d.directory.Rename(c.Context(), userID, id, rename.Name); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (d *Directory) move(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var move dto.DirectoryMove
+	if err := c.BodyParser(&move); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := d.directory.Move(c.Context(), userID, id, move.ParentID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (d *Directory) delete(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := d.directory.Delete(c.Context(), userID, id, queryCascade(c)); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (d *Directory) getTracks(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	tracks, err := d.directory.GetTracks(c.Context(), userID, id, querySubdirectories(c))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(tracks)
+}
+
+func (d *Directory) getDuplicates(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	duplicates, err := d.directory.GetDuplicates(c.Context(), userID, id, querySubdirectories(c))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(duplicates)
+}
+
+func (d *Directory) removeDuplicate(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var remove dto.DirectoryDuplicateRemove
+	if err := c.BodyParser(&remove); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err := dto.Validate.Struct(remove); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := d.directory.RemoveDuplicate(c.Context(), userID, id, remove, querySubdirectories(c)); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// querySubdirectories returns if the playlists of all subdirectories should be used as well
+func querySubdirectories(c *fiber.Ctx) bool {
+	subdirectories := false
+	if v := c.Query("subdirectories"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			subdirectories = b
+		}
+	}
+
+	return subdirectories
+}
+
+// queryCascade returns if links depending on deleted directories should be deleted as well
+func queryCascade(c *fiber.Ctx) bool {
+	cascade := false
+	if v := c.Query("cascade"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cascade = b
+		}
+	}
+
+	return cascade
+}
+
 func (d *Directory) sync(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(int)
 	if !ok {
@@ -57,7 +243,7 @@ func (d *Directory) sync(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	newDirectories, err := d.directory.Sync(c.Context(), userID, directories)
+	newDirectories, err := d.directory.Sync(c.Context(), userID, directories, queryCascade(c))
 	if err != nil {
 		return err
 	}
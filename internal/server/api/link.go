@@ -25,6 +25,7 @@ func NewLink(router fiber.Router, service service.Service) *Link {
 
 func (l *Link) createRoutes() {
 	l.router.Get("/", l.getAll)
+	l.router.Get("/:id/preview", l.getPreview)
 	l.router.Post("/sync", l.sync)
 }
 
@@ -42,6 +43,25 @@ func (l *Link) getAll(c *fiber.Ctx) error {
 	return c.JSON(links)
 }
 
+func (l *Link) getPreview(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	linkID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	previews, err := l.link.GetPreview(c.Context(), userID, linkID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(previews)
+}
+
 func (l *Link) sync(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(int)
 	if !ok {
@@ -2,6 +2,7 @@ package api
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/topvennie/sortifyr/internal/server/dto"
 	"github.com/topvennie/sortifyr/internal/server/service"
 )
 
@@ -28,6 +29,8 @@ func (p *Playlist) routes() {
 	p.router.Get("/duplicate", p.getDuplicates)
 	p.router.Post("/duplicate", p.removeDuplicates)
 	p.router.Get("/unplayable", p.getUnplayables)
+	p.router.Post("/merge", p.merge)
+	p.router.Post("/split", p.split)
 }
 
 func (p *Playlist) getAll(c *fiber.Ctx) error {
@@ -100,3 +103,45 @@ func (p *Playlist) removeDuplicates(c *fiber.Ctx) error {
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+func (p *Playlist) merge(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	var merge dto.PlaylistMerge
+	if err := c.BodyParser(&merge); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err := dto.Validate.Struct(merge); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := p.playlist.Merge(c.Context(), userID, merge); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (p *Playlist) split(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	var split dto.PlaylistSplit
+	if err := c.BodyParser(&split); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err := dto.Validate.Struct(split); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := p.playlist.Split(c.Context(), userID, split); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/topvennie/sortifyr/internal/server/service"
+)
+
+type Recap struct {
+	router fiber.Router
+
+	recap service.Recap
+}
+
+func NewRecap(router fiber.Router, service service.Service) *Recap {
+	api := &Recap{
+		router: router.Group("/recap"),
+		recap:  *service.NewRecap(),
+	}
+
+	api.routes()
+
+	return api
+}
+
+func (r *Recap) routes() {
+	r.router.Get("/:year", r.get)
+	r.router.Get("/:year/:month", r.get)
+	r.router.Post("/:year/playlist", r.createPlaylist)
+	r.router.Post("/:year/:month/playlist", r.createPlaylist)
+}
+
+func (r *Recap) get(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	year, err := c.ParamsInt("year")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	month, err := c.ParamsInt("month", 0)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	refresh := false
+	if v := c.Query("refresh"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			refresh = b
+		}
+	}
+
+	recap, err := r.recap.Get(c.Context(), userID, year, month, refresh)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(recap)
+}
+
+func (r *Recap) createPlaylist(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	year, err := c.ParamsInt("year")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	month, err := c.ParamsInt("month", 0)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	playlist, err := r.recap.CreatePlaylist(c.Context(), userID, year, month)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(playlist)
+}
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"bytes"
+	"errors"
 	"io"
+	"mime/multipart"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/topvennie/sortifyr/internal/server/service"
@@ -26,6 +29,8 @@ func NewSetting(router fiber.Router, service service.Service) *Setting {
 
 func (s *Setting) routes() {
 	s.router.Post("/export", s.export)
+	s.router.Get("/import/unmatched", s.getImportUnmatched)
+	s.router.Post("/import/:source", s.importHistory)
 }
 
 func (s *Setting) export(c *fiber.Ctx) error {
@@ -34,28 +39,85 @@ func (s *Setting) export(c *fiber.Ctx) error {
 		return fiber.ErrUnauthorized
 	}
 
-	fileHeader, err := c.FormFile("zip")
+	// Exports can be large, stream the file instead of reading it in memory
+	file, err := formFileStream(c, "zip")
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		return err
 	}
 
-	file, err := fileHeader.Open()
-	if err != nil {
-		return fiber.ErrInternalServerError
+	if c.QueryBool("dry_run") {
+		report, err := s.setting.ExportDryRun(c.Context(), userID, file)
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(report)
 	}
-	defer func() {
-		// nolint:errcheck // Unlucky if it fails
-		_ = file.Close()
-	}()
 
-	data, err := io.ReadAll(file)
+	if err := s.setting.Export(c.Context(), userID, file); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (s *Setting) importHistory(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	file, err := formFileStream(c, "file")
 	if err != nil {
-		return fiber.ErrInternalServerError
+		return err
 	}
 
-	if err := s.setting.Export(c.Context(), userID, data); err != nil {
+	if err := s.setting.Import(c.Context(), userID, c.Params("source"), file); err != nil {
 		return err
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+func (s *Setting) getImportUnmatched(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	unmatched, err := s.setting.GetImportUnmatched(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(unmatched)
+}
+
+// formFileStream returns the content of a multipart form file
+// Contrary to c.FormFile the body is read while the file is being consumed.
+func formFileStream(c *fiber.Ctx, name string) (io.Reader, error) {
+	boundary := string(c.Request().Header.MultipartFormBoundary())
+	if boundary == "" {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Expected a multipart form")
+	}
+
+	body := c.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(c.Body())
+	}
+
+	mr := multipart.NewReader(body, boundary)
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Missing form file "+name)
+		}
+		if err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+
+		if part.FormName() == name {
+			return part, nil
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/topvennie/sortifyr/internal/server/dto"
+	"github.com/topvennie/sortifyr/internal/server/service"
+)
+
+type Show struct {
+	router fiber.Router
+
+	show service.Show
+}
+
+func NewShow(router fiber.Router, service service.Service) *Show {
+	api := &Show{
+		router: router.Group("/show"),
+		show:   *service.NewShow(),
+	}
+
+	api.createRoutes()
+
+	return api
+}
+
+func (r *Show) createRoutes() {
+	r.router.Get("/", r.getAll)
+	r.router.Get("/history", r.getHistory)
+}
+
+func (r *Show) getAll(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	shows, err := r.show.GetByUser(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(shows)
+}
+
+func (r *Show) getHistory(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	showID := c.QueryInt("show_id")
+
+	var err error
+
+	startRaw := c.Query("start")
+	start := time.Time{}
+	if startRaw != "" {
+		start, err = time.Parse("2006-01-02T15:04:05.000Z", startRaw)
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+	}
+
+	endRaw := c.Query("end")
+	end := time.Time{}
+	if endRaw != "" {
+		end, err = time.Parse("2006-01-02T15:04:05.000Z", endRaw)
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+	}
+
+	limit := c.QueryInt("limit", 10)
+	page := c.QueryInt("page", 1)
+	if limit < 1 || page < 1 {
+		return fiber.ErrBadRequest
+	}
+
+	history, err := r.show.GetHistory(c.Context(), dto.EpisodeHistoryFilter{
+		UserID: userID,
+		ShowID: showID,
+		Start:  start,
+		End:    end,
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(history)
+}
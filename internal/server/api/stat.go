@@ -0,0 +1,208 @@
+package api
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/topvennie/sortifyr/internal/server/dto"
+	"github.com/topvennie/sortifyr/internal/server/service"
+)
+
+type Stat struct {
+	router fiber.Router
+
+	stat service.Stat
+}
+
+func NewStat(router fiber.Router, service service.Service) *Stat {
+	api := &Stat{
+		router: router.Group("/stat"),
+		stat:   *service.NewStat(),
+	}
+
+	api.routes()
+
+	return api
+}
+
+func (s *Stat) routes() {
+	s.router.Get("/summary", s.getSummary)
+	s.router.Get("/track", s.getTopTracks)
+	s.router.Get("/artist", s.getTopArtists)
+	s.router.Get("/album", s.getTopAlbums)
+	s.router.Get("/playlist", s.getTopPlaylists)
+	s.router.Get("/heatmap", s.getHeatmap)
+	s.router.Get("/daily", s.getDaily)
+}
+
+func (s *Stat) getSummary(c *fiber.Ctx) error {
+	filter, err := statFilter(c)
+	if err != nil {
+		return err
+	}
+
+	summary, err := s.stat.GetSummary(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(summary)
+}
+
+func (s *Stat) getTopTracks(c *fiber.Ctx) error {
+	filter, err := statFilter(c)
+	if err != nil {
+		return err
+	}
+
+	tracks, err := s.stat.GetTopTracks(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(tracks)
+}
+
+func (s *Stat) getTopArtists(c *fiber.Ctx) error {
+	filter, err := statFilter(c)
+	if err != nil {
+		return err
+	}
+
+	artists, err := s.stat.GetTopArtists(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(artists)
+}
+
+func (s *Stat) getTopAlbums(c *fiber.Ctx) error {
+	filter, err := statFilter(c)
+	if err != nil {
+		return err
+	}
+
+	albums, err := s.stat.GetTopAlbums(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(albums)
+}
+
+func (s *Stat) getTopPlaylists(c *fiber.Ctx) error {
+	filter, err := statFilter(c)
+	if err != nil {
+		return err
+	}
+
+	playlists, err := s.stat.GetTopPlaylists(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(playlists)
+}
+
+func (s *Stat) getHeatmap(c *fiber.Ctx) error {
+	filter, err := statRollupFilter(c)
+	if err != nil {
+		return err
+	}
+
+	cells, err := s.stat.GetHeatmap(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(cells)
+}
+
+func (s *Stat) getDaily(c *fiber.Ctx) error {
+	filter, err := statRollupFilter(c)
+	if err != nil {
+		return err
+	}
+
+	days, err := s.stat.GetDaily(c.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(days)
+}
+
+// statRollupFilter parses the date range and the track, playlist and artist query parameters
+// The dates are in the user's timezone
+func statRollupFilter(c *fiber.Ctx) (dto.StatRollupFilter, error) {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return dto.StatRollupFilter{}, fiber.ErrUnauthorized
+	}
+
+	var err error
+
+	start := time.Time{}
+	if startRaw := c.Query("start"); startRaw != "" {
+		start, err = time.Parse(time.DateOnly, startRaw)
+		if err != nil {
+			return dto.StatRollupFilter{}, fiber.ErrBadRequest
+		}
+	}
+
+	end := time.Time{}
+	if endRaw := c.Query("end"); endRaw != "" {
+		end, err = time.Parse(time.DateOnly, endRaw)
+		if err != nil {
+			return dto.StatRollupFilter{}, fiber.ErrBadRequest
+		}
+	}
+
+	return dto.StatRollupFilter{
+		UserID:     userID,
+		Start:      start,
+		End:        end,
+		TrackID:    c.QueryInt("track_id"),
+		PlaylistID: c.QueryInt("playlist_id"),
+		ArtistID:   c.QueryInt("artist_id"),
+	}, nil
+}
+
+// statFilter parses the period and limit query parameters
+func statFilter(c *fiber.Ctx) (dto.StatFilter, error) {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return dto.StatFilter{}, fiber.ErrUnauthorized
+	}
+
+	var err error
+
+	start := time.Time{}
+	if startRaw := c.Query("start"); startRaw != "" {
+		start, err = time.Parse("2006-01-02T15:04:05.000Z", startRaw)
+		if err != nil {
+			return dto.StatFilter{}, fiber.ErrBadRequest
+		}
+	}
+
+	end := time.Time{}
+	if endRaw := c.Query("end"); endRaw != "" {
+		end, err = time.Parse("2006-01-02T15:04:05.000Z", endRaw)
+		if err != nil {
+			return dto.StatFilter{}, fiber.ErrBadRequest
+		}
+	}
+
+	limit := c.QueryInt("limit", 10)
+	if limit < 1 {
+		return dto.StatFilter{}, fiber.ErrBadRequest
+	}
+
+	return dto.StatFilter{
+		UserID: userID,
+		Start:  start,
+		End:    end,
+		Limit:  limit,
+	}, nil
+}
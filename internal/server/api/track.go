@@ -29,6 +29,7 @@ func NewTrack(router fiber.Router, service service.Service) *Track {
 func (r *Track) createRoutes() {
 	r.router.Get("/ids", r.getAllByID)
 	r.router.Get("/history", r.getHistory)
+	r.router.Get("/sessions", r.getSessions)
 	r.router.Get("/added", r.getAdded)
 	r.router.Get("/deleted", r.getDeleted)
 }
@@ -90,12 +91,13 @@ func (r *Track) getHistory(c *fiber.Ctx) error {
 	}
 
 	history, err := r.track.GetHistory(c.Context(), dto.HistoryFilter{
-		UserID:  userID,
-		Skipped: skipped,
-		Start:   start,
-		End:     end,
-		Limit:   limit,
-		Offset:  (page - 1) * limit,
+		UserID:    userID,
+		Skipped:   skipped,
+		Start:     start,
+		End:       end,
+		SessionID: c.QueryInt("session_id"),
+		Limit:     limit,
+		Offset:    (page - 1) * limit,
 	})
 	if err != nil {
 		return err
@@ -104,6 +106,52 @@ func (r *Track) getHistory(c *fiber.Ctx) error {
 	return c.JSON(history)
 }
 
+func (r *Track) getSessions(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	var err error
+
+	startRaw := c.Query("start")
+	start := time.Time{}
+	if startRaw != "" {
+		start, err = time.Parse("2006-01-02T15:04:05.000Z", startRaw)
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+	}
+
+	endRaw := c.Query("end")
+	end := time.Time{}
+	if endRaw != "" {
+		end, err = time.Parse("2006-01-02T15:04:05.000Z", endRaw)
+		if err != nil {
+			return fiber.ErrBadRequest
+		}
+	}
+
+	limit := c.QueryInt("limit", 10)
+	page := c.QueryInt("page", 1)
+	if limit < 1 || page < 1 {
+		return fiber.ErrBadRequest
+	}
+
+	sessions, err := r.track.GetSessions(c.Context(), dto.HistorySessionFilter{
+		UserID: userID,
+		Start:  start,
+		End:    end,
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(sessions)
+}
+
 func (r *Track) getAdded(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(int)
 	if !ok {
@@ -111,6 +159,7 @@ func (r *Track) getAdded(c *fiber.Ctx) error {
 	}
 
 	playlistID := c.QueryInt("playlist_id")
+	liked := c.QueryBool("liked")
 
 	limit := c.QueryInt("limit", 10)
 	page := c.QueryInt("page", 1)
@@ -121,6 +170,7 @@ func (r *Track) getAdded(c *fiber.Ctx) error {
 	tracks, err := r.track.GetAdded(c.Context(), dto.TrackFilter{
 		UserID:     userID,
 		PlaylistID: playlistID,
+		Liked:      liked,
 		Limit:      limit,
 		Offset:     (page - 1) * limit,
 	})
@@ -138,6 +188,7 @@ func (r *Track) getDeleted(c *fiber.Ctx) error {
 	}
 
 	playlistID := c.QueryInt("playlist_id")
+	liked := c.QueryBool("liked")
 
 	limit := c.QueryInt("limit", 10)
 	page := c.QueryInt("page", 1)
@@ -148,6 +199,7 @@ func (r *Track) getDeleted(c *fiber.Ctx) error {
 	tracks, err := r.track.GetDeleted(c.Context(), dto.TrackFilter{
 		UserID:     userID,
 		PlaylistID: playlistID,
+		Liked:      liked,
 		Limit:      limit,
 		Offset:     (page - 1) * limit,
 	})
@@ -2,7 +2,10 @@ package api
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/shareed2k/goth_fiber"
+	"github.com/topvennie/sortifyr/internal/server/dto"
 	"github.com/topvennie/sortifyr/internal/server/service"
+	"go.uber.org/zap"
 )
 
 type User struct {
@@ -24,6 +27,8 @@ func NewUser(router fiber.Router, service service.Service) *User {
 
 func (u *User) routes() {
 	u.router.Get("/me", u.getMe)
+	u.router.Post("/me/timezone", u.updateTimezone)
+	u.router.Delete("/me", u.deleteMe)
 }
 
 func (u *User) getMe(c *fiber.Ctx) error {
@@ -39,3 +44,48 @@ func (u *User) getMe(c *fiber.Ctx) error {
 
 	return c.JSON(user)
 }
+
+func (u *User) updateTimezone(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	var timezone dto.UserTimezone
+	if err := c.BodyParser(&timezone); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err := dto.Validate.Struct(timezone); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := u.user.UpdateTimezone(c.Context(), userID, timezone.Timezone); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (u *User) deleteMe(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(int)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+	spotifyID, ok := c.Locals("spotifyID").(string)
+	if !ok {
+		return fiber.ErrUnauthorized
+	}
+
+	if err := u.user.Delete(c.Context(), userID); err != nil {
+		return err
+	}
+
+	if err := goth_fiber.Logout(c); err != nil {
+		zap.S().Errorf("Failed to logout %v", err)
+	}
+	if err := destroySessions(c, spotifyID); err != nil {
+		zap.S().Errorf("Failed to destroy sessions %v", err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
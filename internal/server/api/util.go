@@ -4,10 +4,12 @@ package api
 
 import (
 	"crypto/md5"
+	"errors"
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/shareed2k/goth_fiber"
+	"github.com/topvennie/sortifyr/pkg/redis"
 )
 
 const (
@@ -25,6 +27,59 @@ func storeInSession(ctx *fiber.Ctx, key string, value any) error {
 	return session.Save()
 }
 
+func sessionsKey(uid string) string {
+	return uid + ":sessions"
+}
+
+// trackSession remembers the current session of a user
+// It allows destroySessions to log the user out everywhere
+func trackSession(c *fiber.Ctx, uid string) error {
+	session, err := goth_fiber.SessionStore.Get(c)
+	if err != nil {
+		return fmt.Errorf("failed to get session %w", err)
+	}
+
+	if _, err := redis.C.SAdd(c.Context(), sessionsKey(uid), session.ID()).Result(); err != nil {
+		return fmt.Errorf("failed to track session %w", err)
+	}
+
+	return nil
+}
+
+// untrackSession forgets a session that was destroyed
+func untrackSession(c *fiber.Ctx, uid, id string) error {
+	if _, err := redis.C.SRem(c.Context(), sessionsKey(uid), id).Result(); err != nil {
+		return fmt.Errorf("failed to untrack session %w", err)
+	}
+
+	return nil
+}
+
+// destroySessions removes the current and all tracked sessions of a user
+func destroySessions(c *fiber.Ctx, uid string) error {
+	ids, err := redis.C.SMembers(c.Context(), sessionsKey(uid)).Result()
+	if err != nil && !errors.Is(err, redis.ErrNil) {
+		return fmt.Errorf("failed to get sessions %w", err)
+	}
+
+	for _, id := range ids {
+		if err := goth_fiber.SessionStore.Storage.Delete(id); err != nil {
+			return fmt.Errorf("failed to delete session %w", err)
+		}
+	}
+
+	if _, err := redis.C.Del(c.Context(), sessionsKey(uid)).Result(); err != nil {
+		return fmt.Errorf("failed to delete tracked sessions %w", err)
+	}
+
+	session, err := goth_fiber.SessionStore.Get(c)
+	if err != nil {
+		return fmt.Errorf("failed to get session %w", err)
+	}
+
+	return session.Destroy()
+}
+
 func sendCached(c *fiber.Ctx, img []byte) error {
 	etag := fmt.Sprintf(`"%x"`, md5.Sum(img))
 
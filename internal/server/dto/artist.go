@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/topvennie/sortifyr/internal/database/model"
+)
+
+type Artist struct {
+	ID        int    `json:"id"`
+	SpotifyID string `json:"spotify_id"`
+	Name      string `json:"name"`
+}
+
+func ArtistDTO(a *model.Artist) Artist {
+	return Artist{
+		ID:        a.ID,
+		SpotifyID: a.SpotifyID,
+		Name:      a.Name,
+	}
+}
+
+type Album struct {
+	ID          int    `json:"id"`
+	SpotifyID   string `json:"spotify_id"`
+	Name        string `json:"name"`
+	TrackAmount int    `json:"track_amount"`
+}
+
+func AlbumDTO(a *model.Album) Album {
+	return Album{
+		ID:          a.ID,
+		SpotifyID:   a.SpotifyID,
+		Name:        a.Name,
+		TrackAmount: a.TrackAmount,
+	}
+}
+
+type ArtistRelease struct {
+	ID          int       `json:"id"`
+	Artist      Artist    `json:"artist"`
+	Album       Album     `json:"album"`
+	AlbumType   string    `json:"album_type"`
+	ReleaseDate time.Time `json:"release_date"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func ArtistReleaseDTO(a *model.ArtistRelease) ArtistRelease {
+	return ArtistRelease{
+		ID:          a.ID,
+		Artist:      ArtistDTO(&a.Artist),
+		Album:       AlbumDTO(&a.Album),
+		AlbumType:   a.AlbumType,
+		ReleaseDate: a.ReleaseDate,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+type ArtistReleaseFilter struct {
+	UserID int
+	Limit  int
+	Offset int
+}
+
+func (a ArtistReleaseFilter) ToModel() *model.ArtistReleaseFilter {
+	return &model.ArtistReleaseFilter{
+		UserID: a.UserID,
+		Limit:  a.Limit,
+		Offset: a.Offset,
+	}
+}
@@ -0,0 +1,117 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/topvennie/sortifyr/internal/database/model"
+	"github.com/topvennie/sortifyr/pkg/utils"
+)
+
+// Data export
+// Spotify ids are included so everything can be matched again outside of sortifyr
+
+type DataHistory struct {
+	PlayedAt       time.Time           `json:"played_at"`
+	TrackSpotifyID string              `json:"track_spotify_id"`
+	TrackName      string              `json:"track_name"`
+	Artists        []string            `json:"artists"`
+	DurationMs     int                 `json:"duration_ms"`
+	Skipped        *bool               `json:"skipped"`
+	PlaylistID     int                 `json:"playlist_id,omitzero"`
+	Source         model.HistorySource `json:"source"`
+	ReasonStart    string              `json:"reason_start,omitzero"`
+	ReasonEnd      string              `json:"reason_end,omitzero"`
+	Shuffle        *bool               `json:"shuffle,omitempty"`
+	Offline        *bool               `json:"offline,omitempty"`
+	Platform       string              `json:"platform,omitzero"`
+	ConnCountry    string              `json:"conn_country,omitzero"`
+}
+
+func DataHistoryDTO(h *model.History) DataHistory {
+	return DataHistory{
+		PlayedAt:       h.PlayedAt,
+		TrackSpotifyID: h.Track.SpotifyID,
+		TrackName:      h.Track.Name,
+		Artists:        utils.SliceMap(h.Track.Artists, func(a model.Artist) string { return a.Name }),
+		DurationMs:     h.Track.DurationMs,
+		Skipped:        h.Skipped,
+		PlaylistID:     h.PlaylistID,
+		Source:         h.Source,
+		ReasonStart:    h.ReasonStart,
+		ReasonEnd:      h.ReasonEnd,
+		Shuffle:        h.Shuffle,
+		Offline:        h.Offline,
+		Platform:       h.Platform,
+		ConnCountry:    h.ConnCountry,
+	}
+}
+
+type DataEpisodeHistory struct {
+	PlayedAt         time.Time           `json:"played_at"`
+	EpisodeSpotifyID string              `json:"episode_spotify_id"`
+	EpisodeName      string              `json:"episode_name"`
+	ShowSpotifyID    string              `json:"show_spotify_id"`
+	ShowName         string              `json:"show_name"`
+	PositionMs       int                 `json:"position_ms"`
+	Source           model.HistorySource `json:"source"`
+	ReasonStart      string              `json:"reason_start,omitzero"`
+	ReasonEnd        string              `json:"reason_end,omitzero"`
+	Shuffle          *bool               `json:"shuffle,omitempty"`
+	Offline          *bool               `json:"offline,omitempty"`
+	Platform         string              `json:"platform,omitzero"`
+	ConnCountry      string              `json:"conn_country,omitzero"`
+}
+
+func DataEpisodeHistoryDTO(h *model.EpisodeHistory) DataEpisodeHistory {
+	return DataEpisodeHistory{
+		PlayedAt:         h.PlayedAt,
+		EpisodeSpotifyID: h.Episode.SpotifyID,
+		EpisodeName:      h.Episode.Name,
+		ShowSpotifyID:    h.Episode.Show.SpotifyID,
+		ShowName:         h.Episode.Show.Name,
+		PositionMs:       h.PositionMs,
+		Source:           h.Source,
+		ReasonStart:      h.ReasonStart,
+		ReasonEnd:        h.ReasonEnd,
+		Shuffle:          h.Shuffle,
+		Offline:          h.Offline,
+		Platform:         h.Platform,
+		ConnCountry:      h.ConnCountry,
+	}
+}
+
+type DataPlaylistTrack struct {
+	SpotifyID string    `json:"spotify_id"`
+	Name      string    `json:"name"`
+	AddedAt   time.Time `json:"added_at"`
+	RemovedAt time.Time `json:"removed_at,omitzero"`
+}
+
+type DataPlaylist struct {
+	ID            int                 `json:"id"`
+	SpotifyID     string              `json:"spotify_id"`
+	Name          string              `json:"name"`
+	Description   string              `json:"description,omitzero"`
+	Owner         string              `json:"owner"`
+	Public        *bool               `json:"public"`
+	Collaborative *bool               `json:"collaborative"`
+	Tracks        []DataPlaylistTrack `json:"tracks"`
+}
+
+func DataPlaylistDTO(p *model.Playlist, tracks []DataPlaylistTrack) DataPlaylist {
+	owner := p.Owner.Name
+	if p.Owner.DisplayName != "" {
+		owner = p.Owner.DisplayName
+	}
+
+	return DataPlaylist{
+		ID:            p.ID,
+		SpotifyID:     p.SpotifyID,
+		Name:          p.Name,
+		Description:   p.Description,
+		Owner:         owner,
+		Public:        p.Public,
+		Collaborative: p.Collaborative,
+		Tracks:        tracks,
+	}
+}
@@ -5,11 +5,57 @@ import (
 	"github.com/topvennie/sortifyr/pkg/utils"
 )
 
+type DirectoryRules struct {
+	Owned          *bool  `json:"owned,omitempty"`
+	NameRegex      string `json:"name_regex,omitzero"`
+	Collaborative  *bool  `json:"collaborative,omitempty"`
+	Public         *bool  `json:"public,omitempty"`
+	TrackAmountMin int    `json:"track_amount_min,omitzero" validate:"min=0"`
+	TrackAmountMax int    `json:"track_amount_max,omitzero" validate:"min=0"`
+	ArtistID       int    `json:"artist_id,omitzero"`
+	NotPlayedDays  int    `json:"not_played_days,omitzero" validate:"min=0"`
+}
+
+func DirectoryRulesDTO(r *model.DirectoryRules) *DirectoryRules {
+	if r == nil {
+		return nil
+	}
+
+	return &DirectoryRules{
+		Owned:          r.Owned,
+		NameRegex:      r.NameRegex,
+		Collaborative:  r.Collaborative,
+		Public:         r.Public,
+		TrackAmountMin: r.TrackAmountMin,
+		TrackAmountMax: r.TrackAmountMax,
+		ArtistID:       r.ArtistID,
+		NotPlayedDays:  r.NotPlayedDays,
+	}
+}
+
+func (r *DirectoryRules) ToModel() *model.DirectoryRules {
+	if r == nil {
+		return nil
+	}
+
+	return &model.DirectoryRules{
+		Owned:          r.Owned,
+		NameRegex:      r.NameRegex,
+		Collaborative:  r.Collaborative,
+		Public:         r.Public,
+		TrackAmountMin: r.TrackAmountMin,
+		TrackAmountMax: r.TrackAmountMax,
+		ArtistID:       r.ArtistID,
+		NotPlayedDays:  r.NotPlayedDays,
+	}
+}
+
 type Directory struct {
-	ID        int         `json:"id"`
-	Name      string      `json:"name" validate:"required"`
-	Children  []Directory `json:"children,omitzero"`
-	Playlists []Playlist  `json:"playlists" validate:"required"`
+	ID        int             `json:"id"`
+	Name      string          `json:"name" validate:"required"`
+	Children  []Directory     `json:"children,omitzero"`
+	Playlists []Playlist      `json:"playlists" validate:"required"`
+	Rules     *DirectoryRules `json:"rules,omitempty"` // Playlists are ignored if set
 }
 
 func DirectoryDTO(d *model.Directory, models []*model.Directory) Directory {
@@ -25,6 +71,7 @@ func DirectoryDTO(d *model.Directory, models []*model.Directory) Directory {
 		Name:      d.Name,
 		Children:  children,
 		Playlists: utils.SliceMap(d.Playlists, func(p model.Playlist) Playlist { return PlaylistDTO(&p, &p.Owner) }),
+		Rules:     DirectoryRulesDTO(d.Rules),
 	}
 }
 
@@ -34,6 +81,51 @@ func (d Directory) ToModel(userID, parentID int) *model.Directory {
 		UserID:    userID,
 		Name:      d.Name,
 		ParentID:  parentID,
+		Rules:     d.Rules.ToModel(),
+		Playlists: utils.SliceMap(d.Playlists, func(p Playlist) model.Playlist { return *p.ToModel() }),
+	}
+}
+
+type DirectorySave struct {
+	Name      string          `json:"name" validate:"required"`
+	ParentID  int             `json:"parent_id,omitzero"`
+	Playlists []Playlist      `json:"playlists"`
+	Rules     *DirectoryRules `json:"rules,omitempty"` // Playlists are ignored if set
+}
+
+func (d DirectorySave) ToModel(userID int) *model.Directory {
+	return &model.Directory{
+		UserID:    userID,
+		Name:      d.Name,
+		ParentID:  d.ParentID,
+		Rules:     d.Rules.ToModel(),
 		Playlists: utils.SliceMap(d.Playlists, func(p Playlist) model.Playlist { return *p.ToModel() }),
 	}
 }
+
+type DirectoryRename struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type DirectoryMove struct {
+	ParentID int `json:"parent_id"`
+}
+
+// DirectoryDuplicate is a track that appears in multiple playlists of a directory
+type DirectoryDuplicate struct {
+	Track     Track      `json:"track"`
+	Playlists []Playlist `json:"playlists"`
+}
+
+func DirectoryDuplicateDTO(t *model.Track, playlists []model.Playlist) DirectoryDuplicate {
+	return DirectoryDuplicate{
+		Track:     TrackDTO(t),
+		Playlists: utils.SliceMap(playlists, func(p model.Playlist) Playlist { return PlaylistDTO(&p, &p.Owner) }),
+	}
+}
+
+// DirectoryDuplicateRemove removes a track from every playlist in the directory except one
+type DirectoryDuplicateRemove struct {
+	TrackID        int `json:"track_id" validate:"required"`
+	KeepPlaylistID int `json:"keep_playlist_id" validate:"required"`
+}
@@ -92,9 +92,11 @@ func (g GeneratorPresetOldTopParams) ToModel() *model.GeneratorPresetOldTopParam
 }
 
 type GeneratorParams struct {
-	TrackAmount         int   `json:"track_amount" validate:"min=0"`
-	ExcludedPlaylistIDs []int `json:"excluded_playlist_ids,omitzero"`
-	ExcludedTrackIDs    []int `json:"excluded_track_ids,omitzero"`
+	TrackAmount          int   `json:"track_amount" validate:"min=0"`
+	ExcludedPlaylistIDs  []int `json:"excluded_playlist_ids,omitzero"`
+	ExcludedDirectoryIDs []int `json:"excluded_directory_ids,omitzero"`
+	ExcludedTrackIDs     []int `json:"excluded_track_ids,omitzero"`
+	ExcludeLiked         bool  `json:"exclude_liked,omitzero"`
 
 	Preset model.GeneratorPreset `json:"preset" validate:"required"`
 
@@ -104,12 +106,14 @@ type GeneratorParams struct {
 
 func generatorParamsDTO(params model.GeneratorParams) GeneratorParams {
 	return GeneratorParams{
-		TrackAmount:         params.TrackAmount,
-		ExcludedPlaylistIDs: params.ExcludedPlaylistIDs,
-		ExcludedTrackIDs:    params.ExcludedTrackIDs,
-		Preset:              params.Preset,
-		ParamsTop:           generatorPresetTopParamsDTO(params.ParamsTop),
-		ParamsOldTop:        generatorPresetOldTopParamsDTO(params.ParamsOldTop),
+		TrackAmount:          params.TrackAmount,
+		ExcludedPlaylistIDs:  params.ExcludedPlaylistIDs,
+		ExcludedDirectoryIDs: params.ExcludedDirectoryIDs,
+		ExcludedTrackIDs:     params.ExcludedTrackIDs,
+		ExcludeLiked:         params.ExcludeLiked,
+		Preset:               params.Preset,
+		ParamsTop:            generatorPresetTopParamsDTO(params.ParamsTop),
+		ParamsOldTop:         generatorPresetOldTopParamsDTO(params.ParamsOldTop),
 	}
 }
 
@@ -123,12 +127,14 @@ func (g GeneratorParams) ToModel() model.GeneratorParams {
 		paramsOldTop = g.ParamsOldTop.ToModel()
 	}
 	return model.GeneratorParams{
-		TrackAmount:         g.TrackAmount,
-		ExcludedPlaylistIDs: g.ExcludedPlaylistIDs,
-		ExcludedTrackIDs:    g.ExcludedTrackIDs,
-		Preset:              g.Preset,
-		ParamsTop:           paramsTop,
-		ParamsOldTop:        paramsOldTop,
+		TrackAmount:          g.TrackAmount,
+		ExcludedPlaylistIDs:  g.ExcludedPlaylistIDs,
+		ExcludedDirectoryIDs: g.ExcludedDirectoryIDs,
+		ExcludedTrackIDs:     g.ExcludedTrackIDs,
+		ExcludeLiked:         g.ExcludeLiked,
+		Preset:               g.Preset,
+		ParamsTop:            paramsTop,
+		ParamsOldTop:         paramsOldTop,
 	}
 }
 
@@ -1,31 +1,115 @@
 package dto
 
-import "github.com/topvennie/sortifyr/internal/database/model"
+import (
+	"time"
+
+	"github.com/topvennie/sortifyr/internal/database/model"
+	"github.com/topvennie/sortifyr/pkg/utils"
+)
+
+type LinkRun struct {
+	RunAt         time.Time `json:"run_at"`
+	TracksAdded   int       `json:"tracks_added"`
+	TracksDeleted int       `json:"tracks_deleted"`
+	Error         string    `json:"error,omitempty"`
+}
+
+type LinkFilter struct {
+	AddedAfter        time.Time `json:"added_after,omitzero"`
+	ArtistIDs         []int     `json:"artist_ids,omitempty"`
+	ExcludeExplicit   bool      `json:"exclude_explicit"`
+	ExcludeUnplayable bool      `json:"exclude_unplayable"`
+	MinPlays          int       `json:"min_plays" validate:"min=0"`
+}
+
+func LinkFilterDTO(f model.LinkFilter) LinkFilter {
+	return LinkFilter{
+		AddedAfter:        f.AddedAfter,
+		ArtistIDs:         f.ArtistIDs,
+		ExcludeExplicit:   f.ExcludeExplicit,
+		ExcludeUnplayable: f.ExcludeUnplayable,
+		MinPlays:          f.MinPlays,
+	}
+}
+
+func (f *LinkFilter) ToModel() model.LinkFilter {
+	return model.LinkFilter{
+		AddedAfter:        f.AddedAfter,
+		ArtistIDs:         f.ArtistIDs,
+		ExcludeExplicit:   f.ExcludeExplicit,
+		ExcludeUnplayable: f.ExcludeUnplayable,
+		MinPlays:          f.MinPlays,
+	}
+}
 
 type Link struct {
-	ID                int `json:"id"`
-	SourceDirectoryID int `json:"source_directory_id,omitzero"`
-	SourcePlaylistID  int `json:"source_playlist_id,omitzero"`
-	TargetDirectoryID int `json:"target_directory_id,omitzero"`
-	TargetPlaylistID  int `json:"target_playlist_id,omitzero"`
+	ID                    int            `json:"id"`
+	SourceDirectoryID     int            `json:"source_directory_id,omitzero"`
+	SourcePlaylistID      int            `json:"source_playlist_id,omitzero"`
+	SourceLiked           bool           `json:"source_liked,omitzero"`
+	TargetDirectoryID     int            `json:"target_directory_id,omitzero"`
+	TargetPlaylistID      int            `json:"target_playlist_id,omitzero"`
+	Mode                  model.LinkMode `json:"mode" validate:"omitempty,oneof=append mirror bidirectional"`
+	Filter                LinkFilter     `json:"filter"`
+	IncludeSubdirectories bool           `json:"include_subdirectories"`
+	LastRun               *LinkRun       `json:"last_run,omitempty"`
 }
 
 func LinkDTO(l *model.Link) Link {
+	var lastRun *LinkRun
+	if !l.LastRunAt.IsZero() {
+		lastRun = &LinkRun{
+			RunAt:         l.LastRunAt,
+			TracksAdded:   l.LastRunAdded,
+			TracksDeleted: l.LastRunDeleted,
+			Error:         l.LastRunError,
+		}
+	}
+
 	return Link{
-		ID:                l.ID,
-		SourceDirectoryID: l.SourceDirectoryID,
-		SourcePlaylistID:  l.SourcePlaylistID,
-		TargetDirectoryID: l.TargetDirectoryID,
-		TargetPlaylistID:  l.TargetPlaylistID,
+		ID:                    l.ID,
+		SourceDirectoryID:     l.SourceDirectoryID,
+		SourcePlaylistID:      l.SourcePlaylistID,
+		SourceLiked:           l.SourceUserID != 0,
+		TargetDirectoryID:     l.TargetDirectoryID,
+		TargetPlaylistID:      l.TargetPlaylistID,
+		Mode:                  l.Mode,
+		Filter:                LinkFilterDTO(l.Filter),
+		IncludeSubdirectories: l.IncludeSubdirectories,
+		LastRun:               lastRun,
 	}
 }
 
+// ToModel converts the dto to a model
+// The source user id is not set as it requires the user
 func (l *Link) ToModel() *model.Link {
+	mode := l.Mode
+	if mode == "" {
+		mode = model.LinkAppend
+	}
+
 	return &model.Link{
-		ID:                l.ID,
-		SourceDirectoryID: l.SourceDirectoryID,
-		SourcePlaylistID:  l.SourcePlaylistID,
-		TargetDirectoryID: l.TargetDirectoryID,
-		TargetPlaylistID:  l.TargetPlaylistID,
+		ID:                    l.ID,
+		SourceDirectoryID:     l.SourceDirectoryID,
+		SourcePlaylistID:      l.SourcePlaylistID,
+		TargetDirectoryID:     l.TargetDirectoryID,
+		TargetPlaylistID:      l.TargetPlaylistID,
+		Mode:                  mode,
+		Filter:                l.Filter.ToModel(),
+		IncludeSubdirectories: l.IncludeSubdirectories,
+	}
+}
+
+type LinkPreview struct {
+	Playlist      Playlist `json:"playlist"`
+	TracksAdded   []Track  `json:"tracks_added"`
+	TracksDeleted []Track  `json:"tracks_deleted"`
+}
+
+func LinkPreviewDTO(c model.LinkChange) LinkPreview {
+	return LinkPreview{
+		Playlist:      PlaylistDTO(&c.Playlist, &c.Playlist.Owner),
+		TracksAdded:   utils.SliceMap(c.TracksAdded, func(t model.Track) Track { return TrackDTO(&t) }),
+		TracksDeleted: utils.SliceMap(c.TracksDeleted, func(t model.Track) Track { return TrackDTO(&t) }),
 	}
 }
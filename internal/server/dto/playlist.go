@@ -47,10 +47,30 @@ func (p Playlist) ToModel() *model.Playlist {
 
 type PlaylistDuplicate struct {
 	Playlist
+	Liked      bool             `json:"liked,omitzero"`
 	Duplicates []TrackDuplicate `json:"duplicates"`
 }
 
 func PlaylistDuplicateDTO(playlist *model.Playlist, user *model.User, duplicates []model.Track) PlaylistDuplicate {
+	return PlaylistDuplicate{
+		Playlist:   PlaylistDTO(playlist, user),
+		Duplicates: trackDuplicatesDTO(duplicates),
+	}
+}
+
+// PlaylistDuplicateLikedDTO represents the liked songs that are also in the user's playlists
+// duplicates contains a track once for every playlist it's in
+func PlaylistDuplicateLikedDTO(user *model.User, duplicates []model.Track) PlaylistDuplicate {
+	liked := utils.SliceUniqueFunc(duplicates, func(t model.Track) int { return t.ID })
+
+	return PlaylistDuplicate{
+		Playlist:   PlaylistDTO(&model.Playlist{Name: "Liked Songs"}, user),
+		Liked:      true,
+		Duplicates: trackDuplicatesDTO(append(liked, duplicates...)),
+	}
+}
+
+func trackDuplicatesDTO(duplicates []model.Track) []TrackDuplicate {
 	type trackAmount struct {
 		track  model.Track
 		amount int
@@ -67,10 +87,7 @@ func PlaylistDuplicateDTO(playlist *model.Playlist, user *model.User, duplicates
 		duplicateMap[duplicates[i].ID] = d
 	}
 
-	return PlaylistDuplicate{
-		Playlist:   PlaylistDTO(playlist, user),
-		Duplicates: utils.SliceMap(utils.MapValues(duplicateMap), func(t trackAmount) TrackDuplicate { return TrackDuplicateDTO(&t.track, t.amount) }),
-	}
+	return utils.SliceMap(utils.MapValues(duplicateMap), func(t trackAmount) TrackDuplicate { return TrackDuplicateDTO(&t.track, t.amount) })
 }
 
 type PlaylistUnplayable struct {
@@ -84,3 +101,19 @@ func PlaylistUnplayableDTO(playlist *model.Playlist, user *model.User, unplayabl
 		Unplayables: utils.SliceMap(unplayables, func(t model.Track) Track { return TrackDTO(&t) }),
 	}
 }
+
+// PlaylistMerge merges playlists into a new playlist or into an existing one
+type PlaylistMerge struct {
+	PlaylistIDs      []int  `json:"playlist_ids" validate:"required,min=1,dive,required"`
+	TargetPlaylistID int    `json:"target_playlist_id,omitzero"`
+	Name             string `json:"name,omitzero" validate:"required_without=TargetPlaylistID"` // Name of the new playlist
+	Deduplicate      bool   `json:"deduplicate"`
+	DirectoryID      int    `json:"directory_id,omitzero"` // Directory to place the result in
+}
+
+// PlaylistSplit splits a playlist into new playlists by a key
+type PlaylistSplit struct {
+	PlaylistID  int                    `json:"playlist_id" validate:"required"`
+	Key         model.PlaylistSplitKey `json:"key" validate:"required,oneof=artist year decade"`
+	DirectoryID int                    `json:"directory_id,omitzero"` // Directory to place the results in
+}
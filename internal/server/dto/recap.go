@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/topvennie/sortifyr/internal/database/model"
+	"github.com/topvennie/sortifyr/pkg/utils"
+)
+
+type RecapItem struct {
+	ID        int    `json:"id"`
+	SpotifyID string `json:"spotify_id"`
+	Name      string `json:"name"`
+	Plays     int    `json:"plays"`
+}
+
+func RecapItemDTO(r model.RecapItem) RecapItem {
+	return RecapItem{
+		ID:        r.ID,
+		SpotifyID: r.SpotifyID,
+		Name:      r.Name,
+		Plays:     r.Plays,
+	}
+}
+
+type Recap struct {
+	Year          int         `json:"year"`
+	Month         int         `json:"month,omitzero"`
+	TopTracks     []RecapItem `json:"top_tracks"`
+	TopArtists    []RecapItem `json:"top_artists"`
+	TopAlbums     []RecapItem `json:"top_albums"`
+	Minutes       int         `json:"minutes"`
+	LongestStreak int         `json:"longest_streak"`
+	TopHour       int         `json:"top_hour"`
+	TopWeekday    string      `json:"top_weekday"`
+	Discovery     *RecapItem  `json:"discovery,omitempty"`
+	MostSkipped   *RecapItem  `json:"most_skipped,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+func RecapDTO(r *model.Recap) Recap {
+	var discovery *RecapItem
+	if r.Data.Discovery != nil {
+		d := RecapItemDTO(*r.Data.Discovery)
+		discovery = &d
+	}
+
+	var mostSkipped *RecapItem
+	if r.Data.MostSkipped != nil {
+		s := RecapItemDTO(*r.Data.MostSkipped)
+		mostSkipped = &s
+	}
+
+	return Recap{
+		Year:          r.Year,
+		Month:         r.Month,
+		TopTracks:     utils.SliceMap(r.Data.TopTracks, RecapItemDTO),
+		TopArtists:    utils.SliceMap(r.Data.TopArtists, RecapItemDTO),
+		TopAlbums:     utils.SliceMap(r.Data.TopAlbums, RecapItemDTO),
+		Minutes:       int(r.Data.Duration.Minutes()),
+		LongestStreak: r.Data.LongestStreak,
+		TopHour:       r.Data.TopHour,
+		TopWeekday:    r.Data.TopWeekday.String(),
+		Discovery:     discovery,
+		MostSkipped:   mostSkipped,
+		CreatedAt:     r.CreatedAt,
+	}
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+type ExportFile struct {
+	Name     string    `json:"name"`
+	Tracks   int       `json:"tracks"`
+	Episodes int       `json:"episodes"`
+	Ignored  int       `json:"ignored"`
+	Existing int       `json:"existing"`
+	Start    time.Time `json:"start,omitzero"`
+	End      time.Time `json:"end,omitzero"`
+}
+
+type ExportReport struct {
+	Files    []ExportFile `json:"files"`
+	Tracks   int          `json:"tracks"`
+	Episodes int          `json:"episodes"`
+	Ignored  int          `json:"ignored"`
+	Start    time.Time    `json:"start,omitzero"`
+	End      time.Time    `json:"end,omitzero"`
+	Existing int          `json:"existing"`
+}
+
+type ImportUnmatched struct {
+	Artist string `json:"artist"`
+	Title  string `json:"title"`
+	Album  string `json:"album"`
+	Plays  int    `json:"plays"`
+}
@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/topvennie/sortifyr/internal/database/model"
+)
+
+type Show struct {
+	ID            int    `json:"id"`
+	SpotifyID     string `json:"spotify_id"`
+	Name          string `json:"name"`
+	EpisodeAmount int    `json:"episode_amount"`
+}
+
+func ShowDTO(s *model.Show) Show {
+	return Show{
+		ID:            s.ID,
+		SpotifyID:     s.SpotifyID,
+		Name:          s.Name,
+		EpisodeAmount: s.EpisodeAmount,
+	}
+}
+
+type Episode struct {
+	ID         int    `json:"id"`
+	SpotifyID  string `json:"spotify_id"`
+	Name       string `json:"name"`
+	DurationMs int    `json:"duration_ms"`
+	Show       Show   `json:"show"`
+}
+
+func EpisodeDTO(e *model.Episode) Episode {
+	return Episode{
+		ID:         e.ID,
+		SpotifyID:  e.SpotifyID,
+		Name:       e.Name,
+		DurationMs: e.DurationMs,
+		Show:       ShowDTO(&e.Show),
+	}
+}
+
+type EpisodeHistory struct {
+	Episode
+
+	HistoryID  int       `json:"history_id"`
+	PlayedAt   time.Time `json:"played_at"`
+	PositionMs int       `json:"position_ms"`
+}
+
+func EpisodeHistoryDTO(h *model.EpisodeHistory) EpisodeHistory {
+	return EpisodeHistory{
+		Episode:    EpisodeDTO(&h.Episode),
+		HistoryID:  h.ID,
+		PlayedAt:   h.PlayedAt,
+		PositionMs: h.PositionMs,
+	}
+}
+
+type EpisodeHistoryFilter struct {
+	UserID int
+	ShowID int
+	Start  time.Time
+	End    time.Time
+	Limit  int
+	Offset int
+}
+
+func (e EpisodeHistoryFilter) ToModel() *model.EpisodeHistoryFilter {
+	return &model.EpisodeHistoryFilter{
+		UserID: e.UserID,
+		ShowID: e.ShowID,
+		Start:  e.Start,
+		End:    e.End,
+		Limit:  e.Limit,
+		Offset: e.Offset,
+	}
+}
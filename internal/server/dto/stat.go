@@ -0,0 +1,135 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/topvennie/sortifyr/internal/database/model"
+)
+
+type StatFilter struct {
+	UserID int
+	Start  time.Time
+	End    time.Time
+	Limit  int
+}
+
+func (s StatFilter) ToModel() *model.StatFilter {
+	return &model.StatFilter{
+		UserID: s.UserID,
+		Start:  s.Start,
+		End:    s.End,
+		Limit:  s.Limit,
+	}
+}
+
+type StatRollupFilter struct {
+	UserID     int
+	Start      time.Time
+	End        time.Time
+	TrackID    int
+	PlaylistID int
+	ArtistID   int
+}
+
+func (s StatRollupFilter) ToModel() *model.StatRollupFilter {
+	return &model.StatRollupFilter{
+		UserID:     s.UserID,
+		Start:      s.Start,
+		End:        s.End,
+		TrackID:    s.TrackID,
+		PlaylistID: s.PlaylistID,
+		ArtistID:   s.ArtistID,
+	}
+}
+
+type StatHeatmap struct {
+	Weekday int `json:"weekday"` // 0 is sunday
+	Hour    int `json:"hour"`
+	Plays   int `json:"plays"`
+}
+
+func StatHeatmapDTO(s *model.StatHeatmap) StatHeatmap {
+	return StatHeatmap{
+		Weekday: int(s.Weekday),
+		Hour:    s.Hour,
+		Plays:   s.Plays,
+	}
+}
+
+type StatDaily struct {
+	Day   string `json:"day"` // YYYY-MM-DD
+	Plays int    `json:"plays"`
+}
+
+func StatDailyDTO(s *model.StatDaily) StatDaily {
+	return StatDaily{
+		Day:   s.Day.Format(time.DateOnly),
+		Plays: s.Plays,
+	}
+}
+
+type StatSummary struct {
+	Plays          int     `json:"plays"`
+	Minutes        int     `json:"minutes"`
+	DistinctTracks int     `json:"distinct_tracks"`
+	Skips          int     `json:"skips"`
+	SkipRate       float64 `json:"skip_rate"`
+}
+
+func StatSummaryDTO(s *model.StatSummary) StatSummary {
+	return StatSummary{
+		Plays:          s.Plays,
+		Minutes:        int(s.Duration.Minutes()),
+		DistinctTracks: s.DistinctTracks,
+		Skips:          s.Skips,
+		SkipRate:       s.SkipRate(),
+	}
+}
+
+type StatTrack struct {
+	Track Track `json:"track"`
+	Plays int   `json:"plays"`
+}
+
+func StatTrackDTO(s *model.StatTrack) StatTrack {
+	return StatTrack{
+		Track: TrackDTO(&s.Track),
+		Plays: s.Plays,
+	}
+}
+
+type StatArtist struct {
+	Artist Artist `json:"artist"`
+	Plays  int    `json:"plays"`
+}
+
+func StatArtistDTO(s *model.StatArtist) StatArtist {
+	return StatArtist{
+		Artist: ArtistDTO(&s.Artist),
+		Plays:  s.Plays,
+	}
+}
+
+type StatAlbum struct {
+	Album Album `json:"album"`
+	Plays int   `json:"plays"`
+}
+
+func StatAlbumDTO(s *model.StatAlbum) StatAlbum {
+	return StatAlbum{
+		Album: AlbumDTO(&s.Album),
+		Plays: s.Plays,
+	}
+}
+
+type StatPlaylist struct {
+	Playlist Playlist `json:"playlist"`
+	Plays    int      `json:"plays"`
+}
+
+func StatPlaylistDTO(s *model.StatPlaylist) StatPlaylist {
+	return StatPlaylist{
+		Playlist: PlaylistDTO(&s.Playlist, &s.Playlist.Owner),
+		Plays:    s.Plays,
+	}
+}
@@ -34,6 +34,11 @@ func TaskHistoryDTO(task *model.Task) TaskHistory {
 	}
 }
 
+type TaskProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
 type Task struct {
 	TaskUID     string           `json:"uid"`
 	Name        string           `json:"name"`
@@ -45,9 +50,15 @@ type Task struct {
 	LastError   string           `json:"last_error,omitempty"`
 	Interval    *time.Duration   `json:"interval,omitzero"`
 	Recurring   bool             `json:"recurring"`
+	Progress    *TaskProgress    `json:"progress,omitempty"`
 }
 
 func TaskDTO(task task.Stat) Task {
+	var progress *TaskProgress
+	if task.Progress.Total > 0 {
+		progress = &TaskProgress{Done: task.Progress.Done, Total: task.Progress.Total}
+	}
+
 	return Task{
 		TaskUID:   task.TaskUID,
 		Name:      task.Name,
@@ -56,6 +67,7 @@ func TaskDTO(task task.Stat) Task {
 		LastRun:   &task.LastRun,
 		Interval:  &task.Interval,
 		Recurring: task.Recurring,
+		Progress:  progress,
 	}
 }
 
@@ -23,6 +23,7 @@ func TrackDTO(t *model.Track) Track {
 type TrackFilter struct {
 	UserID     int
 	PlaylistID int
+	Liked      bool
 	Limit      int
 	Offset     int
 }
@@ -31,6 +32,7 @@ func (t TrackFilter) ToModel() *model.TrackFilter {
 	return &model.TrackFilter{
 		UserID:     t.UserID,
 		PlaylistID: t.PlaylistID,
+		Liked:      t.Liked,
 		Limit:      t.Limit,
 		Offset:     t.Offset,
 	}
@@ -39,7 +41,8 @@ func (t TrackFilter) ToModel() *model.TrackFilter {
 type TrackAdded struct {
 	Track
 
-	Playlist  Playlist  `json:"playlist"`
+	Playlist  Playlist  `json:"playlist,omitzero"`
+	Liked     bool      `json:"liked,omitzero"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -51,10 +54,22 @@ func TrackAddedDTO(t *model.Track) TrackAdded {
 	}
 }
 
+// TrackAddedLikedDTO is used for tracks added to the user's liked songs
+// The created at time is the saved at time
+func TrackAddedLikedDTO(t *model.Track) TrackAdded {
+	return TrackAdded{
+		Track:     TrackDTO(t),
+		Liked:     true,
+		CreatedAt: t.CreatedAt,
+	}
+}
+
 type TrackDeleted struct {
 	Track
 
-	Playlist  Playlist  `json:"playlist"`
+	Playlist  Playlist  `json:"playlist,omitzero"`
+	Liked     bool      `json:"liked,omitzero"`
+	SavedAt   time.Time `json:"saved_at,omitzero"`
 	DeletedAt time.Time `json:"deleted_at"`
 }
 
@@ -66,6 +81,16 @@ func TrackDeletedDTO(t *model.Track) TrackDeleted {
 	}
 }
 
+// TrackDeletedLikedDTO is used for tracks removed from the user's liked songs
+func TrackDeletedLikedDTO(t *model.Track) TrackDeleted {
+	return TrackDeleted{
+		Track:     TrackDTO(t),
+		Liked:     true,
+		SavedAt:   t.CreatedAt,
+		DeletedAt: t.DeletedAt,
+	}
+}
+
 type TrackDuplicate struct {
 	Track
 
@@ -85,6 +110,7 @@ type History struct {
 	HistoryID int       `json:"history_id"`
 	PlayedAt  time.Time `json:"played_at"`
 	PlayCount int       `json:"play_count,omitzero"`
+	SessionID int       `json:"session_id,omitzero"`
 }
 
 func HistoryDTO(t *model.Track, h *model.History) History {
@@ -93,25 +119,79 @@ func HistoryDTO(t *model.Track, h *model.History) History {
 		HistoryID: h.ID,
 		PlayedAt:  h.PlayedAt,
 		PlayCount: h.PlayCount,
+		SessionID: h.SessionID,
 	}
 }
 
 type HistoryFilter struct {
-	UserID  int
-	Skipped *bool
-	Start   time.Time
-	End     time.Time
-	Limit   int
-	Offset  int
+	UserID    int
+	Skipped   *bool
+	Start     time.Time
+	End       time.Time
+	SessionID int
+	Limit     int
+	Offset    int
 }
 
 func (h HistoryFilter) ToModel() *model.HistoryFilter {
 	return &model.HistoryFilter{
-		UserID:  h.UserID,
-		Skipped: h.Skipped,
-		Start:   h.Start,
-		End:     h.End,
-		Limit:   h.Limit,
-		Offset:  h.Offset,
+		UserID:    h.UserID,
+		Skipped:   h.Skipped,
+		Start:     h.Start,
+		End:       h.End,
+		SessionID: h.SessionID,
+		Limit:     h.Limit,
+		Offset:    h.Offset,
+	}
+}
+
+type HistorySession struct {
+	ID         int       `json:"id"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	DurationMs int       `json:"duration_ms"`
+	Plays      int       `json:"plays"`
+	SkipRate   float64   `json:"skip_rate"` // Only plays of which we know if they were skipped count
+	PlaylistID int       `json:"playlist_id,omitzero"`
+	AlbumID    int       `json:"album_id,omitzero"`
+	ArtistID   int       `json:"artist_id,omitzero"`
+	ShowID     int       `json:"show_id,omitzero"`
+}
+
+func HistorySessionDTO(s *model.HistorySession) HistorySession {
+	skipRate := 0.0
+	if s.SkipsKnown > 0 {
+		skipRate = float64(s.Skips) / float64(s.SkipsKnown)
+	}
+
+	return HistorySession{
+		ID:         s.ID,
+		Start:      s.StartAt,
+		End:        s.EndAt,
+		DurationMs: int(s.EndAt.Sub(s.StartAt).Milliseconds()),
+		Plays:      s.Plays,
+		SkipRate:   skipRate,
+		PlaylistID: s.PlaylistID,
+		AlbumID:    s.AlbumID,
+		ArtistID:   s.ArtistID,
+		ShowID:     s.ShowID,
+	}
+}
+
+type HistorySessionFilter struct {
+	UserID int
+	Start  time.Time
+	End    time.Time
+	Limit  int
+	Offset int
+}
+
+func (h HistorySessionFilter) ToModel() *model.HistorySessionFilter {
+	return &model.HistorySessionFilter{
+		UserID: h.UserID,
+		Start:  h.Start,
+		End:    h.End,
+		Limit:  h.Limit,
+		Offset: h.Offset,
 	}
 }
@@ -8,6 +8,7 @@ type User struct {
 	Name        string `json:"name"`
 	DisplayName string `json:"display_name"`
 	Email       string `json:"email"`
+	Timezone    string `json:"timezone"`
 }
 
 func UserDTO(user *model.User) User {
@@ -22,10 +23,20 @@ func UserDTO(user *model.User) User {
 		Name:        name,
 		DisplayName: user.DisplayName,
 		Email:       user.Email,
+		Timezone:    user.Timezone,
 	}
 }
 
 func (u *User) ToModel() *model.User {
-	user := model.User(*u)
-	return &user
+	return &model.User{
+		ID:          u.ID,
+		UID:         u.UID,
+		Name:        u.Name,
+		DisplayName: u.DisplayName,
+		Email:       u.Email,
+	}
+}
+
+type UserTimezone struct {
+	Timezone string `json:"timezone" validate:"required,timezone"`
 }
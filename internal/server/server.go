@@ -27,9 +27,13 @@ type Server struct {
 func New(service service.Service, pool *pgxpool.Pool) *Server {
 	// Construct app
 	app := fiber.New(fiber.Config{
-		BodyLimit:         20 * 1024 * 1024,
-		ReadBufferSize:    8096,
-		StreamRequestBody: true,
+		// Larger bodies are streamed to the handler instead of being rejected
+		BodyLimit:      20 * 1024 * 1024,
+		ReadBufferSize: 8096,
+		// Don't buffer multipart forms before the handler is called
+		// The handlers can then stream file uploads
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 	})
 
 	app.Use(fiberzap.New(fiberzap.Config{
@@ -68,8 +72,13 @@ func New(service service.Service, pool *pgxpool.Pool) *Server {
 	routers.NewLink(protectedAPI, service)
 	routers.NewTask(protectedAPI, service)
 	routers.NewTrack(protectedAPI, service)
+	routers.NewStat(protectedAPI, service)
+	routers.NewRecap(protectedAPI, service)
+	routers.NewShow(protectedAPI, service)
+	routers.NewArtist(protectedAPI, service)
 	routers.NewGenerator(protectedAPI, service)
 	routers.NewSetting(protectedAPI, service)
+	routers.NewData(protectedAPI, service)
 
 	// Static files if served in production
 	if !config.IsDev() {
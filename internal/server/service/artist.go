@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/topvennie/sortifyr/internal/database/repository"
+	"github.com/topvennie/sortifyr/internal/server/dto"
+	"github.com/topvennie/sortifyr/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type Artist struct {
+	service Service
+
+	artist repository.Artist
+}
+
+func (s *Service) NewArtist() *Artist {
+	return &Artist{
+		service: *s,
+		artist:  *s.repo.NewArtist(),
+	}
+}
+
+func (a *Artist) GetByUser(ctx context.Context, userID int) ([]dto.Artist, error) {
+	artists, err := a.artist.GetByUser(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if artists == nil {
+		return []dto.Artist{}, nil
+	}
+
+	return utils.SliceMap(artists, dto.ArtistDTO), nil
+}
+
+func (a *Artist) GetReleases(ctx context.Context, filter dto.ArtistReleaseFilter) ([]dto.ArtistRelease, error) {
+	releases, err := a.artist.GetReleaseByUserPopulated(ctx, *filter.ToModel())
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return utils.SliceMap(releases, dto.ArtistReleaseDTO), nil
+}
@@ -0,0 +1,483 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/topvennie/sortifyr/internal/database/model"
+	"github.com/topvennie/sortifyr/internal/database/repository"
+	"github.com/topvennie/sortifyr/internal/server/dto"
+	"github.com/topvennie/sortifyr/internal/task"
+	"github.com/topvennie/sortifyr/pkg/config"
+	"github.com/topvennie/sortifyr/pkg/storage"
+	"github.com/topvennie/sortifyr/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	taskDataExportUID = "task-data-export"
+
+	dataBatchSize = 1000 // Amount of history entries fetched at once
+)
+
+type Data struct {
+	service Service
+
+	// Time an export stays available for download
+	expiration time.Duration
+
+	directory repository.Directory
+	episode   repository.Episode
+	generator repository.Generator
+	history   repository.History
+	link      repository.Link
+	playlist  repository.Playlist
+	task      repository.Task
+	track     repository.Track
+	user      repository.User
+}
+
+func (s *Service) NewData() *Data {
+	return &Data{
+		service:    *s,
+		expiration: config.GetDefaultDurationS("data.export_expiration_s", 7*24*60*60),
+		directory:  *s.repo.NewDirectory(),
+		episode:    *s.repo.NewEpisode(),
+		generator:  *s.repo.NewGenerator(),
+		history:    *s.repo.NewHistory(),
+		link:       *s.repo.NewLink(),
+		playlist:   *s.repo.NewPlaylist(),
+		task:       *s.repo.NewTask(),
+		track:      *s.repo.NewTrack(),
+		user:       *s.repo.NewUser(),
+	}
+}
+
+// Export starts a task that archives all data of a user
+// The archive replaces the previous one and can be downloaded until it expires
+func (d *Data) Export(ctx context.Context, userID int) error {
+	user, err := d.user.GetByID(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+	if user == nil {
+		return fiber.ErrUnauthorized
+	}
+
+	if err := task.Manager.Add(ctx, task.NewTask(
+		fmt.Sprintf("%s-%d", taskDataExportUID, user.ID),
+		"Export Data",
+		task.IntervalOnce,
+		false,
+		func(ctx context.Context, _ []model.User) []task.TaskResult {
+			message, err := d.exportTask(ctx, *user)
+
+			return []task.TaskResult{{
+				User:    *user,
+				Message: message,
+				Error:   err,
+			}}
+		},
+	)); err != nil {
+		if errors.Is(err, task.ErrTaskExists) {
+			return fiber.NewError(fiber.StatusBadRequest, "Task is already running")
+		}
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// GetExport returns the last archive of a user
+// Expired archives are treated as missing, they are cleaned up by the storage
+func (d *Data) GetExport(_ context.Context, userID int) (*storage.File, error) {
+	file, err := storage.Open(dataExportKey(userID))
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if file == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "No export available")
+	}
+
+	return file, nil
+}
+
+func dataExportKey(userID int) string {
+	return fmt.Sprintf("data-export-%d", userID)
+}
+
+func (d *Data) exportTask(ctx context.Context, user model.User) (string, error) {
+	// The archive is streamed to the storage while it's being created
+	pr, pw := io.Pipe()
+	go func() {
+		_ = pw.CloseWithError(d.exportWrite(ctx, user, pw)) // nolint:errcheck // Always returns nil
+	}()
+
+	// The previous archive is only replaced once the new one is completely stored
+	size, err := storage.Write(dataExportKey(user.ID), pr, d.expiration)
+	_ = pr.Close() // nolint:errcheck // Unblocks the writer if the storage failed
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Exported %.1f MB, available until %s", float64(size)/1024/1024, time.Now().Add(d.expiration).Format(time.DateTime)), nil
+}
+
+func (d *Data) exportWrite(ctx context.Context, user model.User, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	for _, file := range []struct {
+		name  string
+		write func(context.Context, int, io.Writer) error
+	}{
+		{"history.json", d.exportHistoryJSON},
+		{"history.csv", d.exportHistoryCSV},
+		{"episode_history.json", d.exportEpisodeHistoryJSON},
+		{"episode_history.csv", d.exportEpisodeHistoryCSV},
+		{"playlists.json", d.exportPlaylists},
+		{"directories.json", d.exportDirectories},
+		{"links.json", d.exportLinks},
+		{"generators.json", d.exportGenerators},
+		{"tasks.json", d.exportTasks},
+	} {
+		fw, err := zw.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("create %s | %w", file.name, err)
+		}
+
+		if err := file.write(ctx, user.ID, fw); err != nil {
+			return fmt.Errorf("write %s | %w", file.name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("close zip %w", err)
+	}
+
+	return nil
+}
+
+// exportHistory calls fn with every history entry of the user in batches
+func (d *Data) exportHistory(ctx context.Context, userID int, fn func([]*model.History) error) error {
+	afterID := 0
+	for {
+		histories, err := d.history.GetPopulatedAfter(ctx, userID, afterID, dataBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(histories) == 0 {
+			return nil
+		}
+
+		if err := fn(histories); err != nil {
+			return err
+		}
+
+		afterID = histories[len(histories)-1].ID
+	}
+}
+
+// exportEpisodeHistory calls fn with every episode history entry of the user in batches
+func (d *Data) exportEpisodeHistory(ctx context.Context, userID int, fn func([]*model.EpisodeHistory) error) error {
+	afterID := 0
+	for {
+		histories, err := d.episode.GetHistoryPopulatedAfter(ctx, userID, afterID, dataBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(histories) == 0 {
+			return nil
+		}
+
+		if err := fn(histories); err != nil {
+			return err
+		}
+
+		afterID = histories[len(histories)-1].ID
+	}
+}
+
+func (d *Data) exportHistoryJSON(ctx context.Context, userID int, w io.Writer) error {
+	array := dataJSONArray{w: w}
+
+	if err := d.exportHistory(ctx, userID, func(histories []*model.History) error {
+		for _, h := range histories {
+			if err := array.add(dto.DataHistoryDTO(h)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return array.close()
+}
+
+func (d *Data) exportHistoryCSV(ctx context.Context, userID int, w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"played_at", "track_spotify_id", "track_name", "artists", "duration_ms", "skipped", "source", "reason_start", "reason_end", "shuffle", "offline", "platform", "conn_country"}); err != nil {
+		return err
+	}
+
+	if err := d.exportHistory(ctx, userID, func(histories []*model.History) error {
+		for _, h := range histories {
+			entry := dto.DataHistoryDTO(h)
+
+			if err := cw.Write([]string{
+				entry.PlayedAt.Format(time.RFC3339),
+				entry.TrackSpotifyID,
+				entry.TrackName,
+				strings.Join(entry.Artists, ", "),
+				strconv.Itoa(entry.DurationMs),
+				dataCSVBool(entry.Skipped),
+				string(entry.Source),
+				entry.ReasonStart,
+				entry.ReasonEnd,
+				dataCSVBool(entry.Shuffle),
+				dataCSVBool(entry.Offline),
+				entry.Platform,
+				entry.ConnCountry,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func (d *Data) exportEpisodeHistoryJSON(ctx context.Context, userID int, w io.Writer) error {
+	array := dataJSONArray{w: w}
+
+	if err := d.exportEpisodeHistory(ctx, userID, func(histories []*model.EpisodeHistory) error {
+		for _, h := range histories {
+			if err := array.add(dto.DataEpisodeHistoryDTO(h)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return array.close()
+}
+
+func (d *Data) exportEpisodeHistoryCSV(ctx context.Context, userID int, w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"played_at", "episode_spotify_id", "episode_name", "show_spotify_id", "show_name", "position_ms", "source", "reason_start", "reason_end", "shuffle", "offline", "platform", "conn_country"}); err != nil {
+		return err
+	}
+
+	if err := d.exportEpisodeHistory(ctx, userID, func(histories []*model.EpisodeHistory) error {
+		for _, h := range histories {
+			entry := dto.DataEpisodeHistoryDTO(h)
+
+			if err := cw.Write([]string{
+				entry.PlayedAt.Format(time.RFC3339),
+				entry.EpisodeSpotifyID,
+				entry.EpisodeName,
+				entry.ShowSpotifyID,
+				entry.ShowName,
+				strconv.Itoa(entry.PositionMs),
+				string(entry.Source),
+				entry.ReasonStart,
+				entry.ReasonEnd,
+				dataCSVBool(entry.Shuffle),
+				dataCSVBool(entry.Offline),
+				entry.Platform,
+				entry.ConnCountry,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// exportPlaylists writes all playlists of the user
+// Tracks that were removed are included with the date they were removed
+func (d *Data) exportPlaylists(ctx context.Context, userID int, w io.Writer) error {
+	playlists, err := d.playlist.GetByUserPopulated(ctx, userID)
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(playlists, func(a, b *model.Playlist) int { return a.ID - b.ID })
+
+	playlistTracks, err := d.playlist.GetTrackByPlaylistIDs(ctx, utils.SliceMap(playlists, func(p *model.Playlist) int { return p.ID }))
+	if err != nil {
+		return err
+	}
+
+	tracks, err := d.track.GetAllByID(ctx, utils.SliceUnique(utils.SliceMap(playlistTracks, func(t *model.PlaylistTrack) int { return t.TrackID })))
+	if err != nil {
+		return err
+	}
+	trackMap := utils.SliceToMap(tracks, func(t *model.Track) int { return t.ID })
+
+	tracksByPlaylist := make(map[int][]dto.DataPlaylistTrack)
+	for _, t := range playlistTracks {
+		track, ok := trackMap[t.TrackID]
+		if !ok {
+			continue
+		}
+
+		tracksByPlaylist[t.PlaylistID] = append(tracksByPlaylist[t.PlaylistID], dto.DataPlaylistTrack{
+			SpotifyID: track.SpotifyID,
+			Name:      track.Name,
+			AddedAt:   t.CreatedAt,
+			RemovedAt: t.DeletedAt,
+		})
+	}
+
+	data := make([]dto.DataPlaylist, 0, len(playlists))
+	for _, p := range playlists {
+		playlistTracks := tracksByPlaylist[p.ID]
+		if playlistTracks == nil {
+			playlistTracks = []dto.DataPlaylistTrack{}
+		}
+		slices.SortStableFunc(playlistTracks, func(a, b dto.DataPlaylistTrack) int { return a.AddedAt.Compare(b.AddedAt) })
+
+		data = append(data, dto.DataPlaylistDTO(p, playlistTracks))
+	}
+
+	return dataWriteJSON(w, data)
+}
+
+func (d *Data) exportDirectories(ctx context.Context, userID int, w io.Writer) error {
+	directoryModels, err := d.directory.GetByUserPopulated(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	roots := utils.SliceFilter(directoryModels, func(d *model.Directory) bool { return d.ParentID == 0 })
+	directories := make([]dto.Directory, 0, len(roots))
+
+	for _, root := range roots {
+		directories = append(directories, dto.DirectoryDTO(root, directoryModels))
+	}
+
+	return dataWriteJSON(w, directories)
+}
+
+func (d *Data) exportLinks(ctx context.Context, userID int, w io.Writer) error {
+	links, err := d.link.GetAllByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	links = utils.SliceUniqueFunc(links, func(l *model.Link) int { return l.ID })
+
+	return dataWriteJSON(w, utils.SliceMap(links, dto.LinkDTO))
+}
+
+func (d *Data) exportGenerators(ctx context.Context, userID int, w io.Writer) error {
+	generators, err := d.generator.GetByUserPopulated(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return dataWriteJSON(w, utils.SliceMap(generators, dto.GeneratorDTO))
+}
+
+func (d *Data) exportTasks(ctx context.Context, userID int, w io.Writer) error {
+	array := dataJSONArray{w: w}
+
+	for offset := 0; ; offset += dataBatchSize {
+		tasks, err := d.task.GetRunFiltered(ctx, model.TaskFilter{
+			UserID: userID,
+			Limit:  dataBatchSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, t := range tasks {
+			if err := array.add(dto.TaskHistoryDTO(t)); err != nil {
+				return err
+			}
+		}
+
+		if len(tasks) < dataBatchSize {
+			break
+		}
+	}
+
+	return array.close()
+}
+
+func dataWriteJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+// dataJSONArray writes a json array one element at a time
+type dataJSONArray struct {
+	w      io.Writer
+	amount int
+}
+
+func (a *dataJSONArray) add(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal %+v | %w", v, err)
+	}
+
+	separator := ",\n"
+	if a.amount == 0 {
+		separator = "[\n"
+	}
+	a.amount++
+
+	if _, err := io.WriteString(a.w, separator); err != nil {
+		return err
+	}
+	_, err = a.w.Write(data)
+
+	return err
+}
+
+func (a *dataJSONArray) close() error {
+	end := "\n]\n"
+	if a.amount == 0 {
+		end = "[]\n"
+	}
+
+	_, err := io.WriteString(a.w, end)
+	return err
+}
+
+func dataCSVBool(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
@@ -1,12 +1,19 @@
 package service
 
 import (
+	"cmp"
 	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/topvennie/sortifyr/internal/database/model"
 	"github.com/topvennie/sortifyr/internal/database/repository"
 	"github.com/topvennie/sortifyr/internal/server/dto"
+	"github.com/topvennie/sortifyr/internal/spotifyapi"
+	"github.com/topvennie/sortifyr/internal/spotifysync"
 	"github.com/topvennie/sortifyr/pkg/utils"
 	"go.uber.org/zap"
 )
@@ -15,12 +22,18 @@ type Directory struct {
 	service Service
 
 	directory repository.Directory
+	link      repository.Link
+	track     repository.Track
+	user      repository.User
 }
 
 func (s *Service) NewDirectory() *Directory {
 	return &Directory{
 		service:   *s,
 		directory: *s.repo.NewDirectory(),
+		link:      *s.repo.NewLink(),
+		track:     *s.repo.NewTrack(),
+		user:      *s.repo.NewUser(),
 	}
 }
 
@@ -44,17 +57,302 @@ func (d *Directory) GetByUser(ctx context.Context, userID int) ([]dto.Directory,
 	return directories, nil
 }
 
-// Sync brings the database up to date with the data received from the api
-func (d *Directory) Sync(ctx context.Context, userID int, roots []dto.Directory) ([]dto.Directory, error) {
-	// Not the best practice to simply delete and recreate everything
-	if err := d.service.withRollback(ctx, func(ctx context.Context) error {
-		if err := d.directory.DeleteByUser(ctx, userID); err != nil {
+// GetTracks returns the deduplicated tracks of all playlists in a directory
+func (d *Directory) GetTracks(ctx context.Context, userID, directoryID int, subdirectories bool) ([]dto.Track, error) {
+	_, tracks, err := d.playlistTracks(ctx, userID, directoryID, subdirectories)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := utils.SliceUniqueFunc(utils.SliceDereference(slices.Concat(utils.MapValues(tracks)...)), func(t model.Track) int { return t.ID })
+	slices.SortFunc(merged, directoryTrackCompare)
+
+	return utils.SliceMap(merged, func(t model.Track) dto.Track { return dto.TrackDTO(&t) }), nil
+}
+
+// GetDuplicates returns the tracks that appear in more than one playlist of a directory
+func (d *Directory) GetDuplicates(ctx context.Context, userID, directoryID int, subdirectories bool) ([]dto.DirectoryDuplicate, error) {
+	playlists, tracks, err := d.playlistTracks(ctx, userID, directoryID, subdirectories)
+	if err != nil {
+		return nil, err
+	}
+
+	trackMap := make(map[int]model.Track)
+	trackPlaylists := make(map[int][]model.Playlist)
+	for _, playlist := range playlists {
+		for _, track := range utils.SliceUniqueFunc(tracks[playlist.ID], func(t *model.Track) int { return t.ID }) {
+			trackMap[track.ID] = *track
+			trackPlaylists[track.ID] = append(trackPlaylists[track.ID], playlist)
+		}
+	}
+
+	duplicates := make([]model.Track, 0)
+	for trackID, playlists := range trackPlaylists {
+		if len(playlists) > 1 {
+			duplicates = append(duplicates, trackMap[trackID])
+		}
+	}
+	slices.SortFunc(duplicates, directoryTrackCompare)
+
+	return utils.SliceMap(duplicates, func(t model.Track) dto.DirectoryDuplicate {
+		return dto.DirectoryDuplicateDTO(&t, trackPlaylists[t.ID])
+	}), nil
+}
+
+// directoryTrackCompare orders tracks by name and id
+// The tracks are collected in maps which would otherwise give a random order
+func directoryTrackCompare(a, b model.Track) int {
+	return cmp.Or(strings.Compare(a.Name, b.Name), a.ID-b.ID)
+}
+
+// RemoveDuplicate removes a track from every playlist in a directory except the one to keep
+func (d *Directory) RemoveDuplicate(ctx context.Context, userID, directoryID int, remove dto.DirectoryDuplicateRemove, subdirectories bool) error {
+	user, err := d.user.GetByID(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+	if user == nil {
+		return fiber.ErrUnauthorized
+	}
+
+	playlists, tracks, err := d.playlistTracks(ctx, userID, directoryID, subdirectories)
+	if err != nil {
+		return err
+	}
+
+	if !slices.ContainsFunc(playlists, func(p model.Playlist) bool { return p.ID == remove.KeepPlaylistID }) {
+		return fiber.NewError(fiber.StatusBadRequest, "playlist to keep is not part of the directory")
+	}
+
+	toRemove := make([]model.Playlist, 0)
+	var track *model.Track
+	for _, playlist := range playlists {
+		if playlist.ID == remove.KeepPlaylistID {
+			continue
+		}
+
+		t, ok := utils.SliceFind(tracks[playlist.ID], func(t *model.Track) bool { return t.ID == remove.TrackID })
+		if !ok {
+			continue
+		}
+
+		if !playlist.Editable(userID) {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("playlist %s can not be edited", playlist.Name))
+		}
+
+		track = *t
+		toRemove = append(toRemove, playlist)
+	}
+
+	if track == nil {
+		return nil
+	}
+	if track.SpotifyID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "track is unavailable and can not be removed")
+	}
+
+	for _, playlist := range toRemove {
+		if err := spotifyapi.C.PlaylistDeleteTrackAll(ctx, *user, playlist.SpotifyID, playlist.SnapshotID, []model.Track{*track}); err != nil {
+			zap.S().Error(err)
+			return fiber.ErrInternalServerError
+		}
+	}
+
+	return nil
+}
+
+// playlistTracks returns the playlists of a directory together with the tracks of each playlist
+func (d *Directory) playlistTracks(ctx context.Context, userID, directoryID int, subdirectories bool) ([]model.Playlist, map[int][]*model.Track, error) {
+	directories, err := d.directory.GetByUserPopulated(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return nil, nil, fiber.ErrInternalServerError
+	}
+
+	directory, ok := utils.SliceFind(directories, func(d *model.Directory) bool { return d.ID == directoryID })
+	if !ok {
+		return nil, nil, fiber.ErrNotFound
+	}
+
+	playlists := (*directory).Playlists
+	if subdirectories {
+		playlists = model.DirectoryPlaylistsRecursive(directories, directoryID)
+	}
+
+	tracks := make(map[int][]*model.Track, len(playlists))
+	for _, playlist := range playlists {
+		playlistTracks, err := d.track.GetByPlaylist(ctx, playlist.ID)
+		if err != nil {
+			zap.S().Error(err)
+			return nil, nil, fiber.ErrInternalServerError
+		}
+
+		tracks[playlist.ID] = playlistTracks
+	}
+
+	return playlists, tracks, nil
+}
+
+func (d *Directory) Create(ctx context.Context, userID int, directorySave dto.DirectorySave) (dto.Directory, error) {
+	directories, err := d.directory.GetByUserPopulated(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return dto.Directory{}, fiber.ErrInternalServerError
+	}
+
+	if directorySave.ParentID != 0 {
+		if _, ok := utils.SliceFind(directories, func(d *model.Directory) bool { return d.ID == directorySave.ParentID }); !ok {
+			return dto.Directory{}, fiber.ErrNotFound
+		}
+	}
+
+	directory := directorySave.ToModel(userID)
+	if directory.IsSmart() {
+		if err := validateRules(*directory.Rules); err != nil {
+			return dto.Directory{}, err
+		}
+		directory.Playlists = nil
+	}
+
+	if err := d.directory.Create(ctx, directory); err != nil {
+		zap.S().Error(err)
+		return dto.Directory{}, fiber.ErrInternalServerError
+	}
+
+	if err := d.smartSync(ctx, userID); err != nil {
+		return dto.Directory{}, err
+	}
+
+	// Get it again to populate the playlists
+	directories, err = d.directory.GetByUserPopulated(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return dto.Directory{}, fiber.ErrInternalServerError
+	}
+
+	created, ok := utils.SliceFind(directories, func(d *model.Directory) bool { return d.ID == directory.ID })
+	if !ok {
+		return dto.Directory{}, fiber.ErrInternalServerError
+	}
+
+	return dto.DirectoryDTO(*created, directories), nil
+}
+
+func (d *Directory) Rename(ctx context.Context, userID, directoryID int, name string) error {
+	directories, err := d.directory.GetByUserPopulated(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	directory, ok := utils.SliceFind(directories, func(d *model.Directory) bool { return d.ID == directoryID })
+	if !ok {
+		return fiber.ErrNotFound
+	}
+
+	(*directory).Name = name
+
+	if err := d.directory.Update(ctx, **directory); err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// Move changes the parent of a directory
+// A parent id of 0 moves it to the root
+func (d *Directory) Move(ctx context.Context, userID, directoryID, parentID int) error {
+	directories, err := d.directory.GetByUserPopulated(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	directory, ok := utils.SliceFind(directories, func(d *model.Directory) bool { return d.ID == directoryID })
+	if !ok {
+		return fiber.ErrNotFound
+	}
+
+	if parentID != 0 {
+		if _, ok := utils.SliceFind(directories, func(d *model.Directory) bool { return d.ID == parentID }); !ok {
+			return fiber.ErrNotFound
+		}
+		if slices.Contains(directorySubtree(directories, directoryID), parentID) {
+			return fiber.NewError(fiber.StatusBadRequest, "a directory can not be moved into itself")
+		}
+	}
+
+	(*directory).ParentID = parentID
+
+	if err := d.directory.Update(ctx, **directory); err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// Delete removes a directory and all its subdirectories
+// If any link depends on them then cascade has to be set to delete those links as well
+func (d *Directory) Delete(ctx context.Context, userID, directoryID int, cascade bool) error {
+	directories, err := d.directory.GetByUserPopulated(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	if _, ok := utils.SliceFind(directories, func(d *model.Directory) bool { return d.ID == directoryID }); !ok {
+		return fiber.ErrNotFound
+	}
+
+	return d.service.withRollback(ctx, func(ctx context.Context) error {
+		if err := d.deleteLinks(ctx, userID, directorySubtree(directories, directoryID), cascade); err != nil {
+			return err
+		}
+
+		if err := d.directory.Delete(ctx, directoryID); err != nil {
 			zap.S().Error(err)
 			return fiber.ErrInternalServerError
 		}
 
+		return nil
+	})
+}
+
+// Sync brings the database up to date with the data received from the api
+// Existing directories keep their id.
+// Directories that are missing are deleted, see Delete for the cascade argument.
+func (d *Directory) Sync(ctx context.Context, userID int, roots []dto.Directory, cascade bool) ([]dto.Directory, error) {
+	if err := validateRulesAll(roots); err != nil {
+		return nil, err
+	}
+
+	directoriesDB, err := d.directory.GetByUserPopulated(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := d.service.withRollback(ctx, func(ctx context.Context) error {
+		seen := make(map[int]bool)
+
 		for _, root := range roots {
-			if err := d.create(ctx, userID, 0, root); err != nil {
+			if err := d.sync(ctx, userID, 0, root, directoriesDB, seen); err != nil {
+				zap.S().Error(err)
+				return fiber.ErrInternalServerError
+			}
+		}
+
+		toDelete := utils.SliceFilter(directoriesDB, func(d *model.Directory) bool { return !seen[d.ID] })
+
+		if err := d.deleteLinks(ctx, userID, utils.SliceMap(toDelete, func(d *model.Directory) int { return d.ID }), cascade); err != nil {
+			return err
+		}
+
+		for _, directory := range toDelete {
+			if err := d.directory.Delete(ctx, directory.ID); err != nil {
 				zap.S().Error(err)
 				return fiber.ErrInternalServerError
 			}
@@ -65,23 +363,162 @@ func (d *Directory) Sync(ctx context.Context, userID int, roots []dto.Directory)
 		return nil, err
 	}
 
+	if err := d.smartSync(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	return d.GetByUser(ctx, userID)
 }
 
-// create is an internal function to create a single directory
-// It goes recursively through the children to create every directory
-func (d *Directory) create(ctx context.Context, userID, parentID int, directorySave dto.Directory) error {
+// sync is an internal function to create or update a single directory
+// It goes recursively through the children to sync every directory
+func (d *Directory) sync(ctx context.Context, userID, parentID int, directorySave dto.Directory, directoriesDB []*model.Directory, seen map[int]bool) error {
 	directory := directorySave.ToModel(userID, parentID)
+	if directory.IsSmart() {
+		// The playlists are managed by the rules
+		directory.Playlists = nil
+	}
 
-	if err := d.directory.Create(ctx, directory); err != nil {
-		return err
+	directoryDB, ok := utils.SliceFind(directoriesDB, func(d *model.Directory) bool { return d.ID == directory.ID })
+	if !ok || seen[directory.ID] {
+		// New directory
+		if err := d.directory.Create(ctx, directory); err != nil {
+			return err
+		}
+	} else {
+		seen[directory.ID] = true
+
+		rulesChanged := (*directoryDB).IsSmart() != directory.IsSmart() || (directory.IsSmart() && !(*directoryDB).Rules.Equal(*directory.Rules))
+		if (*directoryDB).Name != directory.Name || (*directoryDB).ParentID != directory.ParentID || rulesChanged {
+			if err := d.directory.Update(ctx, *directory); err != nil {
+				return err
+			}
+		}
+
+		// The playlists of a smart directory are updated by smartSync
+		// If it used to be a manual one then the diff below removes the old playlists
+		if directory.IsSmart() && (*directoryDB).IsSmart() {
+			return d.syncChildren(ctx, userID, directory.ID, directorySave, directoriesDB, seen)
+		}
+
+		for _, p := range directory.Playlists {
+			if !slices.ContainsFunc((*directoryDB).Playlists, func(p2 model.Playlist) bool { return p2.ID == p.ID }) {
+				if err := d.directory.CreatePlaylist(ctx, &model.DirectoryPlaylist{DirectoryID: directory.ID, PlaylistID: p.ID}); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, p := range (*directoryDB).Playlists {
+			if !slices.ContainsFunc(directory.Playlists, func(p2 model.Playlist) bool { return p2.ID == p.ID }) {
+				if err := d.directory.DeletePlaylistByDirectoryPlaylist(ctx, model.DirectoryPlaylist{DirectoryID: directory.ID, PlaylistID: p.ID}); err != nil {
+					return err
+				}
+			}
+		}
 	}
 
+	return d.syncChildren(ctx, userID, directory.ID, directorySave, directoriesDB, seen)
+}
+
+func (d *Directory) syncChildren(ctx context.Context, userID, directoryID int, directorySave dto.Directory, directoriesDB []*model.Directory, seen map[int]bool) error {
 	for _, child := range directorySave.Children {
-		if err := d.create(ctx, userID, directory.ID, child); err != nil {
+		if err := d.sync(ctx, userID, directoryID, child, directoriesDB, seen); err != nil {
 			return err
 		}
 	}
 
 	return nil
 }
+
+// smartSync evaluates the rules of the smart directories
+func (d *Directory) smartSync(ctx context.Context, userID int) error {
+	user, err := d.user.GetByID(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+	if user == nil {
+		return fiber.ErrUnauthorized
+	}
+
+	if err := spotifysync.C.DirectorySmartSync(ctx, *user); err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// deleteLinks handles the links that depend on directories that are about to be deleted
+// Without cascade it returns an error if there are any
+func (d *Directory) deleteLinks(ctx context.Context, userID int, directoryIDs []int, cascade bool) error {
+	links, err := d.link.GetAllByUser(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	dependents := utils.SliceFilter(links, func(l *model.Link) bool {
+		return slices.Contains(directoryIDs, l.SourceDirectoryID) || slices.Contains(directoryIDs, l.TargetDirectoryID)
+	})
+	if len(dependents) == 0 {
+		return nil
+	}
+
+	if !cascade {
+		return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("%d link(s) depend on the directory, delete them first or delete with cascade", len(dependents)))
+	}
+
+	for _, link := range dependents {
+		if err := d.link.Delete(ctx, link.ID); err != nil {
+			zap.S().Error(err)
+			return fiber.ErrInternalServerError
+		}
+	}
+
+	return nil
+}
+
+func validateRules(rules model.DirectoryRules) error {
+	if _, err := regexp.Compile(rules.NameRegex); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid name regex %s", rules.NameRegex))
+	}
+
+	if rules.TrackAmountMax != 0 && rules.TrackAmountMin > rules.TrackAmountMax {
+		return fiber.NewError(fiber.StatusBadRequest, "minimum track amount is larger than the maximum")
+	}
+
+	return nil
+}
+
+func validateRulesAll(directories []dto.Directory) error {
+	for _, directory := range directories {
+		if directory.Rules != nil {
+			if err := validateRules(*directory.Rules.ToModel()); err != nil {
+				return err
+			}
+		}
+
+		if err := validateRulesAll(directory.Children); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// directorySubtree returns the id of the directory and of all its subdirectories
+func directorySubtree(directories []*model.Directory, directoryID int) []int {
+	ids := []int{directoryID}
+
+	for i := 0; i < len(ids); i++ {
+		for _, d := range directories {
+			if d.ParentID == ids[i] && !slices.Contains(ids, d.ID) {
+				ids = append(ids, d.ID)
+			}
+		}
+	}
+
+	return ids
+}
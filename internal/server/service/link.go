@@ -2,11 +2,15 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/topvennie/sortifyr/internal/database/model"
 	"github.com/topvennie/sortifyr/internal/database/repository"
 	"github.com/topvennie/sortifyr/internal/server/dto"
+	"github.com/topvennie/sortifyr/internal/spotifysync"
 	"github.com/topvennie/sortifyr/pkg/utils"
 	"go.uber.org/zap"
 )
@@ -14,13 +18,19 @@ import (
 type Link struct {
 	service Service
 
-	link repository.Link
+	directory repository.Directory
+	link      repository.Link
+	playlist  repository.Playlist
+	user      repository.User
 }
 
 func (s *Service) NewLink() *Link {
 	return &Link{
-		service: *s,
-		link:    *s.repo.NewLink(),
+		service:   *s,
+		directory: *s.repo.NewDirectory(),
+		link:      *s.repo.NewLink(),
+		playlist:  *s.repo.NewPlaylist(),
+		user:      *s.repo.NewUser(),
 	}
 }
 
@@ -37,8 +47,51 @@ func (l *Link) GetAllByUser(ctx context.Context, userID int) ([]dto.Link, error)
 	return utils.SliceMap(links, dto.LinkDTO), nil
 }
 
+func (l *Link) GetPreview(ctx context.Context, userID, linkID int) ([]dto.LinkPreview, error) {
+	user, err := l.user.GetByID(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if user == nil {
+		return nil, fiber.ErrUnauthorized
+	}
+
+	links, err := l.link.GetAllByUser(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if _, ok := utils.SliceFind(links, func(l *model.Link) bool { return l.ID == linkID }); !ok {
+		return nil, fiber.ErrNotFound
+	}
+
+	changes, err := spotifysync.C.LinkPreview(ctx, *user, linkID)
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return utils.SliceMap(changes, dto.LinkPreviewDTO), nil
+}
+
 func (l *Link) Sync(ctx context.Context, userID int, linksSave []dto.Link) ([]dto.Link, error) {
 	linksNew := utils.SliceMap(linksSave, func(l dto.Link) *model.Link { return l.ToModel() })
+	for i := range linksSave {
+		if linksNew[i].Mode == model.LinkBidirectional && !linksNew[i].Filter.IsZero() {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "bidirectional links can not have filters")
+		}
+		if linksSave[i].SourceLiked {
+			if linksNew[i].Mode == model.LinkBidirectional {
+				return nil, fiber.NewError(fiber.StatusBadRequest, "liked songs can not be used in a bidirectional link")
+			}
+			linksNew[i].SourceUserID = userID
+		}
+	}
+
+	if err := l.validate(ctx, userID, linksNew); err != nil {
+		return nil, err
+	}
 
 	linksDB, err := l.link.GetAllByUser(ctx, userID)
 	if err != nil {
@@ -103,3 +156,166 @@ func (l *Link) Sync(ctx context.Context, userID int, linksSave []dto.Link) ([]dt
 
 	return l.GetAllByUser(ctx, userID)
 }
+
+// linkEdge is a source and target playlist pair of a link
+type linkEdge struct {
+	link   *model.Link
+	source model.Playlist
+	target model.Playlist
+}
+
+// validate checks if the links can be synced.
+// Every target has to be editable by the user and the links can't contain a cycle.
+// A cycle would make the playlists grow until they all contain the same tracks.
+// A mirrored target can't be the target of an append link
+// as the mirror would never remove the appended tracks.
+func (l *Link) validate(ctx context.Context, userID int, links []*model.Link) error {
+	directories, err := l.directory.GetByUserPopulated(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	playlists, err := l.playlist.GetByUserPopulated(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	edges := make([]linkEdge, 0)
+	targetModes := make(map[int][]model.LinkMode)
+
+	for _, link := range links {
+		sources, targets, err := model.LinkResolve(*link, directories, playlists)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+
+		// Bidirectional links write to their sources as well
+		editables := targets
+		if link.Mode == model.LinkBidirectional {
+			editables = slices.Concat(targets, sources)
+		}
+
+		for _, p := range editables {
+			if !p.Editable(userID) {
+				return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("playlist %s can not be edited", p.Name))
+			}
+		}
+
+		for _, t := range targets {
+			modes := targetModes[t.ID]
+			if (link.Mode == model.LinkMirror && slices.Contains(modes, model.LinkAppend)) || (link.Mode == model.LinkAppend && slices.Contains(modes, model.LinkMirror)) {
+				return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("playlist %s can not be the target of both an append and a mirror link", t.Name))
+			}
+			targetModes[t.ID] = append(modes, link.Mode)
+		}
+
+		for i := range sources {
+			for j := range targets {
+				edges = append(edges, linkEdge{link: link, source: sources[i], target: targets[j]})
+			}
+		}
+	}
+
+	if path := linkCycle(edges); path != "" {
+		return fiber.NewError(fiber.StatusBadRequest, "links contain a cycle: "+path)
+	}
+
+	return nil
+}
+
+// linkCycle returns the path of the first cycle it finds, an empty string if there are none.
+// Playlists in a bidirectional link are kept in sync with each other
+// and are considered as a single node.
+func linkCycle(edges []linkEdge) string {
+	// Group the playlists of bidirectional links
+	groups := make(map[int]int)
+	var group func(id int) int
+	group = func(id int) int {
+		parent, ok := groups[id]
+		if !ok || parent == id {
+			return id
+		}
+		root := group(parent)
+		groups[id] = root
+		return root
+	}
+
+	for _, e := range edges {
+		if e.link.Mode == model.LinkBidirectional {
+			groups[group(e.source.ID)] = group(e.target.ID)
+		}
+	}
+
+	graph := make(map[int][]linkEdge)
+	for _, e := range edges {
+		if e.link.Mode == model.LinkBidirectional {
+			continue
+		}
+		graph[group(e.source.ID)] = append(graph[group(e.source.ID)], e)
+	}
+
+	// Depth first search
+	// A node on the current path that is reached again is a cycle
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[int]int)
+	var path []linkEdge
+
+	var visit func(node int) []linkEdge
+	visit = func(node int) []linkEdge {
+		state[node] = visiting
+
+		for _, e := range graph[node] {
+			next := group(e.target.ID)
+			path = append(path, e)
+
+			switch state[next] {
+			case visiting:
+				start := 0
+				for i := range path {
+					if group(path[i].source.ID) == next {
+						start = i
+						break
+					}
+				}
+				return path[start:]
+			case unvisited:
+				if cycle := visit(next); cycle != nil {
+					return cycle
+				}
+			}
+
+			path = path[:len(path)-1]
+		}
+
+		state[node] = done
+
+		return nil
+	}
+
+	for node := range graph {
+		if state[node] != unvisited {
+			continue
+		}
+		if cycle := visit(node); cycle != nil {
+			names := []string{cycle[0].source.Name}
+			last := cycle[0].source.ID
+			for _, e := range cycle {
+				if e.source.ID != last {
+					names = append(names, e.source.Name)
+				}
+				names = append(names, e.target.Name)
+				last = e.target.ID
+			}
+
+			return strings.Join(names, " -> ")
+		}
+	}
+
+	return ""
+}
@@ -3,6 +3,10 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/topvennie/sortifyr/internal/database/model"
@@ -15,20 +19,31 @@ import (
 	"go.uber.org/zap"
 )
 
-const taskPlaylistDuplicateUID = "task-playlist-duplicate"
+const (
+	taskPlaylistDuplicateUID = "task-playlist-duplicate"
+	taskPlaylistMergeUID     = "task-playlist-merge"
+	taskPlaylistSplitUID     = "task-playlist-split"
+)
+
+// Maximum amount of playlists a split can create
+const playlistSplitMax = 100
 
 type Playlist struct {
 	service Service
 
-	playlist repository.Playlist
-	user     repository.User
+	directory repository.Directory
+	playlist  repository.Playlist
+	track     repository.Track
+	user      repository.User
 }
 
 func (s *Service) NewPlaylist() *Playlist {
 	return &Playlist{
-		service:  *s,
-		playlist: *s.repo.NewPlaylist(),
-		user:     *s.repo.NewUser(),
+		service:   *s,
+		directory: *s.repo.NewDirectory(),
+		playlist:  *s.repo.NewPlaylist(),
+		track:     *s.repo.NewTrack(),
+		user:      *s.repo.NewUser(),
 	}
 }
 
@@ -84,9 +99,32 @@ func (p *Playlist) GetDuplicates(ctx context.Context, userID int) ([]dto.Playlis
 		}
 	}
 
-	return utils.SliceMap(playlists, func(p *model.Playlist) dto.PlaylistDuplicate {
+	duplicates := utils.SliceMap(playlists, func(p *model.Playlist) dto.PlaylistDuplicate {
 		return dto.PlaylistDuplicateDTO(p, &p.Owner, p.Duplicates)
-	}), nil
+	})
+
+	// Liked songs can't contain a track twice
+	// Instead report the liked songs that are also in one of the user's playlists
+	// They're only reported, the removal task leaves liked songs alone
+	liked, err := p.track.GetSavedInPlaylistByUser(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if len(liked) > 0 {
+		user, err := p.user.GetByID(ctx, userID)
+		if err != nil {
+			zap.S().Error(err)
+			return nil, fiber.ErrInternalServerError
+		}
+		if user == nil {
+			return nil, fiber.ErrUnauthorized
+		}
+
+		duplicates = append(duplicates, dto.PlaylistDuplicateLikedDTO(user, utils.SliceDereference(liked)))
+	}
+
+	return duplicates, nil
 }
 
 func (p *Playlist) GetUnplayables(ctx context.Context, userID int) ([]dto.PlaylistUnplayable, error) {
@@ -165,3 +203,303 @@ func (p *Playlist) removeDuplicatesTask(ctx context.Context, user model.User) er
 
 	return nil
 }
+
+// Merge adds the tracks of multiple playlists to a new or an existing playlist
+// It runs as a task
+func (p *Playlist) Merge(ctx context.Context, userID int, merge dto.PlaylistMerge) error {
+	user, err := p.user.GetByID(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+	if user == nil {
+		return fiber.ErrUnauthorized
+	}
+
+	playlists, err := p.playlist.GetByUser(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	sources := make([]model.Playlist, 0, len(merge.PlaylistIDs))
+	for _, id := range utils.SliceUnique(merge.PlaylistIDs) {
+		source, ok := utils.SliceFind(playlists, func(p *model.Playlist) bool { return p.ID == id })
+		if !ok {
+			return fiber.ErrNotFound
+		}
+		sources = append(sources, **source)
+	}
+
+	var target *model.Playlist
+	if merge.TargetPlaylistID != 0 {
+		t, ok := utils.SliceFind(playlists, func(p *model.Playlist) bool { return p.ID == merge.TargetPlaylistID })
+		if !ok {
+			return fiber.ErrNotFound
+		}
+		if !(*t).Editable(userID) {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("playlist %s can not be edited", (*t).Name))
+		}
+		if slices.Contains(merge.PlaylistIDs, merge.TargetPlaylistID) {
+			return fiber.NewError(fiber.StatusBadRequest, "the target can not be one of the merged playlists")
+		}
+		target = *t
+	}
+
+	if err := p.validateDirectory(ctx, userID, merge.DirectoryID); err != nil {
+		return err
+	}
+
+	uid := fmt.Sprintf("%s-%d", taskPlaylistMergeUID, userID)
+
+	return p.addTask(ctx, uid, "Playlist Merge", *user, func(ctx context.Context) (string, error) {
+		return p.mergeTask(ctx, *user, uid, sources, target, merge)
+	})
+}
+
+func (p *Playlist) mergeTask(ctx context.Context, user model.User, uid string, sources []model.Playlist, target *model.Playlist, merge dto.PlaylistMerge) (string, error) {
+	total := len(sources) + 1
+
+	tracks := make([]model.Track, 0)
+	for i, source := range sources {
+		sourceTracks, err := p.track.GetByPlaylist(ctx, source.ID)
+		if err != nil {
+			return "", err
+		}
+		tracks = append(tracks, utils.SliceDereference(sourceTracks)...)
+
+		task.Manager.SetProgress(uid, i+1, total)
+	}
+
+	// Tracks without spotify id can't be added with the api
+	tracks = utils.SliceFilter(tracks, func(t model.Track) bool { return t.SpotifyID != "" })
+
+	if merge.Deduplicate {
+		tracks = utils.SliceUniqueFunc(tracks, func(t model.Track) string { return t.SpotifyID })
+
+		if target != nil {
+			existing, err := p.track.GetByPlaylist(ctx, target.ID)
+			if err != nil {
+				return "", err
+			}
+			tracks = utils.SliceFilter(tracks, func(t model.Track) bool {
+				return !slices.ContainsFunc(existing, func(e *model.Track) bool { return e.SpotifyID == t.SpotifyID })
+			})
+		}
+	}
+
+	if target == nil {
+		created, err := createPlaylist(ctx, p.playlist, user, merge.Name, "Merged by Sortifyr")
+		if err != nil {
+			return "", err
+		}
+		target = created
+	}
+
+	if err := spotifyapi.C.PlaylistPostTrackAll(ctx, user, target.SpotifyID, tracks); err != nil {
+		return "", err
+	}
+
+	if err := p.addToDirectory(ctx, user.ID, merge.DirectoryID, target.ID); err != nil {
+		return "", err
+	}
+
+	task.Manager.SetProgress(uid, total, total)
+
+	return fmt.Sprintf("Added %d tracks to %s", len(tracks), target.Name), nil
+}
+
+// Split divides the tracks of a playlist over new playlists, one for each value of the key
+// It runs as a task
+func (p *Playlist) Split(ctx context.Context, userID int, split dto.PlaylistSplit) error {
+	user, err := p.user.GetByID(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+	if user == nil {
+		return fiber.ErrUnauthorized
+	}
+
+	playlists, err := p.playlist.GetByUser(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	source, ok := utils.SliceFind(playlists, func(p *model.Playlist) bool { return p.ID == split.PlaylistID })
+	if !ok {
+		return fiber.ErrNotFound
+	}
+
+	if err := p.validateDirectory(ctx, userID, split.DirectoryID); err != nil {
+		return err
+	}
+
+	uid := fmt.Sprintf("%s-%d", taskPlaylistSplitUID, userID)
+
+	return p.addTask(ctx, uid, "Playlist Split", *user, func(ctx context.Context) (string, error) {
+		return p.splitTask(ctx, *user, uid, **source, split)
+	})
+}
+
+func (p *Playlist) splitTask(ctx context.Context, user model.User, uid string, source model.Playlist, split dto.PlaylistSplit) (string, error) {
+	// Our database doesn't have the release dates
+	tracks, err := spotifyapi.C.PlaylistGetTrackAll(ctx, user, source.SpotifyID)
+	if err != nil {
+		return "", err
+	}
+
+	groups := make(map[string][]model.Track)
+	for _, t := range tracks {
+		if t.SpotifyID == "" {
+			continue
+		}
+
+		key := playlistSplitKey(t, split.Key)
+		groups[key] = append(groups[key], t.ToModel())
+	}
+
+	if len(groups) > playlistSplitMax {
+		return "", fmt.Errorf("split would create %d playlists, the maximum is %d", len(groups), playlistSplitMax)
+	}
+
+	keys := slices.Sorted(maps.Keys(groups))
+	for i, key := range keys {
+		playlist, err := createPlaylist(ctx, p.playlist, user, fmt.Sprintf("%s - %s", source.Name, key), fmt.Sprintf("Split from %s by Sortifyr", source.Name))
+		if err != nil {
+			return "", err
+		}
+
+		groupTracks := utils.SliceUniqueFunc(groups[key], func(t model.Track) string { return t.SpotifyID })
+		if err := spotifyapi.C.PlaylistPostTrackAll(ctx, user, playlist.SpotifyID, groupTracks); err != nil {
+			return "", err
+		}
+
+		if err := p.addToDirectory(ctx, user.ID, split.DirectoryID, playlist.ID); err != nil {
+			return "", err
+		}
+
+		task.Manager.SetProgress(uid, i+1, len(keys))
+	}
+
+	return fmt.Sprintf("Split %s into %d playlists", source.Name, len(keys)), nil
+}
+
+func playlistSplitKey(track spotifyapi.Track, key model.PlaylistSplitKey) string {
+	switch key {
+	case model.PlaylistSplitArtist:
+		if len(track.Artists) > 0 {
+			return track.Artists[0].Name
+		}
+	case model.PlaylistSplitYear:
+		if release := track.Album.ReleaseTime(); !release.IsZero() {
+			return strconv.Itoa(release.Year())
+		}
+	case model.PlaylistSplitDecade:
+		if release := track.Album.ReleaseTime(); !release.IsZero() {
+			return fmt.Sprintf("%ds", release.Year()/10*10)
+		}
+	}
+
+	return "Unknown"
+}
+
+// addTask runs a function once as a task for a single user
+func (p *Playlist) addTask(ctx context.Context, uid, name string, user model.User, fn func(context.Context) (string, error)) error {
+	if err := task.Manager.Add(ctx, task.NewTask(
+		uid,
+		name,
+		task.IntervalOnce,
+		false,
+		func(ctx context.Context, _ []model.User) []task.TaskResult {
+			message, err := fn(ctx)
+			return []task.TaskResult{{
+				User:    user,
+				Message: message,
+				Error:   err,
+			}}
+		},
+	)); err != nil {
+		if errors.Is(err, task.ErrTaskExists) {
+			return fiber.NewError(fiber.StatusBadRequest, "Task is already running")
+		}
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// createPlaylist makes a new private playlist in spotify and saves it
+func createPlaylist(ctx context.Context, repo repository.Playlist, user model.User, name, description string) (*model.Playlist, error) {
+	public := false
+	collaborative := false
+
+	playlist := model.Playlist{
+		OwnerID:       user.ID,
+		Name:          name,
+		Description:   description,
+		Public:        &public,
+		Collaborative: &collaborative,
+	}
+
+	if err := spotifyapi.C.PlaylistCreate(ctx, user, &playlist); err != nil {
+		return nil, err
+	}
+
+	if err := repo.Create(ctx, &playlist); err != nil {
+		return nil, err
+	}
+	if err := repo.CreateUser(ctx, &model.PlaylistUser{UserID: user.ID, PlaylistID: playlist.ID}); err != nil {
+		return nil, err
+	}
+
+	return &playlist, nil
+}
+
+// validateDirectory checks if playlists can be placed in the directory
+// A directory id of 0 is always valid
+func (p *Playlist) validateDirectory(ctx context.Context, userID, directoryID int) error {
+	if directoryID == 0 {
+		return nil
+	}
+
+	directories, err := p.directory.GetByUserPopulated(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	directory, ok := utils.SliceFind(directories, func(d *model.Directory) bool { return d.ID == directoryID })
+	if !ok {
+		return fiber.ErrNotFound
+	}
+	if (*directory).IsSmart() {
+		return fiber.NewError(fiber.StatusBadRequest, "the playlists of a smart directory are determined by its rules")
+	}
+
+	return nil
+}
+
+func (p *Playlist) addToDirectory(ctx context.Context, userID, directoryID, playlistID int) error {
+	if directoryID == 0 {
+		return nil
+	}
+
+	directories, err := p.directory.GetByUserPopulated(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	directory, ok := utils.SliceFind(directories, func(d *model.Directory) bool { return d.ID == directoryID })
+	if !ok {
+		// Deleted in the meantime
+		return nil
+	}
+	if slices.ContainsFunc((*directory).Playlists, func(p model.Playlist) bool { return p.ID == playlistID }) {
+		return nil
+	}
+
+	return p.directory.CreatePlaylist(ctx, &model.DirectoryPlaylist{DirectoryID: directoryID, PlaylistID: playlistID})
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/topvennie/sortifyr/internal/database/model"
+	"github.com/topvennie/sortifyr/internal/database/repository"
+	"github.com/topvennie/sortifyr/internal/server/dto"
+	"github.com/topvennie/sortifyr/internal/spotifyapi"
+	"github.com/topvennie/sortifyr/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	recapTopAmount      = 5
+	recapPlaylistAmount = 50
+)
+
+type Recap struct {
+	service Service
+
+	playlist repository.Playlist
+	recap    repository.Recap
+	stat     repository.Stat
+	user     repository.User
+}
+
+func (s *Service) NewRecap() *Recap {
+	return &Recap{
+		service:  *s,
+		playlist: *s.repo.NewPlaylist(),
+		recap:    *s.repo.NewRecap(),
+		stat:     *s.repo.NewStat(),
+		user:     *s.repo.NewUser(),
+	}
+}
+
+// Get returns the recap of a year or of a month if it's not 0
+// A recap is cached once its period is over, refresh forces a new one
+func (r *Recap) Get(ctx context.Context, userID, year, month int, refresh bool) (dto.Recap, error) {
+	user, err := r.user.GetByID(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return dto.Recap{}, fiber.ErrInternalServerError
+	}
+	if user == nil {
+		return dto.Recap{}, fiber.ErrUnauthorized
+	}
+
+	period := model.Recap{Year: year, Month: month}
+	if err := recapValidate(*user, period); err != nil {
+		return dto.Recap{}, err
+	}
+
+	recap, err := r.recap.GetByUserPeriod(ctx, userID, year, month)
+	if err != nil {
+		zap.S().Error(err)
+		return dto.Recap{}, fiber.ErrInternalServerError
+	}
+
+	_, end := period.Period(user.Location())
+	if recap == nil || refresh || recap.CreatedAt.Before(end) {
+		recap, err = r.generate(ctx, *user, period)
+		if err != nil {
+			zap.S().Error(err)
+			return dto.Recap{}, fiber.ErrInternalServerError
+		}
+	}
+
+	return dto.RecapDTO(recap), nil
+}
+
+// CreatePlaylist creates a spotify playlist with the most played tracks of the period
+func (r *Recap) CreatePlaylist(ctx context.Context, userID, year, month int) (dto.Playlist, error) {
+	user, err := r.user.GetByID(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return dto.Playlist{}, fiber.ErrInternalServerError
+	}
+	if user == nil {
+		return dto.Playlist{}, fiber.ErrUnauthorized
+	}
+
+	period := model.Recap{Year: year, Month: month}
+	if err := recapValidate(*user, period); err != nil {
+		return dto.Playlist{}, err
+	}
+
+	start, end := period.Period(user.Location())
+
+	tracks, err := r.stat.GetTopTracks(ctx, model.StatFilter{UserID: userID, Start: start, End: end.Add(-time.Microsecond), Limit: recapPlaylistAmount})
+	if err != nil {
+		zap.S().Error(err)
+		return dto.Playlist{}, fiber.ErrInternalServerError
+	}
+
+	// Tracks without spotify id can't be added with the api
+	filtered := utils.SliceFilter(tracks, func(t *model.StatTrack) bool { return t.Track.SpotifyID != "" })
+	if len(filtered) == 0 {
+		return dto.Playlist{}, fiber.NewError(fiber.StatusBadRequest, "no tracks played in this period")
+	}
+
+	name := fmt.Sprintf("Recap %d", year)
+	if month != 0 {
+		name = fmt.Sprintf("Recap %s %d", time.Month(month), year)
+	}
+
+	playlist, err := createPlaylist(ctx, r.playlist, *user, name, "Your most played tracks, created by Sortifyr")
+	if err != nil {
+		zap.S().Error(err)
+		return dto.Playlist{}, fiber.ErrInternalServerError
+	}
+
+	if err := spotifyapi.C.PlaylistPostTrackAll(ctx, *user, playlist.SpotifyID, utils.SliceMap(filtered, func(t *model.StatTrack) model.Track { return t.Track })); err != nil {
+		zap.S().Error(err)
+		return dto.Playlist{}, fiber.ErrInternalServerError
+	}
+
+	return dto.PlaylistDTO(playlist, user), nil
+}
+
+// generate calculates a recap with the period boundaries in the user's timezone
+func (r *Recap) generate(ctx context.Context, user model.User, recap model.Recap) (*model.Recap, error) {
+	start, end := recap.Period(user.Location())
+	filter := model.StatFilter{UserID: user.ID, Start: start, End: end.Add(-time.Microsecond), Limit: recapTopAmount}
+
+	tracks, err := r.stat.GetTopTracks(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	artists, err := r.stat.GetTopArtists(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	albums, err := r.stat.GetTopAlbums(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := r.stat.GetSummary(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	streak, err := r.stat.GetLongestStreak(ctx, user.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	hour, err := r.stat.GetTopHour(ctx, user.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	weekday, err := r.stat.GetTopWeekday(ctx, user.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	discovery, err := r.stat.GetTopDiscovery(ctx, user.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	skipped, err := r.stat.GetTopSkipped(ctx, user.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	recap.UserID = user.ID
+	recap.Data = model.RecapData{
+		TopTracks:     utils.SliceMap(tracks, recapTrack),
+		TopArtists:    utils.SliceMap(artists, recapArtist),
+		TopAlbums:     utils.SliceMap(albums, recapAlbum),
+		Duration:      summary.Duration,
+		LongestStreak: streak,
+		TopHour:       hour,
+		TopWeekday:    weekday,
+	}
+	if discovery != nil {
+		item := recapTrack(discovery)
+		recap.Data.Discovery = &item
+	}
+	if skipped != nil {
+		item := recapTrack(skipped)
+		recap.Data.MostSkipped = &item
+	}
+
+	if err := r.recap.Save(ctx, &recap); err != nil {
+		return nil, err
+	}
+	recap.CreatedAt = time.Now()
+
+	return &recap, nil
+}
+
+func recapTrack(t *model.StatTrack) model.RecapItem {
+	return model.RecapItem{ID: t.Track.ID, SpotifyID: t.Track.SpotifyID, Name: t.Track.Name, Plays: t.Plays}
+}
+
+func recapArtist(a *model.StatArtist) model.RecapItem {
+	return model.RecapItem{ID: a.Artist.ID, SpotifyID: a.Artist.SpotifyID, Name: a.Artist.Name, Plays: a.Plays}
+}
+
+func recapAlbum(a *model.StatAlbum) model.RecapItem {
+	return model.RecapItem{ID: a.Album.ID, SpotifyID: a.Album.SpotifyID, Name: a.Album.Name, Plays: a.Plays}
+}
+
+func recapValidate(user model.User, recap model.Recap) error {
+	if recap.Month < 0 || recap.Month > 12 {
+		return fiber.NewError(fiber.StatusBadRequest, "month has to be between 1 and 12 or 0 for the whole year")
+	}
+
+	if start, _ := recap.Period(user.Location()); start.After(time.Now()) {
+		return fiber.NewError(fiber.StatusBadRequest, "the period hasn't started yet")
+	}
+
+	return nil
+}
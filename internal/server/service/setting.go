@@ -2,8 +2,9 @@ package service
 
 import (
 	"archive/zip"
-	"bytes"
+	"bufio"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,17 +12,33 @@ import (
 	"regexp"
 	"slices"
 	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/topvennie/sortifyr/internal/database/model"
 	"github.com/topvennie/sortifyr/internal/database/repository"
+	"github.com/topvennie/sortifyr/internal/server/dto"
+	"github.com/topvennie/sortifyr/internal/spotifyapi"
 	"github.com/topvennie/sortifyr/internal/spotifysync"
 	"github.com/topvennie/sortifyr/internal/task"
+	"github.com/topvennie/sortifyr/pkg/config"
+	"github.com/topvennie/sortifyr/pkg/storage"
+	"github.com/topvennie/sortifyr/pkg/utils"
 	"go.uber.org/zap"
 )
 
-const taskExportUID = "task-export"
+const (
+	taskExportUID = "task-export"
+	taskImportUID = "task-import"
+
+	exportBatchSize  = 1000           // Amount of entries imported at once
+	exportExpiration = 24 * time.Hour // Uploaded exports are removed after the import, this is a fallback
+
+	importDurationTolerance = 10 * time.Second // Max difference between the duration of a scrobble and the matched track
+)
 
 var (
 	exportNameReg  = regexp.MustCompile(`(?i)^.*_audio_.*\.json$`)
@@ -31,21 +48,33 @@ var (
 type Setting struct {
 	service Service
 
+	// Imported plays of the same track starting within the tolerance of an existing play are the same play
+	tolerance time.Duration
+
+	episode repository.Episode
 	history repository.History
+	recap   repository.Recap
+	show    repository.Show
 	track   repository.Track
 	user    repository.User
 }
 
 func (s *Service) NewSetting() *Setting {
 	return &Setting{
-		service: *s,
-		history: *s.repo.NewHistory(),
-		track:   *s.repo.NewTrack(),
-		user:    *s.repo.NewUser(),
+		service:   *s,
+		tolerance: config.GetDefaultDurationS("import.tolerance_s", 30),
+		episode:   *s.repo.NewEpisode(),
+		history:   *s.repo.NewHistory(),
+		recap:     *s.repo.NewRecap(),
+		show:      *s.repo.NewShow(),
+		track:     *s.repo.NewTrack(),
+		user:      *s.repo.NewUser(),
 	}
 }
 
-func (s *Setting) Export(ctx context.Context, userID int, zip []byte) error {
+// Export imports a spotify export
+// The zip is streamed to the storage first so it never has to fit in memory
+func (s *Setting) Export(ctx context.Context, userID int, r io.Reader) error {
 	user, err := s.user.GetByID(ctx, userID)
 	if err != nil {
 		zap.S().Error(err)
@@ -55,55 +84,337 @@ func (s *Setting) Export(ctx context.Context, userID int, zip []byte) error {
 		return fiber.ErrUnauthorized
 	}
 
-	if err := task.Manager.Add(ctx, task.NewTask(
-		taskExportUID,
-		"Import Spotify Export",
-		task.IntervalOnce,
-		false,
-		func(ctx context.Context, _ []model.User) []task.TaskResult {
-			return []task.TaskResult{{
-				User:    *user,
-				Message: "",
-				Error:   s.exportTask(ctx, *user, zip),
-			}}
-		},
-	)); err != nil {
-		if errors.Is(err, task.ErrTaskExists) {
-			return fiber.NewError(fiber.StatusBadRequest, "Task is already running")
+	return s.addFileTask(ctx, *user, fmt.Sprintf("%s-%d", taskExportUID, user.ID), "Import Spotify Export", r, s.exportTask)
+}
+
+// ExportDryRun reports what an import of the export would do without changing anything
+func (s *Setting) ExportDryRun(ctx context.Context, userID int, r io.Reader) (dto.ExportReport, error) {
+	key, err := exportStore(r)
+	if err != nil {
+		return dto.ExportReport{}, err
+	}
+	defer func() {
+		if err := storage.Remove(key); err != nil {
+			zap.S().Error(err)
 		}
-		zap.S().Error(err)
-		return fiber.ErrInternalServerError
+	}()
+
+	zr, err := exportOpen(key)
+	if err != nil {
+		return dto.ExportReport{}, fiber.NewError(fiber.StatusBadRequest, "Invalid zip file")
 	}
 
-	return nil
+	report := dto.ExportReport{Files: []dto.ExportFile{}}
+
+	for _, f := range exportFiles(zr) {
+		file := dto.ExportFile{Name: f.FileInfo().Name()}
+
+		if err := exportDecode(f, func(entries []exportTaskEntry) error {
+			tracks, episodes := exportSplit(model.User{ID: userID}, entries)
+
+			file.Tracks += len(tracks)
+			file.Episodes += len(episodes)
+			file.Ignored += len(entries) - len(tracks) - len(episodes)
+
+			for _, t := range tracks {
+				file.Start, file.End = exportPeriod(file.Start, file.End, t.PlayedAt)
+			}
+			for _, e := range episodes {
+				file.Start, file.End = exportPeriod(file.Start, file.End, e.PlayedAt)
+			}
+
+			if len(tracks) > 0 {
+				existing, err := s.history.CountExisting(ctx, userID, tracks, s.tolerance)
+				if err != nil {
+					zap.S().Error(err)
+					return fiber.ErrInternalServerError
+				}
+				file.Existing += existing
+			}
+			if len(episodes) > 0 {
+				existing, err := s.episode.CountHistoryExisting(ctx, userID, episodes, s.tolerance)
+				if err != nil {
+					zap.S().Error(err)
+					return fiber.ErrInternalServerError
+				}
+				file.Existing += existing
+			}
+
+			return nil
+		}); err != nil {
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				return dto.ExportReport{}, err
+			}
+			return dto.ExportReport{}, fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+
+		report.Files = append(report.Files, file)
+
+		report.Tracks += file.Tracks
+		report.Episodes += file.Episodes
+		report.Ignored += file.Ignored
+		report.Existing += file.Existing
+		if !file.Start.IsZero() {
+			report.Start, report.End = exportPeriod(report.Start, report.End, file.Start)
+			report.Start, report.End = exportPeriod(report.Start, report.End, file.End)
+		}
+	}
+
+	return report, nil
+}
+
+type exportTaskEntry struct {
+	StoppedAt         time.Time `json:"ts"`
+	Username          string    `json:"username"`
+	Platform          string    `json:"platform"`
+	MsPlayed          int       `json:"ms_played"`
+	ConnCountry       string    `json:"conn_country"`
+	SpotifyTrackURI   string    `json:"spotify_track_uri"`
+	SpotifyEpisodeURI string    `json:"spotify_episode_uri"`
+	ReasonStart       string    `json:"reason_start"`
+	ReasonEnd         string    `json:"reason_end"`
+	Shuffle           bool      `json:"shuffle"`
+	Skipped           bool      `json:"skipped"`
+	Offline           bool      `json:"offline"`
 }
 
-type exportTaskTrack struct {
-	StoppedAt       time.Time `json:"ts"`
-	Username        string    `json:"username"`
-	MsPlayed        int       `json:"ms_played"`
-	SpotifyTrackURI string    `json:"spotify_track_uri"`
-	Skipped         bool      `json:"skipped"`
+func (e exportTaskEntry) playedAt() time.Time {
+	return e.StoppedAt.Add(time.Duration(-1*e.MsPlayed) * time.Millisecond)
 }
 
-func (e exportTaskTrack) toHistory(userID int) *model.History {
+func (e exportTaskEntry) toHistory(userID int) *model.History {
 	return &model.History{
-		UserID:   userID,
-		PlayedAt: e.StoppedAt.Add(time.Duration(-1*e.MsPlayed) * time.Millisecond),
-		Skipped:  &e.Skipped,
+		UserID:      userID,
+		PlayedAt:    e.playedAt(),
+		Skipped:     &e.Skipped,
+		ReasonStart: e.ReasonStart,
+		ReasonEnd:   e.ReasonEnd,
+		Shuffle:     &e.Shuffle,
+		Offline:     &e.Offline,
+		Platform:    e.Platform,
+		ConnCountry: e.ConnCountry,
 		Track: model.Track{
 			SpotifyID: spotifysync.URIToID(e.SpotifyTrackURI),
 		},
 	}
 }
 
-func (s *Setting) exportTask(ctx context.Context, user model.User, zipFile []byte) error {
-	zr, err := zip.NewReader(bytes.NewReader(zipFile), int64(len(zipFile)))
+func (e exportTaskEntry) toEpisodeHistory(userID int) *model.EpisodeHistory {
+	return &model.EpisodeHistory{
+		UserID:      userID,
+		PlayedAt:    e.playedAt(),
+		PositionMs:  e.MsPlayed, // The export doesn't contain the position, the time listened is the closest we have
+		ReasonStart: e.ReasonStart,
+		ReasonEnd:   e.ReasonEnd,
+		Shuffle:     &e.Shuffle,
+		Offline:     &e.Offline,
+		Platform:    e.Platform,
+		ConnCountry: e.ConnCountry,
+		Episode: model.Episode{
+			SpotifyID: spotifysync.URIToID(e.SpotifyEpisodeURI),
+		},
+	}
+}
+
+// exportTask merges the export with the existing history
+// Importing the same export multiple times is safe, known plays are never duplicated or removed.
+func (s *Setting) exportTask(ctx context.Context, user model.User, key string) (string, error) {
+	zr, err := exportOpen(key)
+	if err != nil {
+		return "", err
+	}
+
+	files := exportFiles(zr)
+	if len(files) == 0 {
+		return "", nil
+	}
+
+	var tracks, episodes int
+
+	for _, f := range files {
+		if err := exportDecode(f, func(entries []exportTaskEntry) error {
+			t, e, err := s.exportTaskFile(ctx, user, entries)
+			if err != nil {
+				return err
+			}
+			tracks += t
+			episodes += e
+
+			return nil
+		}); err != nil {
+			return "", err
+		}
+	}
+
+	if err := task.Manager.RunRecurringByUID(spotifysync.TaskTrackUID, user); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Added %d track and %d episode plays", tracks, episodes), nil
+}
+
+// exportTaskFile imports a batch of entries
+// It returns the amount of new track and episode plays
+func (s *Setting) exportTaskFile(ctx context.Context, user model.User, entries []exportTaskEntry) (int, int, error) {
+	tracksHistory, episodesHistory := exportSplit(user, entries)
+
+	tracks, err := s.exportTaskTracks(ctx, tracksHistory)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	episodes, err := s.exportTaskEpisodes(ctx, user, episodesHistory)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return tracks, episodes, nil
+}
+
+func (s *Setting) exportTaskTracks(ctx context.Context, exportHistory []model.History) (int, error) {
+	if len(exportHistory) == 0 {
+		return 0, nil
+	}
+
+	trackIDs, err := s.trackIDs(ctx, utils.SliceMap(exportHistory, func(h model.History) string { return h.Track.SpotifyID }))
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range exportHistory {
+		exportHistory[i].TrackID = trackIDs[exportHistory[i].Track.SpotifyID]
+	}
+
+	return s.historyMerge(ctx, exportHistory, model.HistorySourceExport)
+}
+
+// historyMerge merges imported plays in the history
+// Recaps are cached, the ones covering a new play are removed so that they are generated again
+func (s *Setting) historyMerge(ctx context.Context, histories []model.History, source model.HistorySource) (int, error) {
+	added, err := s.history.MergeBatch(ctx, histories, source, s.tolerance)
+	if err != nil {
+		return 0, err
+	}
+	if added == 0 {
+		return 0, nil
+	}
+
+	start, end := histories[0].PlayedAt, histories[0].PlayedAt
+	for _, h := range histories[1:] {
+		start, end = exportPeriod(start, end, h.PlayedAt)
+	}
+
+	if err := s.recap.DeleteByUserOverlap(ctx, histories[0].UserID, start, end); err != nil {
+		return 0, err
+	}
+
+	return added, nil
+}
+
+func (s *Setting) exportTaskEpisodes(ctx context.Context, user model.User, exportHistory []model.EpisodeHistory) (int, error) {
+	if len(exportHistory) == 0 {
+		return 0, nil
+	}
+
+	spotifyIDs := utils.SliceUnique(utils.SliceMap(exportHistory, func(h model.EpisodeHistory) string { return h.Episode.SpotifyID }))
+
+	// Get all already saved episodes
+	episodesDB, err := s.episode.GetAllBySpotify(ctx, spotifyIDs)
+	if err != nil {
+		return 0, err
+	}
+	episodeMap := make(map[string]int)
+	for i := range episodesDB {
+		episodeMap[episodesDB[i].SpotifyID] = episodesDB[i].ID
+	}
+
+	// An episode requires a show so new episodes are retrieved from spotify
+	missing := utils.SliceFilter(spotifyIDs, func(id string) bool {
+		_, ok := episodeMap[id]
+		return !ok
+	})
+	if len(missing) > 0 {
+		episodesAPI, err := spotifyapi.C.EpisodeGetAll(ctx, user, missing)
+		if err != nil {
+			return 0, err
+		}
+
+		for _, e := range episodesAPI {
+			episode := e.ToModel()
+			if err := s.exportEpisodeCreate(ctx, &episode); err != nil {
+				return 0, err
+			}
+			episodeMap[episode.SpotifyID] = episode.ID
+		}
+	}
+
+	// Episodes no longer available on spotify are left out
+	exportHistory = utils.SliceFilter(exportHistory, func(h model.EpisodeHistory) bool {
+		_, ok := episodeMap[h.Episode.SpotifyID]
+		return ok
+	})
+	if len(exportHistory) == 0 {
+		return 0, nil
+	}
+	for i := range exportHistory {
+		exportHistory[i].EpisodeID = episodeMap[exportHistory[i].Episode.SpotifyID]
+	}
+
+	return s.episode.MergeHistoryBatch(ctx, exportHistory, model.HistorySourceExport, s.tolerance)
+}
+
+// trackIDs returns the id of each track by spotify id
+// Tracks we don't have yet are created, the track task fills in the rest
+func (s *Setting) trackIDs(ctx context.Context, spotifyIDs []string) (map[string]int, error) {
+	spotifyIDs = utils.SliceUnique(spotifyIDs)
+
+	// Get all already saved tracks
+	tracksDB, err := s.track.GetAllBySpotify(ctx, spotifyIDs)
+	if err != nil {
+		return nil, err
+	}
+	trackMap := make(map[string]int, len(spotifyIDs))
+	for i := range tracksDB {
+		trackMap[tracksDB[i].SpotifyID] = tracksDB[i].ID
+	}
+
+	// Populate db with any new track
+	for _, spotifyID := range spotifyIDs {
+		if _, ok := trackMap[spotifyID]; ok {
+			continue
+		}
+
+		track := model.Track{SpotifyID: spotifyID}
+		if err := s.track.Create(ctx, &track); err != nil {
+			return nil, err
+		}
+		trackMap[track.SpotifyID] = track.ID
+	}
+
+	return trackMap, nil
+}
+
+// exportEpisodeCreate creates a new episode together with its show if needed
+func (s *Setting) exportEpisodeCreate(ctx context.Context, episode *model.Episode) error {
+	show, err := s.show.GetBySpotify(ctx, episode.Show.SpotifyID)
 	if err != nil {
-		return fmt.Errorf("read zip file %w", err)
+		return err
 	}
+	if show == nil {
+		show = &episode.Show
+		if err := s.show.Create(ctx, show); err != nil {
+			return err
+		}
+	}
+	episode.ShowID = show.ID
+
+	return s.episode.Create(ctx, episode)
+}
 
-	// Get all files
+// exportFiles returns all audio history files in the zip
+// They're sorted from the most recent to the oldest
+func exportFiles(zr *zip.Reader) []*zip.File {
 	files := make([]*zip.File, 0)
 
 	for _, f := range zr.File {
@@ -119,12 +430,8 @@ func (s *Setting) exportTask(ctx context.Context, user model.User, zipFile []byt
 
 		files = append(files, f)
 	}
-	if len(files) == 0 {
-		return nil
-	}
 
 	// Go from the most recent to the oldest
-	// Required for the exportFile function
 	slices.SortFunc(files, func(a, b *zip.File) int {
 		aIdx := exportIndex(a.FileInfo().Name())
 		bIdx := exportIndex(b.FileInfo().Name())
@@ -132,103 +439,782 @@ func (s *Setting) exportTask(ctx context.Context, user model.User, zipFile []byt
 		return bIdx - aIdx
 	})
 
-	for _, f := range files {
-		rc, err := f.Open()
-		if err != nil {
-			return fmt.Errorf("open file %s | %w", f.Name, err)
+	return files
+}
+
+// addFileTask stores the uploaded file and starts a task to process it
+// The file is removed once the task is done
+func (s *Setting) addFileTask(ctx context.Context, user model.User, uid, name string, r io.Reader, fn func(context.Context, model.User, string) (string, error)) error {
+	key, err := exportStore(r)
+	if err != nil {
+		return err
+	}
+
+	if err := task.Manager.Add(ctx, task.NewTask(
+		uid,
+		name,
+		task.IntervalOnce,
+		false,
+		func(ctx context.Context, _ []model.User) []task.TaskResult {
+			message, err := fn(ctx, user, key)
+
+			if err := storage.Remove(key); err != nil {
+				zap.S().Error(err)
+			}
+
+			return []task.TaskResult{{
+				User:    user,
+				Message: message,
+				Error:   err,
+			}}
+		},
+	)); err != nil {
+		if err := storage.Remove(key); err != nil {
+			zap.S().Error(err)
 		}
 
-		content, err := io.ReadAll(rc)
-		rc.Close()
-		if err != nil {
-			return fmt.Errorf("read file content %s | %w", f.FileInfo().Name(), err)
+		if errors.Is(err, task.ErrTaskExists) {
+			return fiber.NewError(fiber.StatusBadRequest, "Task is already running")
 		}
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
 
-		if err := s.exportTaskFile(ctx, user, content); err != nil {
+	return nil
+}
+
+// exportStore streams the upload to the storage and returns its key
+func exportStore(r io.Reader) (string, error) {
+	key := "export-" + uuid.NewString()
+
+	if _, err := storage.Write(key, r, exportExpiration); err != nil {
+		zap.S().Error(err)
+		return "", fiber.ErrInternalServerError
+	}
+
+	return key, nil
+}
+
+// exportOpen opens a stored export
+// Only the parts that are being read are loaded in memory
+func exportOpen(key string) (*zip.Reader, error) {
+	file, err := storage.Open(key)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, fmt.Errorf("export %s not found", key)
+	}
+
+	zr, err := zip.NewReader(file, file.Size())
+	if err != nil {
+		return nil, fmt.Errorf("read zip file %w", err)
+	}
+
+	return zr, nil
+}
+
+// exportDecode decodes the json array in the file one entry at a time
+// The entries are passed to fn in batches, the slice is reused after fn returns
+func exportDecode(f *zip.File, fn func([]exportTaskEntry) error) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("open file %s | %w", f.Name, err)
+	}
+	defer func() {
+		// nolint:errcheck // Only read from it
+		_ = rc.Close()
+	}()
+
+	dec := json.NewDecoder(rc)
+
+	if t, err := dec.Token(); err != nil || t != json.Delim('[') {
+		return fmt.Errorf("parse file content %s | expected a json array", f.FileInfo().Name())
+	}
+
+	entries := make([]exportTaskEntry, 0, exportBatchSize)
+
+	for dec.More() {
+		var entry exportTaskEntry
+		if err := dec.Decode(&entry); err != nil {
+			return fmt.Errorf("parse file content to json %s | %w", f.FileInfo().Name(), err)
+		}
+
+		entries = append(entries, entry)
+
+		if len(entries) == exportBatchSize {
+			if err := fn(entries); err != nil {
+				return err
+			}
+			entries = entries[:0]
+		}
+	}
+
+	if len(entries) > 0 {
+		if err := fn(entries); err != nil {
 			return err
 		}
 	}
 
-	if err := task.Manager.RunRecurringByUID(spotifysync.TaskTrackUID, user); err != nil {
+	return nil
+}
+
+// exportPeriod extends the period to include t
+func exportPeriod(start, end, t time.Time) (time.Time, time.Time) {
+	if start.IsZero() || t.Before(start) {
+		start = t
+	}
+	if t.After(end) {
+		end = t
+	}
+
+	return start, end
+}
+
+// exportSplit separates the track and episode entries
+// Entries without either, for example audiobooks, are ignored
+// Both results are sorted from the oldest to the most recent
+func exportSplit(user model.User, entries []exportTaskEntry) ([]model.History, []model.EpisodeHistory) {
+	tracks := make([]model.History, 0, len(entries))
+	episodes := make([]model.EpisodeHistory, 0)
+
+	for _, e := range entries {
+		switch {
+		case e.SpotifyTrackURI != "":
+			tracks = append(tracks, *e.toHistory(user.ID))
+		case e.SpotifyEpisodeURI != "":
+			episodes = append(episodes, *e.toEpisodeHistory(user.ID))
+		}
+	}
+
+	slices.SortFunc(tracks, func(a, b model.History) int { return a.PlayedAt.Compare(b.PlayedAt) })
+	slices.SortFunc(episodes, func(a, b model.EpisodeHistory) int { return a.PlayedAt.Compare(b.PlayedAt) })
+
+	return tracks, episodes
+}
+
+func exportIndex(name string) int {
+	matches := exportIndexReg.FindStringSubmatch(name)
+	if len(matches) != 2 {
+		return -1
+	}
+
+	idx, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return -1
+	}
+
+	return idx
+}
+
+// Import imports the listening history of another service
+// Plays are matched to the tracks we know by artist, title and duration
+func (s *Setting) Import(ctx context.Context, userID int, source string, r io.Reader) error {
+	user, err := s.user.GetByID(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+	if user == nil {
+		return fiber.ErrUnauthorized
+	}
+
+	var name string
+	var decode importDecoder
+
+	switch model.HistorySource(source) {
+	case model.HistorySourceLastFM:
+		name = "Import Last.fm"
+		decode = importLastFM
+	case model.HistorySourceListenBrainz:
+		name = "Import ListenBrainz"
+		decode = importListenBrainz
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "Unknown import source")
+	}
+
+	return s.addFileTask(ctx, *user, fmt.Sprintf("%s-%s-%d", taskImportUID, source, user.ID), name, r, func(ctx context.Context, user model.User, key string) (string, error) {
+		return s.importTask(ctx, user, key, model.HistorySource(source), decode)
+	})
+}
+
+// GetImportUnmatched returns the plays of the last import that couldn't be matched to a track
+func (s *Setting) GetImportUnmatched(_ context.Context, userID int) ([]dto.ImportUnmatched, error) {
+	data, err := storage.Get(importUnmatchedKey(userID))
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if len(data) == 0 {
+		return []dto.ImportUnmatched{}, nil
+	}
+
+	var unmatched []dto.ImportUnmatched
+	_ = json.Unmarshal(data, &unmatched) // nolint:errcheck // Data controlled by us
+
+	return unmatched, nil
+}
+
+// importScrobble is a play from another service
+type importScrobble struct {
+	Artist     string
+	Title      string
+	Album      string
+	DurationMs int    // 0 if unknown
+	SpotifyID  string // Empty if unknown
+	PlayedAt   time.Time
+}
+
+// importDecoder passes every scrobble in the file to add
+type importDecoder func(r io.ReaderAt, size int64, add func(importScrobble) error) error
+
+// importState is kept for the whole import
+type importState struct {
+	source model.HistorySource
+
+	matches   map[model.TrackMatch]int // Track id, 0 if unmatched
+	unmatched map[[2]string]*dto.ImportUnmatched
+	added     int
+}
+
+func (s *Setting) importTask(ctx context.Context, user model.User, key string, source model.HistorySource, decode importDecoder) (string, error) {
+	file, err := storage.Open(key)
+	if err != nil {
+		return "", err
+	}
+	if file == nil {
+		return "", fmt.Errorf("import %s not found", key)
+	}
+
+	state := importState{
+		source:    source,
+		matches:   make(map[model.TrackMatch]int),
+		unmatched: make(map[[2]string]*dto.ImportUnmatched),
+	}
+
+	scrobbles := make([]importScrobble, 0, exportBatchSize)
+
+	if err := decode(file, file.Size(), func(scrobble importScrobble) error {
+		scrobbles = append(scrobbles, scrobble)
+		if len(scrobbles) < exportBatchSize {
+			return nil
+		}
+
+		err := s.importBatch(ctx, user, scrobbles, &state)
+		scrobbles = scrobbles[:0]
+
 		return err
+	}); err != nil {
+		return "", err
+	}
+	if len(scrobbles) > 0 {
+		if err := s.importBatch(ctx, user, scrobbles, &state); err != nil {
+			return "", err
+		}
 	}
 
-	return nil
+	// Save the unmatched plays for review
+	unmatched := utils.MapValues(state.unmatched)
+	slices.SortFunc(unmatched, func(a, b *dto.ImportUnmatched) int { return b.Plays - a.Plays })
+
+	data, err := json.Marshal(unmatched)
+	if err != nil {
+		return "", fmt.Errorf("marshal unmatched plays %w", err)
+	}
+	if err := storage.S.Set(importUnmatchedKey(user.ID), data, 0); err != nil {
+		return "", fmt.Errorf("store unmatched plays %w", err)
+	}
+
+	if err := task.Manager.RunRecurringByUID(spotifysync.TaskTrackUID, user); err != nil {
+		return "", err
+	}
+
+	unmatchedPlays := 0
+	for _, u := range unmatched {
+		unmatchedPlays += u.Plays
+	}
+
+	return fmt.Sprintf("Added %d plays, %d plays could not be matched", state.added, unmatchedPlays), nil
 }
 
-func (s *Setting) exportTaskFile(ctx context.Context, user model.User, file []byte) error {
-	var exportTracks []exportTaskTrack
-	if err := json.Unmarshal(file, &exportTracks); err != nil {
-		return fmt.Errorf("parse file content to json %w", err)
+func (s *Setting) importBatch(ctx context.Context, user model.User, scrobbles []importScrobble, state *importState) error {
+	// Scrobbles with a spotify id don't need to be matched
+	spotifyIDs := make([]string, 0)
+	for _, scrobble := range scrobbles {
+		if scrobble.SpotifyID != "" {
+			spotifyIDs = append(spotifyIDs, scrobble.SpotifyID)
+		}
 	}
 
-	// Get all entries
-	exportHistory := make([]model.History, 0, len(exportTracks))
-	spotifyIDs := make([]string, 0, len(exportTracks))
-	for _, t := range exportTracks {
-		if t.SpotifyTrackURI == "" {
+	trackIDs, err := s.trackIDs(ctx, spotifyIDs)
+	if err != nil {
+		return err
+	}
+
+	// Match the others on their metadata
+	matches := make([]model.TrackMatch, 0)
+	for _, scrobble := range scrobbles {
+		if scrobble.SpotifyID != "" {
 			continue
 		}
 
-		h := *t.toHistory(user.ID)
+		match := importMatch(scrobble)
+		if _, ok := state.matches[match]; ok {
+			continue
+		}
 
-		exportHistory = append(exportHistory, h)
-		spotifyIDs = append(spotifyIDs, h.Track.SpotifyID)
+		state.matches[match] = 0
+		matches = append(matches, match)
 	}
-	slices.SortFunc(exportHistory, func(a, b model.History) int { return int(a.PlayedAt.UnixMilli() - b.PlayedAt.UnixMilli()) })
 
-	if len(exportHistory) == 0 {
+	if len(matches) > 0 {
+		found, err := s.track.GetMatches(ctx, matches, importDurationTolerance)
+		if err != nil {
+			return err
+		}
+
+		for idx, trackID := range found {
+			state.matches[matches[idx]] = trackID
+		}
+	}
+
+	histories := make([]model.History, 0, len(scrobbles))
+	for _, scrobble := range scrobbles {
+		trackID := trackIDs[scrobble.SpotifyID]
+		if scrobble.SpotifyID == "" {
+			trackID = state.matches[importMatch(scrobble)]
+		}
+
+		if trackID == 0 {
+			key := [2]string{strings.ToLower(scrobble.Artist), strings.ToLower(scrobble.Title)}
+			if _, ok := state.unmatched[key]; !ok {
+				state.unmatched[key] = &dto.ImportUnmatched{Artist: scrobble.Artist, Title: scrobble.Title, Album: scrobble.Album}
+			}
+			state.unmatched[key].Plays++
+
+			continue
+		}
+
+		histories = append(histories, model.History{
+			UserID:   user.ID,
+			TrackID:  trackID,
+			PlayedAt: scrobble.PlayedAt,
+			Source:   state.source,
+		})
+	}
+
+	if len(histories) == 0 {
 		return nil
 	}
 
-	// Get all already saved tracks
-	tracksDB, err := s.track.GetAllBySpotify(ctx, spotifyIDs)
+	added, err := s.historyMerge(ctx, histories, state.source)
 	if err != nil {
 		return err
 	}
-	trackMap := make(map[string]int)
-	for i := range tracksDB {
-		trackMap[tracksDB[i].SpotifyID] = tracksDB[i].ID
+	state.added += added
+
+	return nil
+}
+
+func importMatch(scrobble importScrobble) model.TrackMatch {
+	return model.TrackMatch{
+		Artist:     strings.ToLower(strings.TrimSpace(scrobble.Artist)),
+		Name:       strings.ToLower(strings.TrimSpace(scrobble.Title)),
+		DurationMs: scrobble.DurationMs,
 	}
+}
 
-	// Populate db with any new track
-	for i := range exportHistory {
-		trackID, ok := trackMap[exportHistory[i].Track.SpotifyID]
-		if !ok {
-			// We don't have the track yet
-			track := model.Track{SpotifyID: exportHistory[i].Track.SpotifyID}
-			if err := s.track.Create(ctx, &track); err != nil {
+func importUnmatchedKey(userID int) string {
+	return fmt.Sprintf("import-unmatched-%d", userID)
+}
+
+// importLastFM decodes a Last.fm scrobble export
+// Both the csv and json exports are supported.
+func importLastFM(r io.ReaderAt, size int64, add func(importScrobble) error) error {
+	br := bufio.NewReader(io.NewSectionReader(r, 0, size))
+
+	first, err := importPeek(br)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return err
+	}
+
+	if first == '[' || first == '{' {
+		return importLastFMJSON(br, add)
+	}
+
+	return importLastFMCSV(br, add)
+}
+
+// importLastFMCSV decodes a csv export
+// Without a header the columns are artist, album, title and date
+func importLastFMCSV(r io.Reader, add func(importScrobble) error) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	columns := map[string]int{"artist": 0, "album": 1, "title": 2, "date": 3}
+
+	record, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return fmt.Errorf("read csv header %w", err)
+	}
+
+	header := make(map[string]int, len(record))
+	for i, column := range record {
+		header[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	if _, ok := header["artist"]; ok {
+		for column, names := range map[string][]string{
+			"artist": {"artist"},
+			"album":  {"album"},
+			"title":  {"track", "title", "name"},
+			"date":   {"uts", "timestamp", "date", "utc_time"},
+		} {
+			columns[column] = -1
+			for _, name := range names {
+				if idx, ok := header[name]; ok {
+					columns[column] = idx
+					break
+				}
+			}
+		}
+
+		record, err = cr.Read()
+	}
+
+	for ; err == nil; record, err = cr.Read() {
+		field := func(column string) string {
+			idx := columns[column]
+			if idx < 0 || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+
+		playedAt, ok := importTime(field("date"))
+		if !ok || field("artist") == "" || field("title") == "" {
+			continue
+		}
+
+		if err := add(importScrobble{
+			Artist:   field("artist"),
+			Title:    field("title"),
+			Album:    field("album"),
+			PlayedAt: playedAt,
+		}); err != nil {
+			return err
+		}
+	}
+	if !errors.Is(err, io.EOF) {
+		return fmt.Errorf("read csv %w", err)
+	}
+
+	return nil
+}
+
+// lastFMText is either a plain string or an object with the value in #text or name
+type lastFMText struct {
+	Text string `json:"#text"`
+	Name string `json:"name"`
+}
+
+func (l *lastFMText) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		l.Text = text
+		return nil
+	}
+
+	type plain lastFMText
+	return json.Unmarshal(data, (*plain)(l))
+}
+
+func (l lastFMText) value() string {
+	if l.Text != "" {
+		return l.Text
+	}
+	return l.Name
+}
+
+type lastFMTrack struct {
+	Artist lastFMText `json:"artist"`
+	Album  lastFMText `json:"album"`
+	Name   string     `json:"name"`
+	Date   struct {
+		UTS string `json:"uts"`
+	} `json:"date"`
+}
+
+// lastFMPage is a page of the recent tracks endpoint
+// Exports are either a list of pages, a single response or a list of tracks
+type lastFMPage struct {
+	Track        []lastFMTrack `json:"track"`
+	RecentTracks *struct {
+		Track []lastFMTrack `json:"track"`
+	} `json:"recenttracks"`
+}
+
+func importLastFMJSON(r *bufio.Reader, add func(importScrobble) error) error {
+	handle := func(raw json.RawMessage) error {
+		var page lastFMPage
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return fmt.Errorf("parse last.fm json %w", err)
+		}
+
+		tracks := page.Track
+		if page.RecentTracks != nil {
+			tracks = page.RecentTracks.Track
+		}
+		if tracks == nil {
+			var track lastFMTrack
+			if err := json.Unmarshal(raw, &track); err != nil {
+				return fmt.Errorf("parse last.fm json %w", err)
+			}
+			tracks = []lastFMTrack{track}
+		}
+
+		for _, t := range tracks {
+			// The track that's currently playing has no date
+			playedAt, ok := importTime(t.Date.UTS)
+			if !ok || t.Artist.value() == "" || t.Name == "" {
+				continue
+			}
+
+			if err := add(importScrobble{
+				Artist:   t.Artist.value(),
+				Title:    t.Name,
+				Album:    t.Album.value(),
+				PlayedAt: playedAt,
+			}); err != nil {
 				return err
 			}
-			trackID = track.ID
-			trackMap[track.SpotifyID] = track.ID
 		}
 
-		exportHistory[i].TrackID = trackID
+		return nil
 	}
 
-	// Delete the old entries
-	// This logic assumes that we go from the most recent track to the oldest
-	if err := s.history.DeleteOlder(ctx, user.ID, exportHistory[len(exportHistory)-1].PlayedAt); err != nil {
+	dec := json.NewDecoder(r)
+
+	first, err := importPeek(r)
+	if err != nil {
 		return err
 	}
-	if err := s.history.CreateBatch(ctx, exportHistory); err != nil {
-		return err
+	if first != '[' {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return fmt.Errorf("parse last.fm json %w", err)
+		}
+		return handle(raw)
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("parse last.fm json %w", err)
+	}
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return fmt.Errorf("parse last.fm json %w", err)
+		}
+		if err := handle(raw); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func exportIndex(name string) int {
-	matches := exportIndexReg.FindStringSubmatch(name)
-	if len(matches) != 2 {
-		return -1
+type listenBrainzListen struct {
+	ListenedAt    int64 `json:"listened_at"`
+	TrackMetadata struct {
+		ArtistName     string `json:"artist_name"`
+		TrackName      string `json:"track_name"`
+		ReleaseName    string `json:"release_name"`
+		AdditionalInfo struct {
+			DurationMs int    `json:"duration_ms"`
+			Duration   int    `json:"duration"` // Seconds
+			SpotifyID  string `json:"spotify_id"`
+			OriginURL  string `json:"origin_url"`
+		} `json:"additional_info"`
+	} `json:"track_metadata"`
+}
+
+func (l listenBrainzListen) toScrobble() importScrobble {
+	metadata := l.TrackMetadata
+	info := metadata.AdditionalInfo
+
+	durationMs := info.DurationMs
+	if durationMs == 0 {
+		durationMs = info.Duration * 1000
 	}
 
-	idx, err := strconv.Atoi(matches[1])
+	spotifyID := listenBrainzSpotifyID(info.SpotifyID)
+	if spotifyID == "" {
+		spotifyID = listenBrainzSpotifyID(info.OriginURL)
+	}
+
+	return importScrobble{
+		Artist:     metadata.ArtistName,
+		Title:      metadata.TrackName,
+		Album:      metadata.ReleaseName,
+		DurationMs: durationMs,
+		SpotifyID:  spotifyID,
+		PlayedAt:   time.Unix(l.ListenedAt, 0),
+	}
+}
+
+// listenBrainzSpotifyID extracts the track id from a spotify url
+func listenBrainzSpotifyID(url string) string {
+	_, id, ok := strings.Cut(url, "open.spotify.com/track/")
+	if !ok {
+		return ""
+	}
+
+	id, _, _ = strings.Cut(id, "?")
+
+	return id
+}
+
+// importListenBrainz decodes a ListenBrainz dump
+// It's either the export zip or a file with a json array or json lines of listens.
+func importListenBrainz(r io.ReaderAt, size int64, add func(importScrobble) error) error {
+	magic := make([]byte, 4)
+	if _, err := r.ReadAt(magic, 0); err == nil && string(magic) == "PK\x03\x04" {
+		zr, err := zip.NewReader(r, size)
+		if err != nil {
+			return fmt.Errorf("read zip file %w", err)
+		}
+
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() || !strings.Contains(f.Name, "listens") {
+				continue
+			}
+			if !strings.HasSuffix(f.Name, ".json") && !strings.HasSuffix(f.Name, ".jsonl") {
+				continue
+			}
+
+			rc, err := f.Open()
+			if err != nil {
+				return fmt.Errorf("open file %s | %w", f.Name, err)
+			}
+
+			err = importListenBrainzListens(rc, add)
+			rc.Close()
+			if err != nil {
+				return fmt.Errorf("file %s | %w", f.Name, err)
+			}
+		}
+
+		return nil
+	}
+
+	return importListenBrainzListens(io.NewSectionReader(r, 0, size), add)
+}
+
+func importListenBrainzListens(r io.Reader, add func(importScrobble) error) error {
+	br := bufio.NewReader(r)
+
+	first, err := importPeek(br)
 	if err != nil {
-		return -1
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return err
 	}
 
-	return idx
+	dec := json.NewDecoder(br)
+
+	handle := func(listen listenBrainzListen) error {
+		if listen.ListenedAt == 0 || listen.TrackMetadata.ArtistName == "" || listen.TrackMetadata.TrackName == "" {
+			return nil
+		}
+		return add(listen.toScrobble())
+	}
+
+	if first == '[' {
+		if _, err := dec.Token(); err != nil {
+			return fmt.Errorf("parse listenbrainz json %w", err)
+		}
+		for dec.More() {
+			var listen listenBrainzListen
+			if err := dec.Decode(&listen); err != nil {
+				return fmt.Errorf("parse listenbrainz json %w", err)
+			}
+			if err := handle(listen); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// Json lines
+	for {
+		var listen listenBrainzListen
+		if err := dec.Decode(&listen); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("parse listenbrainz json %w", err)
+		}
+		if err := handle(listen); err != nil {
+			return err
+		}
+	}
+}
+
+// importPeek returns the first non whitespace character without consuming it
+func importPeek(br *bufio.Reader) (byte, error) {
+	// Skip the byte order mark
+	if bom, err := br.Peek(3); err == nil && string(bom) == "\xef\xbb\xbf" {
+		_, _ = br.Discard(3) // nolint:errcheck // Already peeked
+	}
+
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		if !unicode.IsSpace(rune(b[0])) {
+			return b[0], nil
+		}
+		_, _ = br.Discard(1) // nolint:errcheck // Already peeked
+	}
+}
+
+// importTime parses the time formats used by the exports
+// Times without a timezone are in UTC
+func importTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		// Some exports use milliseconds
+		if unix > 1e11 {
+			return time.UnixMilli(unix), true
+		}
+		return time.Unix(unix, 0), true
+	}
+
+	for _, layout := range []string{
+		time.RFC3339,
+		time.DateTime,
+		"02 Jan 2006 15:04",
+		"2 Jan 2006 15:04",
+		"02 Jan 2006, 15:04",
+		"2 Jan 2006, 15:04",
+	} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
 }
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/topvennie/sortifyr/internal/database/repository"
+	"github.com/topvennie/sortifyr/internal/server/dto"
+	"github.com/topvennie/sortifyr/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type Show struct {
+	service Service
+
+	episode repository.Episode
+	show    repository.Show
+}
+
+func (s *Service) NewShow() *Show {
+	return &Show{
+		service: *s,
+		episode: *s.repo.NewEpisode(),
+		show:    *s.repo.NewShow(),
+	}
+}
+
+func (s *Show) GetByUser(ctx context.Context, userID int) ([]dto.Show, error) {
+	shows, err := s.show.GetByUser(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+	if shows == nil {
+		return []dto.Show{}, nil
+	}
+
+	return utils.SliceMap(shows, dto.ShowDTO), nil
+}
+
+func (s *Show) GetHistory(ctx context.Context, filter dto.EpisodeHistoryFilter) ([]dto.EpisodeHistory, error) {
+	histories, err := s.episode.GetHistoryPopulatedFilteredPaginated(ctx, *filter.ToModel())
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return utils.SliceMap(histories, dto.EpisodeHistoryDTO), nil
+}
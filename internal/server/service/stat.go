@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/topvennie/sortifyr/internal/database/repository"
+	"github.com/topvennie/sortifyr/internal/server/dto"
+	"github.com/topvennie/sortifyr/pkg/utils"
+	"go.uber.org/zap"
+)
+
+type Stat struct {
+	service Service
+
+	stat repository.Stat
+}
+
+func (s *Service) NewStat() *Stat {
+	return &Stat{
+		service: *s,
+		stat:    *s.repo.NewStat(),
+	}
+}
+
+func (s *Stat) GetSummary(ctx context.Context, filter dto.StatFilter) (dto.StatSummary, error) {
+	summary, err := s.stat.GetSummary(ctx, *filter.ToModel())
+	if err != nil {
+		zap.S().Error(err)
+		return dto.StatSummary{}, fiber.ErrInternalServerError
+	}
+
+	return dto.StatSummaryDTO(summary), nil
+}
+
+func (s *Stat) GetTopTracks(ctx context.Context, filter dto.StatFilter) ([]dto.StatTrack, error) {
+	tracks, err := s.stat.GetTopTracks(ctx, *filter.ToModel())
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return utils.SliceMap(tracks, dto.StatTrackDTO), nil
+}
+
+func (s *Stat) GetTopArtists(ctx context.Context, filter dto.StatFilter) ([]dto.StatArtist, error) {
+	artists, err := s.stat.GetTopArtists(ctx, *filter.ToModel())
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return utils.SliceMap(artists, dto.StatArtistDTO), nil
+}
+
+func (s *Stat) GetTopAlbums(ctx context.Context, filter dto.StatFilter) ([]dto.StatAlbum, error) {
+	albums, err := s.stat.GetTopAlbums(ctx, *filter.ToModel())
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return utils.SliceMap(albums, dto.StatAlbumDTO), nil
+}
+
+func (s *Stat) GetTopPlaylists(ctx context.Context, filter dto.StatFilter) ([]dto.StatPlaylist, error) {
+	playlists, err := s.stat.GetTopPlaylists(ctx, *filter.ToModel())
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return utils.SliceMap(playlists, dto.StatPlaylistDTO), nil
+}
+
+// GetHeatmap returns the plays per hour and weekday in the user's timezone
+func (s *Stat) GetHeatmap(ctx context.Context, filter dto.StatRollupFilter) ([]dto.StatHeatmap, error) {
+	cells, err := s.stat.GetHeatmap(ctx, *filter.ToModel())
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return utils.SliceMap(cells, dto.StatHeatmapDTO), nil
+}
+
+// GetDaily returns the plays per day in the user's timezone
+func (s *Stat) GetDaily(ctx context.Context, filter dto.StatRollupFilter) ([]dto.StatDaily, error) {
+	days, err := s.stat.GetDaily(ctx, *filter.ToModel())
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return utils.SliceMap(days, dto.StatDailyDTO), nil
+}
@@ -14,15 +14,17 @@ import (
 type Track struct {
 	service Service
 
-	history repository.History
-	track   repository.Track
+	history        repository.History
+	historySession repository.HistorySession
+	track          repository.Track
 }
 
 func (s *Service) NewTrack() *Track {
 	return &Track{
-		service: *s,
-		history: *s.repo.NewHistory(),
-		track:   *s.repo.NewTrack(),
+		service:        *s,
+		history:        *s.repo.NewHistory(),
+		historySession: *s.repo.NewHistorySession(),
+		track:          *s.repo.NewTrack(),
 	}
 }
 
@@ -52,7 +54,28 @@ func (t *Track) GetHistory(ctx context.Context, filter dto.HistoryFilter) ([]dto
 	return utils.SliceMap(history, func(h *model.History) dto.History { return dto.HistoryDTO(&h.Track, h) }), nil
 }
 
+// GetSessions returns the listening sessions of a user
+func (t *Track) GetSessions(ctx context.Context, filter dto.HistorySessionFilter) ([]dto.HistorySession, error) {
+	sessions, err := t.historySession.GetFilteredPaginated(ctx, *filter.ToModel())
+	if err != nil {
+		zap.S().Error(err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return utils.SliceMap(sessions, dto.HistorySessionDTO), nil
+}
+
 func (t *Track) GetAdded(ctx context.Context, filter dto.TrackFilter) ([]dto.TrackAdded, error) {
+	if filter.Liked {
+		tracks, err := t.track.GetSavedCreatedFiltered(ctx, *filter.ToModel())
+		if err != nil {
+			zap.S().Error(err)
+			return nil, fiber.ErrInternalServerError
+		}
+
+		return utils.SliceMap(tracks, dto.TrackAddedLikedDTO), nil
+	}
+
 	tracks, err := t.track.GetCreatedFiltered(ctx, *filter.ToModel())
 	if err != nil {
 		zap.S().Error(err)
@@ -63,6 +86,16 @@ func (t *Track) GetAdded(ctx context.Context, filter dto.TrackFilter) ([]dto.Tra
 }
 
 func (t *Track) GetDeleted(ctx context.Context, filter dto.TrackFilter) ([]dto.TrackDeleted, error) {
+	if filter.Liked {
+		tracks, err := t.track.GetSavedDeletedFiltered(ctx, *filter.ToModel())
+		if err != nil {
+			zap.S().Error(err)
+			return nil, fiber.ErrInternalServerError
+		}
+
+		return utils.SliceMap(tracks, dto.TrackDeletedLikedDTO), nil
+	}
+
 	tracks, err := t.track.GetDeletedFiltered(ctx, *filter.ToModel())
 	if err != nil {
 		zap.S().Error(err)
@@ -5,20 +5,35 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/topvennie/sortifyr/internal/database/repository"
+	"github.com/topvennie/sortifyr/internal/generator"
 	"github.com/topvennie/sortifyr/internal/server/dto"
+	"github.com/topvennie/sortifyr/internal/spotifyapi"
+	"github.com/topvennie/sortifyr/internal/spotifysync"
+	"github.com/topvennie/sortifyr/internal/task"
+	"github.com/topvennie/sortifyr/pkg/storage"
 	"go.uber.org/zap"
 )
 
 type User struct {
 	service Service
 
-	user repository.User
+	directory repository.Directory
+	generator repository.Generator
+	link      repository.Link
+	playlist  repository.Playlist
+	recap     repository.Recap
+	user      repository.User
 }
 
 func (s *Service) NewUser() *User {
 	return &User{
-		service: *s,
-		user:    *s.repo.NewUser(),
+		service:   *s,
+		directory: *s.repo.NewDirectory(),
+		generator: *s.repo.NewGenerator(),
+		link:      *s.repo.NewLink(),
+		playlist:  *s.repo.NewPlaylist(),
+		recap:     *s.repo.NewRecap(),
+		user:      *s.repo.NewUser(),
 	}
 }
 
@@ -69,3 +84,107 @@ func (u *User) Update(ctx context.Context, userSave dto.User) (dto.User, error)
 
 	return dto.UserDTO(user), nil
 }
+
+// UpdateTimezone changes the timezone used for time of day statistics
+func (u *User) UpdateTimezone(ctx context.Context, userID int, timezone string) error {
+	user, err := u.user.GetByID(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+	if user == nil {
+		return fiber.ErrNotFound
+	}
+
+	user.Timezone = timezone
+
+	if err := u.user.UpdateTimezone(ctx, *user); err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	// Recaps are calculated in the user's timezone
+	if err := u.recap.DeleteByUser(ctx, user.ID); err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	// The rollup buckets are in the user's timezone
+	// Changing it makes the next refresh calculate everything again
+	if err := task.Manager.RunRecurringByUID(spotifysync.TaskRollupUID, *user); err != nil {
+		zap.S().Error(err)
+	}
+
+	return nil
+}
+
+// Delete removes a user together with all of their data
+// Playlists owned by the user are only deleted if nobody else uses them
+func (u *User) Delete(ctx context.Context, userID int) error {
+	user, err := u.user.GetByID(ctx, userID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+	if user == nil {
+		return fiber.ErrNotFound
+	}
+
+	// Fetched before the generators cascade with the user
+	gens, err := u.generator.GetByUserPopulated(ctx, user.ID)
+	if err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	var coverIDs []string
+
+	if err := u.service.withRollback(ctx, func(ctx context.Context) error {
+		// Directories don't cascade on the user
+		if err := u.directory.DeleteByUser(ctx, user.ID); err != nil {
+			return err
+		}
+		if err := u.link.DeleteBySourcePlaylistUser(ctx, user.ID); err != nil {
+			return err
+		}
+
+		playlistIDs, err := u.playlist.UpdateOwnerClear(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := u.user.Delete(ctx, user.ID); err != nil {
+			return err
+		}
+
+		coverIDs, err = u.playlist.DeleteUnused(ctx, playlistIDs)
+		return err
+	}); err != nil {
+		zap.S().Error(err)
+		return fiber.ErrInternalServerError
+	}
+
+	// The user is gone, from here on errors only leave unused data behind
+
+	if err := generator.G.RemoveTasks(ctx, gens); err != nil {
+		zap.S().Error(err)
+	}
+
+	if err := spotifyapi.C.DeleteUser(ctx, *user); err != nil {
+		zap.S().Error(err)
+	}
+
+	for _, coverID := range coverIDs {
+		if err := storage.Delete(coverID); err != nil {
+			zap.S().Error(err)
+		}
+	}
+	if err := storage.Remove(dataExportKey(user.ID)); err != nil {
+		zap.S().Error(err)
+	}
+	if err := storage.Delete(importUnmatchedKey(user.ID)); err != nil {
+		zap.S().Error(err)
+	}
+
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 type client struct {
 	clientID     string
 	clientSecret string
+	cacheTTL     time.Duration
 }
 
 var C *client
@@ -30,6 +31,7 @@ func Init() error {
 	C = &client{
 		clientID:     clientID,
 		clientSecret: clientSecret,
+		cacheTTL:     config.GetDefaultDurationS("spotify.cache_s", 7*24*60*60),
 	}
 
 	return nil
@@ -46,3 +48,23 @@ func (c *client) NewUser(ctx context.Context, user model.User, accessToken, refr
 
 	return nil
 }
+
+// DeleteUser removes the tokens and cached responses of a user
+// Spotify has no endpoint to revoke a token, access stays granted until the user removes the app in their account settings
+func (c *client) DeleteUser(ctx context.Context, user model.User) error {
+	keys := []string{accessKey(user), refreshKey(user)}
+
+	iter := redis.C.Scan(ctx, 0, cacheKey(user, "*"), 0).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("scan cache keys %w", err)
+	}
+
+	if _, err := redis.C.Del(ctx, keys...).Result(); err != nil {
+		return fmt.Errorf("delete redis keys %w", err)
+	}
+
+	return nil
+}
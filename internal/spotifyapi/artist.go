@@ -61,3 +61,61 @@ func (c *client) ArtistGetAll(ctx context.Context, user model.User, artistIDs []
 
 	return artists, nil
 }
+
+type artistUserResponse struct {
+	Artists struct {
+		Items   []Artist `json:"items"`
+		Next    string   `json:"next"`
+		Cursors struct {
+			After string `json:"after"`
+		} `json:"cursors"`
+	} `json:"artists"`
+}
+
+// ArtistGetUser returns the artists the user follows
+func (c *client) ArtistGetUser(ctx context.Context, user model.User) ([]Artist, error) {
+	artists := make([]Artist, 0)
+
+	url := "me/following?type=artist&limit=50"
+
+	for {
+		var resp artistUserResponse
+
+		if err := c.request(ctx, user, http.MethodGet, url, http.NoBody, &resp); err != nil {
+			return nil, fmt.Errorf("get followed artists %s | %w", url, err)
+		}
+
+		artists = append(artists, resp.Artists.Items...)
+
+		if resp.Artists.Next == "" || resp.Artists.Cursors.After == "" {
+			break
+		}
+
+		url = "me/following?type=artist&limit=50&after=" + resp.Artists.Cursors.After
+	}
+
+	return artists, nil
+}
+
+type artistAlbumResponse struct {
+	Items []Album `json:"items"`
+}
+
+// ArtistGetAlbumRecent returns the most recent albums and singles of an artist
+// It only looks at the first page of each group
+func (c *client) ArtistGetAlbumRecent(ctx context.Context, user model.User, spotifyID string) ([]Album, error) {
+	albums := make([]Album, 0)
+
+	for _, group := range []string{"album", "single"} {
+		var resp artistAlbumResponse
+
+		url := fmt.Sprintf("artists/%s/albums?include_groups=%s&limit=50", spotifyID, group)
+		if err := c.request(ctx, user, http.MethodGet, url, http.NoBody, &resp); err != nil {
+			return nil, fmt.Errorf("get artist albums %s | %w", spotifyID, err)
+		}
+
+		albums = append(albums, resp.Items...)
+	}
+
+	return albums, nil
+}
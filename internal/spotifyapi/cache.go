@@ -0,0 +1,50 @@
+package spotifyapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/topvennie/sortifyr/internal/database/model"
+	"github.com/topvennie/sortifyr/pkg/redis"
+)
+
+// The spotify api returns an etag for most GET requests.
+// By sending it back with the If-None-Match header spotify
+// responds with a 304 if nothing changed, in which case we reuse the previous body.
+
+type cacheEntry struct {
+	ETag string `json:"etag"`
+	Body []byte `json:"body"`
+}
+
+func (c *client) cacheGet(ctx context.Context, user model.User, url string) (*cacheEntry, error) {
+	data, err := redis.C.Get(ctx, cacheKey(user, url)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.ErrNil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get redis key %s | %w", cacheKey(user, url), err)
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("unmarshal cache entry %s | %w", cacheKey(user, url), err)
+	}
+
+	return &entry, nil
+}
+
+func (c *client) cacheSet(ctx context.Context, user model.User, url string, entry cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal cache entry %s | %w", cacheKey(user, url), err)
+	}
+
+	if _, err := redis.C.Set(ctx, cacheKey(user, url), data, c.cacheTTL).Result(); err != nil {
+		return fmt.Errorf("set redis key %s | %w", cacheKey(user, url), err)
+	}
+
+	return nil
+}
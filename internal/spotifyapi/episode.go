@@ -0,0 +1,37 @@
+package spotifyapi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/topvennie/sortifyr/internal/database/model"
+)
+
+type episodeAllResponse struct {
+	Episodes []Episode `json:"episodes"`
+}
+
+func (c *client) EpisodeGetAll(ctx context.Context, user model.User, episodeIDs []string) ([]Episode, error) {
+	episodes := make([]Episode, 0, len(episodeIDs))
+
+	limit := 50
+
+	for i := 0; i < len(episodeIDs); i += limit {
+		var resp episodeAllResponse
+
+		url := "episodes?ids=" + strings.Join(episodeIDs[i:min(len(episodeIDs), i+limit)], ",")
+		if err := c.request(ctx, user, http.MethodGet, url, http.NoBody, &resp); err != nil {
+			return nil, err
+		}
+
+		// Unavailable episodes are returned as null
+		for _, e := range resp.Episodes {
+			if e.SpotifyID != "" {
+				episodes = append(episodes, e)
+			}
+		}
+	}
+
+	return episodes, nil
+}
@@ -8,12 +8,34 @@ import (
 )
 
 type Album struct {
-	SpotifyID   string   `json:"id"`
-	Name        string   `json:"name"`
-	TrackAmount int      `json:"total_tracks"`
-	Popularity  int      `json:"popularity"`
-	Images      []Image  `json:"images"`
-	Artists     []Artist `json:"artists"`
+	SpotifyID            string   `json:"id"`
+	Name                 string   `json:"name"`
+	TrackAmount          int      `json:"total_tracks"`
+	Popularity           int      `json:"popularity"`
+	Images               []Image  `json:"images"`
+	Artists              []Artist `json:"artists"`
+	AlbumType            string   `json:"album_type"`
+	ReleaseDate          string   `json:"release_date"`
+	ReleaseDatePrecision string   `json:"release_date_precision"`
+}
+
+// ReleaseTime parses the release date according to its precision.
+// Returns a zero time if it can't be parsed
+func (a Album) ReleaseTime() time.Time {
+	layout := "2006-01-02"
+	switch a.ReleaseDatePrecision {
+	case "year":
+		layout = "2006"
+	case "month":
+		layout = "2006-01"
+	}
+
+	t, err := time.Parse(layout, a.ReleaseDate)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
 }
 
 func (a Album) ToModel() model.Album {
@@ -64,6 +86,22 @@ func (a Artist) ToModel() model.Artist {
 	}
 }
 
+type Episode struct {
+	SpotifyID  string `json:"id"`
+	Name       string `json:"name"`
+	DurationMs int    `json:"duration_ms"`
+	Show       Show   `json:"show"`
+}
+
+func (e Episode) ToModel() model.Episode {
+	return model.Episode{
+		SpotifyID:  e.SpotifyID,
+		Name:       e.Name,
+		DurationMs: e.DurationMs,
+		Show:       e.Show.ToModel(),
+	}
+}
+
 type Image struct {
 	URL    string `json:"url"`
 	Height int    `json:"height"`
@@ -82,10 +120,12 @@ type History struct {
 }
 
 type Current struct {
-	Track      Track   `json:"item"`
-	ProgressMs int     `json:"progress_ms"`
-	IsPlaying  bool    `json:"is_playing"`
-	Context    Context `json:"context"`
+	Track      Track
+	Episode    Episode
+	Type       string
+	ProgressMs int
+	IsPlaying  bool
+	Context    Context
 }
 
 func (h History) ToModel(user model.User) model.History {
@@ -169,6 +209,8 @@ type Track struct {
 	Popularity int      `json:"popularity"`
 	Artists    []Artist `json:"artists"`
 	DurationMs int      `json:"duration_ms"`
+	Explicit   bool     `json:"explicit"`
+	Album      Album    `json:"album"`
 	LinkedFrom struct {
 		SpotifyID string `json:"id"`
 	} `json:"linked_from"`
@@ -179,12 +221,26 @@ func (t *Track) ToModel() model.Track {
 	if t.LinkedFrom.SpotifyID != "" {
 		spotifyID = t.LinkedFrom.SpotifyID
 	}
+	explicit := t.Explicit
 
 	return model.Track{
 		SpotifyID:  spotifyID,
 		Name:       t.Name,
 		Popularity: t.Popularity,
 		DurationMs: t.DurationMs,
+		Explicit:   &explicit,
 		Artists:    utils.SliceMap(t.Artists, func(a Artist) model.Artist { return a.ToModel() }),
 	}
 }
+
+type SavedTrack struct {
+	AddedAt time.Time `json:"added_at"`
+	Track   Track     `json:"track"`
+}
+
+func (s *SavedTrack) ToModel() model.Track {
+	track := s.Track.ToModel()
+	track.CreatedAt = s.AddedAt
+
+	return track
+}
@@ -2,38 +2,103 @@ package spotifyapi
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/topvennie/sortifyr/internal/database/model"
 )
 
 type playerHistoryResponse struct {
-	Items []History `json:"items"`
+	Items   []History `json:"items"`
+	Next    string    `json:"next"`
+	Cursors struct {
+		After string `json:"after"`
+	} `json:"cursors"`
 }
 
-func (c *client) PlayerGetHistory(ctx context.Context, user model.User) ([]History, error) {
-	var resp playerHistoryResponse
-	if err := c.request(ctx, user, http.MethodGet, "me/player/recently-played?limit=50", http.NoBody, &resp); err != nil {
-		return nil, err
+// PlayerGetHistory returns the recently played tracks that were played after the given time.
+// A zero time returns the most recent tracks.
+// Spotify only keeps the last 50 played tracks, anything older is lost.
+func (c *client) PlayerGetHistory(ctx context.Context, user model.User, after time.Time) ([]History, error) {
+	histories := make([]History, 0)
+
+	url := "me/player/recently-played?limit=50"
+	if !after.IsZero() {
+		url = fmt.Sprintf("%s&after=%d", url, after.UnixMilli())
+	}
+
+	for {
+		var resp playerHistoryResponse
+		if err := c.request(ctx, user, http.MethodGet, url, http.NoBody, &resp); err != nil {
+			return nil, fmt.Errorf("get recently played after %s | %w", after, err)
+		}
+
+		histories = append(histories, resp.Items...)
+
+		if len(resp.Items) == 0 || resp.Next == "" || resp.Cursors.After == "" {
+			break
+		}
+
+		cursor, err := strconv.ParseInt(resp.Cursors.After, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse recently played cursor %s | %w", resp.Cursors.After, err)
+		}
+		if !time.UnixMilli(cursor).After(after) {
+			break
+		}
+		after = time.UnixMilli(cursor)
+
+		url = fmt.Sprintf("me/player/recently-played?limit=50&after=%d", cursor)
 	}
 
-	return resp.Items, nil
+	return histories, nil
 }
 
 type playerCurrentResponse struct {
-	Current
+	Item       json.RawMessage `json:"item"`
+	Type       string          `json:"currently_playing_type"`
+	ProgressMs int             `json:"progress_ms"`
+	IsPlaying  bool            `json:"is_playing"`
+	Context    Context         `json:"context"`
 }
 
+// PlayerGetCurrent returns what the user is currently listening to.
+// The item is either a track or an episode depending on the type.
 func (c *client) PlayerGetCurrent(ctx context.Context, user model.User) (Current, error) {
 	var resp playerCurrentResponse
-	if err := c.request(ctx, user, http.MethodGet, "me/player/currently-playing", http.NoBody, &resp); err != nil {
+	if err := c.request(ctx, user, http.MethodGet, "me/player/currently-playing?additional_types=track,episode", http.NoBody, &resp); err != nil {
 		if errors.Is(err, io.EOF) {
 			return Current{}, nil
 		}
 		return Current{}, err
 	}
 
-	return resp.Current, nil
+	current := Current{
+		Type:       resp.Type,
+		ProgressMs: resp.ProgressMs,
+		IsPlaying:  resp.IsPlaying,
+		Context:    resp.Context,
+	}
+
+	if len(resp.Item) == 0 || string(resp.Item) == "null" {
+		return current, nil
+	}
+
+	var err error
+	switch resp.Type {
+	case "track":
+		err = json.Unmarshal(resp.Item, &current.Track)
+	case "episode":
+		err = json.Unmarshal(resp.Item, &current.Episode)
+	}
+	if err != nil {
+		return Current{}, fmt.Errorf("decode currently playing %s | %w", resp.Type, err)
+	}
+
+	return current, nil
 }
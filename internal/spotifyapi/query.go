@@ -1,6 +1,7 @@
 package spotifyapi
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
@@ -129,6 +130,21 @@ func (c *client) request(ctx context.Context, user model.User, method, url strin
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 	req.Header.Set("Content-Type", "application/json")
 
+	// Player data is time sensitive (e.g. the progress), never reuse it
+	cacheable := method == http.MethodGet && !strings.HasPrefix(url, "me/player")
+
+	var cached *cacheEntry
+	if cacheable {
+		cached, err = c.cacheGet(ctx, user, url)
+		if err != nil {
+			// Not fatal, do the request without the cache
+			zap.S().Error(err)
+		}
+		if cached != nil {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+	}
+
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("do http request %w", err)
@@ -151,8 +167,24 @@ func (c *client) request(ctx context.Context, user model.User, method, url strin
 		return c.request(ctx, user, method, url, body, target)
 	}
 
+	var data []byte
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		data = cached.Body
+	} else {
+		data, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("read body %w", err)
+		}
+
+		if etag := resp.Header.Get("ETag"); cacheable && resp.StatusCode == http.StatusOK && etag != "" {
+			if err := c.cacheSet(ctx, user, url, cacheEntry{ETag: etag, Body: data}); err != nil {
+				zap.S().Error(err)
+			}
+		}
+	}
+
 	if target != noResp {
-		if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		if err := json.NewDecoder(bytes.NewReader(data)).Decode(target); err != nil {
 			return fmt.Errorf("decode body to json %w", err)
 		}
 	}
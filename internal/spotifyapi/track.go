@@ -61,3 +61,29 @@ func (c *client) TrackGetAll(ctx context.Context, user model.User, trackIDs []st
 
 	return tracks, nil
 }
+
+type trackUserResponse struct {
+	Total int          `json:"total"`
+	Items []SavedTrack `json:"items"`
+}
+
+// TrackGetUser returns the user's saved tracks (liked songs)
+func (c *client) TrackGetUser(ctx context.Context, user model.User) ([]SavedTrack, error) {
+	tracks := make([]SavedTrack, 0)
+
+	total := 51
+	limit := 50
+
+	for i := 0; i < total; i += limit {
+		var resp trackUserResponse
+
+		if err := c.request(ctx, user, http.MethodGet, fmt.Sprintf("me/tracks?offset=%d&limit=%d", i, limit), http.NoBody, &resp); err != nil {
+			return nil, fmt.Errorf("get saved tracks with limit %d and offset %d | %w", limit, i, err)
+		}
+
+		tracks = append(tracks, resp.Items...)
+		total = resp.Total
+	}
+
+	return tracks, nil
+}
@@ -9,3 +9,7 @@ func accessKey(user model.User) string {
 func refreshKey(user model.User) string {
 	return user.UID + ":spotify:refresh_token"
 }
+
+func cacheKey(user model.User, url string) string {
+	return user.UID + ":spotify:cache:" + url
+}
@@ -9,6 +9,109 @@ import (
 	"github.com/topvennie/sortifyr/pkg/utils"
 )
 
+// artistSync will syncronize the user's followed artists
+func (c *client) artistSync(ctx context.Context, user model.User) error {
+	artistsDB, err := c.artist.GetByUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	artistsSpotifyAPI, err := spotifyapi.C.ArtistGetUser(ctx, user)
+	if err != nil {
+		return err
+	}
+	artistsSpotify := utils.SliceMap(artistsSpotifyAPI, func(a spotifyapi.Artist) model.Artist { return a.ToModel() })
+
+	return syncUserData(syncUserDataStruct[model.Artist]{
+		DB:     utils.SliceDereference(artistsDB),
+		API:    artistsSpotify,
+		Equal:  func(a1, a2 model.Artist) bool { return a1.Equal(a2) },
+		Get:    func(a model.Artist) (*model.Artist, error) { return c.artist.GetBySpotify(ctx, a.SpotifyID) },
+		Create: func(a *model.Artist) error { return c.artist.Create(ctx, a) },
+		CreateUserLink: func(a model.Artist) error {
+			return c.artist.CreateUser(ctx, &model.ArtistUser{ArtistID: a.ID, UserID: user.ID})
+		},
+		DeleteUserLink: func(a model.Artist) error {
+			return c.artist.DeleteUserByUserArtist(ctx, model.ArtistUser{ArtistID: a.ID, UserID: user.ID})
+		},
+	})
+}
+
+// artistReleaseSync looks for new releases of the user's followed artists.
+// Artists are shared between users so an artist that was recently checked is skipped.
+// Only albums released since the previous check are recorded.
+// The first check doesn't record the back catalogue of an artist.
+func (c *client) artistReleaseSync(ctx context.Context, user model.User) error {
+	artists, err := c.artist.GetByUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	// Use half the interval so that a check from another user
+	// right before this one doesn't delay the next check by a full interval
+	cutOff := time.Now().Add(-c.releaseInterval / 2)
+
+	for _, artist := range artists {
+		if artist.ReleasesCheckedAt.After(cutOff) {
+			continue
+		}
+
+		// Release dates only have a day precision
+		since := artist.ReleasesCheckedAt
+		if since.IsZero() {
+			since = time.Now()
+		}
+		since = since.Truncate(24 * time.Hour)
+
+		releasesDB, err := c.artist.GetReleaseByArtistPopulated(ctx, artist.ID)
+		if err != nil {
+			return err
+		}
+
+		albumsAPI, err := spotifyapi.C.ArtistGetAlbumRecent(ctx, user, artist.SpotifyID)
+		if err != nil {
+			return err
+		}
+
+		for _, albumAPI := range albumsAPI {
+			releaseDate := albumAPI.ReleaseTime()
+			if releaseDate.IsZero() || releaseDate.Before(since) {
+				continue
+			}
+
+			album := albumAPI.ToModel()
+			if _, ok := utils.SliceFind(releasesDB, func(r *model.ArtistRelease) bool { return r.Album.Equal(album) }); ok {
+				// Already known
+				continue
+			}
+
+			// Only create the album once it becomes a release
+			if err := c.historyAlbumCheck(ctx, &album); err != nil {
+				return err
+			}
+
+			release := &model.ArtistRelease{
+				ArtistID:    artist.ID,
+				AlbumID:     album.ID,
+				AlbumType:   albumAPI.AlbumType,
+				ReleaseDate: releaseDate,
+			}
+			if err := c.artist.CreateRelease(ctx, release); err != nil {
+				return err
+			}
+
+			release.Album = album
+			releasesDB = append(releasesDB, release)
+		}
+
+		if err := c.artist.UpdateReleasesCheckedAt(ctx, artist.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // artistUpdate updates local artist instances to match the spotify data.
 // It updates all artists, regardless of the user given.
 // However the given user's access token is used.
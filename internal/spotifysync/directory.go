@@ -0,0 +1,134 @@
+package spotifysync
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/topvennie/sortifyr/internal/database/model"
+	"github.com/topvennie/sortifyr/pkg/utils"
+)
+
+// DirectorySmartSync updates the playlists of every smart directory to match its rules
+func (c *client) DirectorySmartSync(ctx context.Context, user model.User) error {
+	directories, err := c.directory.GetByUserPopulated(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	directories = utils.SliceFilter(directories, func(d *model.Directory) bool { return d.IsSmart() })
+	if len(directories) == 0 {
+		return nil
+	}
+
+	playlists, err := c.playlist.GetByUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	lastPlayed, err := c.history.GetLastPlayedByPlaylist(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, directory := range directories {
+		matches, err := c.directoryMatches(ctx, user, *directory.Rules, playlists, lastPlayed)
+		if err != nil {
+			return fmt.Errorf("evaluate rules of directory %+v | %w", *directory, err)
+		}
+
+		if err := c.directorySmartUpdate(ctx, *directory, matches); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// directoryMatches returns the ids of all playlists matching every rule
+func (c *client) directoryMatches(ctx context.Context, user model.User, rules model.DirectoryRules, playlists []*model.Playlist, lastPlayed map[int]time.Time) (map[int]bool, error) {
+	var nameRegex *regexp.Regexp
+	if rules.NameRegex != "" {
+		var err error
+		nameRegex, err = regexp.Compile(rules.NameRegex)
+		if err != nil {
+			return nil, fmt.Errorf("compile name regex %s | %w", rules.NameRegex, err)
+		}
+	}
+
+	var artistPlaylists map[int]int
+	if rules.ArtistID != 0 {
+		ids, err := c.playlist.GetIDsByArtist(ctx, rules.ArtistID)
+		if err != nil {
+			return nil, err
+		}
+		artistPlaylists = utils.SliceToMap(ids, func(id int) int { return id })
+	}
+
+	notPlayedSince := time.Now().AddDate(0, 0, -rules.NotPlayedDays)
+
+	matches := make(map[int]bool)
+	for _, p := range playlists {
+		if rules.Owned != nil && (p.OwnerID == user.ID) != *rules.Owned {
+			continue
+		}
+		if nameRegex != nil && !nameRegex.MatchString(p.Name) {
+			continue
+		}
+		if rules.Collaborative != nil && (p.Collaborative == nil || *p.Collaborative != *rules.Collaborative) {
+			continue
+		}
+		if rules.Public != nil && (p.Public == nil || *p.Public != *rules.Public) {
+			continue
+		}
+		if rules.TrackAmountMin != 0 && p.TrackAmount < rules.TrackAmountMin {
+			continue
+		}
+		if rules.TrackAmountMax != 0 && p.TrackAmount > rules.TrackAmountMax {
+			continue
+		}
+		if artistPlaylists != nil {
+			if _, ok := artistPlaylists[p.ID]; !ok {
+				continue
+			}
+		}
+		if rules.NotPlayedDays != 0 {
+			if played, ok := lastPlayed[p.ID]; ok && played.After(notPlayedSince) {
+				continue
+			}
+		}
+
+		matches[p.ID] = true
+	}
+
+	return matches, nil
+}
+
+// directorySmartUpdate brings the directory playlists in line with the matching playlists
+func (c *client) directorySmartUpdate(ctx context.Context, directory model.Directory, matches map[int]bool) error {
+	current := make(map[int]bool, len(directory.Playlists))
+	for _, p := range directory.Playlists {
+		current[p.ID] = true
+
+		if matches[p.ID] {
+			continue
+		}
+
+		if err := c.directory.DeletePlaylistByDirectoryPlaylist(ctx, model.DirectoryPlaylist{DirectoryID: directory.ID, PlaylistID: p.ID}); err != nil {
+			return err
+		}
+	}
+
+	for id := range matches {
+		if current[id] {
+			continue
+		}
+
+		if err := c.directory.CreatePlaylist(ctx, &model.DirectoryPlaylist{DirectoryID: directory.ID, PlaylistID: id}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
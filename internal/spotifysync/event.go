@@ -0,0 +1,33 @@
+package spotifysync
+
+import (
+	"context"
+	"errors"
+
+	"github.com/topvennie/sortifyr/internal/database/model"
+)
+
+// Events are emitted by the sync functions and handled synchronously
+// so that any error ends up in the result of the task that emitted it.
+
+// playlistChangedHandler is called with the playlists whose tracks changed
+type playlistChangedHandler func(ctx context.Context, user model.User, playlists []model.Playlist) error
+
+func (c *client) onPlaylistChanged(handler playlistChangedHandler) {
+	c.playlistChangedHandlers = append(c.playlistChangedHandlers, handler)
+}
+
+func (c *client) emitPlaylistChanged(ctx context.Context, user model.User, playlists []model.Playlist) error {
+	if len(playlists) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, handler := range c.playlistChangedHandlers {
+		if err := handler(ctx, user, playlists); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
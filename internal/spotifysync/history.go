@@ -6,6 +6,7 @@ import (
 
 	"github.com/topvennie/sortifyr/internal/database/model"
 	"github.com/topvennie/sortifyr/internal/spotifyapi"
+	"github.com/topvennie/sortifyr/pkg/utils"
 )
 
 func (c *client) historySync(ctx context.Context, user model.User) error {
@@ -17,6 +18,14 @@ func (c *client) historySync(ctx context.Context, user model.User) error {
 		return nil
 	}
 
+	if current.Type == "episode" {
+		return c.historyEpisodeSync(ctx, user, current)
+	}
+	if current.Type != "track" {
+		// Ads or unknown items
+		return nil
+	}
+
 	now := time.Now()
 	currentStart := now.Add(time.Duration(-current.ProgressMs) * time.Millisecond)
 
@@ -66,11 +75,150 @@ func (c *client) historySync(ctx context.Context, user model.User) error {
 		UserID:   user.ID,
 		PlayedAt: currentStart,
 		TrackID:  track.ID,
+		Source:   model.HistorySourcePoll,
+	}
+
+	if err := c.historyContextCheck(ctx, &history, current.Context); err != nil {
+		return err
+	}
+
+	if err := c.history.Create(ctx, &history); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// historyEpisodeSync records the podcast episode the user is listening to.
+// Contrary to tracks, episodes are often paused and resumed later on.
+// As long as the user keeps listening to the same episode the existing entry is kept
+// and only the resume position is updated.
+func (c *client) historyEpisodeSync(ctx context.Context, user model.User, current spotifyapi.Current) error {
+	episode := current.Episode.ToModel()
+	if episode.SpotifyID == "" {
+		return nil
+	}
+
+	if err := c.historyShowCheck(ctx, &episode.Show); err != nil {
+		return err
+	}
+	episode.ShowID = episode.Show.ID
+
+	if err := c.historyEpisodeCheck(ctx, &episode); err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	previous, err := c.episode.GetHistoryLatestPopulated(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	// Consider it the same listen if the user is still on the same episode
+	// and we've seen it recently
+	if previous != nil && previous.EpisodeID == episode.ID && previous.UpdatedAt.Add(10*time.Minute).After(now) {
+		previous.PositionMs = current.ProgressMs
+		return c.episode.UpdateHistory(ctx, *previous)
+	}
+
+	history := model.EpisodeHistory{
+		UserID:     user.ID,
+		EpisodeID:  episode.ID,
+		PlayedAt:   now,
+		PositionMs: current.ProgressMs,
+		Source:     model.HistorySourcePoll,
+	}
+
+	if err := c.episode.CreateHistory(ctx, &history); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// historyRecentlyPlayedSync reconciles the polled history with spotify's recently played endpoint.
+// The polling sync can miss short plays or plays while we were down.
+// Spotify's played at is the time the track ended, so the start is somewhere
+// between the end minus the duration and the end.
+// A play is considered already known if a history entry of the same track starts in that window.
+func (c *client) historyRecentlyPlayedSync(ctx context.Context, user model.User) error {
+	recents, err := spotifyapi.C.PlayerGetHistory(ctx, user, user.RecentlyPlayedAfter)
+	if err != nil {
+		return err
+	}
+	if len(recents) == 0 {
+		return nil
+	}
+
+	buffer := 30 * time.Second
+
+	start := recents[0].PlayedAt
+	end := recents[0].PlayedAt
+	for _, r := range recents {
+		if s := r.PlayedAt.Add(-time.Duration(r.Track.DurationMs) * time.Millisecond); s.Before(start) {
+			start = s
+		}
+		if r.PlayedAt.After(end) {
+			end = r.PlayedAt
+		}
+	}
+
+	historiesDB, err := c.history.GetPopulatedFiltered(ctx, model.HistoryFilter{
+		UserID: user.ID,
+		Start:  start.Add(-buffer),
+		End:    end.Add(buffer),
+	})
+	if err != nil {
+		return err
 	}
 
-	contextSpotifyID := URIToID(current.Context.URI)
+	for _, recent := range recents {
+		recentStart := recent.PlayedAt.Add(-time.Duration(recent.Track.DurationMs) * time.Millisecond)
+		spotifyID := recent.Track.ToModel().SpotifyID
+
+		if _, ok := utils.SliceFind(historiesDB, func(h *model.History) bool {
+			return h.Track.SpotifyID == spotifyID && !h.PlayedAt.Before(recentStart.Add(-buffer)) && !h.PlayedAt.After(recent.PlayedAt.Add(buffer))
+		}); ok {
+			// Already picked up by the polling sync
+			continue
+		}
+
+		track := recent.Track.ToModel()
+		if err := c.historyTrackCheck(ctx, &track); err != nil {
+			return err
+		}
+
+		history := recent.ToModel(user)
+		history.PlayedAt = recentStart
+		history.TrackID = track.ID
+		history.Source = model.HistorySourceRecentlyPlayed
+
+		if err := c.historyContextCheck(ctx, &history, recent.Context); err != nil {
+			return err
+		}
 
-	switch current.Context.Type {
+		if err := c.history.Create(ctx, &history); err != nil {
+			return err
+		}
+
+		history.Track = track
+		historiesDB = append(historiesDB, &history)
+	}
+
+	user.RecentlyPlayedAfter = end
+	if err := c.user.UpdateRecentlyPlayedAfter(ctx, user); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// historyContextCheck links the history entry to the context it was played from
+func (c *client) historyContextCheck(ctx context.Context, history *model.History, playContext spotifyapi.Context) error {
+	contextSpotifyID := URIToID(playContext.URI)
+
+	switch playContext.Type {
 	case "album":
 		album := model.Album{SpotifyID: contextSpotifyID}
 		if err := c.historyAlbumCheck(ctx, &album); err != nil {
@@ -97,10 +245,6 @@ func (c *client) historySync(ctx context.Context, user model.User) error {
 		history.ShowID = show.ID
 	}
 
-	if err := c.history.Create(ctx, &history); err != nil {
-		return err
-	}
-
 	return nil
 }
 
@@ -121,6 +265,23 @@ func (c *client) historyTrackCheck(ctx context.Context, track *model.Track) erro
 	return nil
 }
 
+func (c *client) historyEpisodeCheck(ctx context.Context, episode *model.Episode) error {
+	episodeDB, err := c.episode.GetBySpotify(ctx, episode.SpotifyID)
+	if err != nil {
+		return err
+	}
+
+	if episodeDB == nil {
+		if err := c.episode.Create(ctx, episode); err != nil {
+			return err
+		}
+	} else {
+		episode.ID = episodeDB.ID
+	}
+
+	return nil
+}
+
 func (c *client) historyArtistCheck(ctx context.Context, artist *model.Artist) error {
 	artistDB, err := c.artist.GetBySpotify(ctx, artist.SpotifyID)
 	if err != nil {
@@ -226,3 +387,80 @@ func (c *client) historySkipped(ctx context.Context, user model.User) error {
 
 	return nil
 }
+
+// historySessions groups the plays without a session in listening sessions
+// Sessions that could be extended by those plays are calculated again
+func (c *client) historySessions(ctx context.Context, user model.User) error {
+	first, err := c.history.GetSessionUnknownFirst(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if first.IsZero() {
+		return nil
+	}
+
+	after := first.Add(-c.sessionGap)
+
+	histories, err := c.history.GetSessionRebuildPopulated(ctx, user.ID, after)
+	if err != nil {
+		return err
+	}
+
+	sessions := make([]model.HistorySession, 0)
+	var current []*model.History
+	var currentEnd time.Time
+
+	for _, h := range histories {
+		if len(current) > 0 && h.PlayedAt.Sub(currentEnd) > c.sessionGap {
+			sessions = append(sessions, historySession(user, current, currentEnd))
+			current = nil
+		}
+
+		current = append(current, h)
+		currentEnd = h.PlayedAt.Add(time.Duration(h.Track.DurationMs) * time.Millisecond)
+	}
+	if len(current) > 0 {
+		sessions = append(sessions, historySession(user, current, currentEnd))
+	}
+
+	return c.historySession.ReplaceAfter(ctx, user.ID, after, sessions)
+}
+
+func historySession(user model.User, histories []*model.History, end time.Time) model.HistorySession {
+	session := model.HistorySession{
+		UserID:     user.ID,
+		StartAt:    histories[0].PlayedAt,
+		EndAt:      end,
+		HistoryIDs: utils.SliceMap(histories, func(h *model.History) int { return h.ID }),
+	}
+
+	// The most played context wins, the first one on a tie
+	type playContext struct {
+		playlistID int
+		albumID    int
+		artistID   int
+		showID     int
+	}
+
+	counts := make(map[playContext]int)
+	var dominant playContext
+
+	for _, h := range histories {
+		c := playContext{playlistID: h.PlaylistID, albumID: h.AlbumID, artistID: h.ArtistID, showID: h.ShowID}
+		if c == (playContext{}) {
+			continue
+		}
+
+		counts[c]++
+		if counts[c] > counts[dominant] {
+			dominant = c
+		}
+	}
+
+	session.PlaylistID = dominant.playlistID
+	session.AlbumID = dominant.albumID
+	session.ArtistID = dominant.artistID
+	session.ShowID = dominant.showID
+
+	return session
+}
@@ -2,109 +2,624 @@ package spotifysync
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/topvennie/sortifyr/internal/database/model"
 	"github.com/topvennie/sortifyr/internal/spotifyapi"
 	"github.com/topvennie/sortifyr/pkg/utils"
 )
 
-func (c *client) linksSync(ctx context.Context, user model.User) error {
+// linkData contains everything needed to resolve the links of a user
+type linkData struct {
+	directories []*model.Directory
+	playlists   []*model.Playlist
+	links       []*model.Link
+}
+
+func (c *client) linkDataGet(ctx context.Context, user model.User) (linkData, error) {
 	directories, err := c.directory.GetByUserPopulated(ctx, user.ID)
 	if err != nil {
-		return err
+		return linkData{}, err
 	}
 
 	playlists, err := c.playlist.GetByUserPopulated(ctx, user.ID)
 	if err != nil {
-		return err
+		return linkData{}, err
 	}
 
 	links, err := c.link.GetAllByUser(ctx, user.ID)
+	if err != nil {
+		return linkData{}, err
+	}
+
+	return linkData{
+		directories: directories,
+		playlists:   playlists,
+		links:       links,
+	}, nil
+}
+
+// LinkPreview returns the changes the link would make if it were synced now
+func (c *client) LinkPreview(ctx context.Context, user model.User, linkID int) ([]model.LinkChange, error) {
+	data, err := c.linkDataGet(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	link, ok := utils.SliceFind(data.links, func(l *model.Link) bool { return l.ID == linkID })
+	if !ok {
+		return nil, fmt.Errorf("link %d not found for user %d", linkID, user.ID)
+	}
+
+	return c.linkChanges(ctx, user, **link, data)
+}
+
+func (c *client) linksSync(ctx context.Context, user model.User) error {
+	data, err := c.linkDataGet(ctx, user)
 	if err != nil {
 		return err
 	}
 
-	for _, link := range links {
-		var sources []model.Playlist
-		var targets []model.Playlist
+	// Multiple links can have the same target.
+	// The playlists in the database are only updated by the next playlist sync
+	// so keep track of what is already done to avoid adding the same track twice.
+	applied := make(map[string]bool)
 
-		switch {
-		case link.SourceDirectoryID != 0:
-			directory, ok := utils.SliceFind(directories, func(d *model.Directory) bool { return d.ID == link.SourceDirectoryID })
-			if !ok {
-				return fmt.Errorf("database foreign key reference error (source directory) for link %+v", *link)
-			}
-			sources = (*directory).Playlists
+	var errs []error
 
-		case link.SourcePlaylistID != 0:
-			playlist, ok := utils.SliceFind(playlists, func(p *model.Playlist) bool { return p.ID == link.SourcePlaylistID })
-			if !ok {
-				return fmt.Errorf("database foreign key reference error (source playlist) for link %+v", *link)
-			}
-			sources = []model.Playlist{**playlist}
+	for _, link := range data.links {
+		start := time.Now()
+		changes, err := c.linkChanges(ctx, user, *link, data)
+		if err := c.linkSync(ctx, user, link, start, changes, err, applied); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// linkPlaylistChanged only syncs the link pairs affected by the changed playlists
+func (c *client) linkPlaylistChanged(ctx context.Context, user model.User, changed []model.Playlist) error {
+	data, err := c.linkDataGet(ctx, user)
+	if err != nil {
+		return err
+	}
 
-		default:
-			return fmt.Errorf("database foreign key reference error (source) for link %+v", *link)
+	applied := make(map[string]bool)
+
+	var errs []error
+
+	for _, link := range data.links {
+		start := time.Now()
+		changes, err := c.linkChangesAffected(ctx, user, *link, data, changed)
+		if err == nil && len(changes) == 0 {
+			// Not affected or nothing to do
+			continue
 		}
 
-		switch {
-		case link.TargetDirectoryID != 0:
-			directory, ok := utils.SliceFind(directories, func(d *model.Directory) bool { return d.ID == link.TargetDirectoryID })
-			if !ok {
-				return fmt.Errorf("database foreign key reference error (target directory) for link %+v", *link)
-			}
-			targets = (*directory).Playlists
+		if err := c.linkSync(ctx, user, link, start, changes, err, applied); err != nil {
+			errs = append(errs, err)
+		}
+	}
 
-		case link.TargetPlaylistID != 0:
-			playlist, ok := utils.SliceFind(playlists, func(p *model.Playlist) bool { return p.ID == link.TargetPlaylistID })
-			if !ok {
-				return fmt.Errorf("database foreign key reference error (target playlist) for link %+v", *link)
-			}
-			targets = []model.Playlist{**playlist}
+	return errors.Join(errs...)
+}
+
+// linkSync applies the changes of a single link and records the run
+// start is the time right before the changes were computed
+// changesErr is the error that occurred while computing the changes
+func (c *client) linkSync(ctx context.Context, user model.User, link *model.Link, start time.Time, changes []model.LinkChange, changesErr error, applied map[string]bool) error {
+	added, deleted, err := 0, 0, changesErr
+	if err == nil {
+		added, deleted, err = c.linkApply(ctx, user, changes, applied)
+	}
+
+	link.LastRunAt = time.Now()
+	link.LastRunAdded = added
+	link.LastRunDeleted = deleted
+	link.LastRunError = ""
+	if err != nil {
+		link.LastRunError = err.Error()
+	} else {
+		link.SyncedAt = start
+	}
+
+	if errRun := c.link.UpdateRun(ctx, *link); errRun != nil {
+		return errors.Join(err, errRun)
+	}
+
+	if err != nil {
+		return fmt.Errorf("link %d | %w", link.ID, err)
+	}
+
+	return nil
+}
+
+// linkApply applies the changes to the spotify playlists
+// It returns the amount of added and deleted tracks
+func (c *client) linkApply(ctx context.Context, user model.User, changes []model.LinkChange, applied map[string]bool) (int, int, error) {
+	added := 0
+	deleted := 0
+
+	for _, change := range changes {
+		change.TracksDeleted = utils.SliceFilter(change.TracksDeleted, func(t model.Track) bool {
+			return !applied[fmt.Sprintf("%d:deleted:%s", change.Playlist.ID, t.SpotifyID)]
+		})
+		change.TracksAdded = utils.SliceFilter(change.TracksAdded, func(t model.Track) bool {
+			return !applied[fmt.Sprintf("%d:added:%s", change.Playlist.ID, t.SpotifyID)]
+		})
+
+		if err := spotifyapi.C.PlaylistDeleteTrackAll(ctx, user, change.Playlist.SpotifyID, change.Playlist.SnapshotID, change.TracksDeleted); err != nil {
+			return added, deleted, err
+		}
+		for _, t := range change.TracksDeleted {
+			applied[fmt.Sprintf("%d:deleted:%s", change.Playlist.ID, t.SpotifyID)] = true
+		}
+		deleted += len(change.TracksDeleted)
+
+		if err := spotifyapi.C.PlaylistPostTrackAll(ctx, user, change.Playlist.SpotifyID, change.TracksAdded); err != nil {
+			return added, deleted, err
+		}
+		for _, t := range change.TracksAdded {
+			applied[fmt.Sprintf("%d:added:%s", change.Playlist.ID, t.SpotifyID)] = true
+		}
+		added += len(change.TracksAdded)
+	}
+
+	return added, deleted, nil
+}
+
+// linkChangesAffected returns the changes of the link pairs that contain a changed playlist
+func (c *client) linkChangesAffected(ctx context.Context, user model.User, link model.Link, data linkData, changed []model.Playlist) ([]model.LinkChange, error) {
+	sources, targets, err := model.LinkResolve(link, data.directories, data.playlists)
+	if err != nil {
+		return nil, err
+	}
+
+	isChanged := func(p model.Playlist) bool {
+		return slices.ContainsFunc(changed, func(c model.Playlist) bool { return c.ID == p.ID })
+	}
+
+	changes := make([]model.LinkChange, 0)
+
+	switch link.Mode {
+	case model.LinkMirror:
+		// A mirrored target depends on all its sources and restores tracks removed from itself
+		if !slices.ContainsFunc(sources, isChanged) && !slices.ContainsFunc(targets, isChanged) {
+			return nil, nil
+		}
+
+		changes, err = c.linkMirrorChanges(ctx, user, targets, data)
+		if err != nil {
+			return nil, err
+		}
+
+	case model.LinkBidirectional:
+		for i := range sources {
+			for j := range targets {
+				if !isChanged(sources[i]) && !isChanged(targets[j]) {
+					continue
+				}
 
-		default:
-			return fmt.Errorf("database foreign key reference error (target) for link %+v", *link)
+				pairChanges, err := c.linkBidirectionalChanges(ctx, sources[i], targets[j])
+				if err != nil {
+					return nil, err
+				}
+				changes = append(changes, pairChanges...)
+			}
 		}
 
+	default:
 		for i := range sources {
+			if !isChanged(sources[i]) {
+				continue
+			}
+
 			for j := range targets {
-				if err := c.linkOneSync(ctx, user, sources[i], targets[j]); err != nil {
-					return err
+				change, err := c.linkOneChanges(ctx, user, link, sources[i], targets[j])
+				if err != nil {
+					return nil, err
 				}
+				changes = append(changes, change)
 			}
 		}
 	}
 
-	return nil
+	return linkChangesMerge(changes), nil
+}
+
+// linkChanges returns the changes a link would make, grouped by playlist
+func (c *client) linkChanges(ctx context.Context, user model.User, link model.Link, data linkData) ([]model.LinkChange, error) {
+	sources, targets, err := model.LinkResolve(link, data.directories, data.playlists)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []model.LinkChange
+
+	switch link.Mode {
+	case model.LinkMirror:
+		changes, err = c.linkMirrorChanges(ctx, user, targets, data)
+
+	case model.LinkBidirectional:
+		changes, err = c.linkBidirectionalAllChanges(ctx, sources, targets)
+
+	default:
+		changes, err = c.linkAppendChanges(ctx, user, link, sources, targets)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return linkChangesMerge(changes), nil
+}
+
+// linkChangesMerge combines the changes of the same playlist and drops empty ones
+func linkChangesMerge(changes []model.LinkChange) []model.LinkChange {
+	merged := make([]model.LinkChange, 0, len(changes))
+
+	for _, change := range changes {
+		idx := slices.IndexFunc(merged, func(m model.LinkChange) bool { return m.Playlist.ID == change.Playlist.ID })
+		if idx == -1 {
+			merged = append(merged, model.LinkChange{Playlist: change.Playlist})
+			idx = len(merged) - 1
+		}
+
+		merged[idx].TracksAdded = append(merged[idx].TracksAdded, change.TracksAdded...)
+		merged[idx].TracksDeleted = append(merged[idx].TracksDeleted, change.TracksDeleted...)
+	}
+
+	for i := range merged {
+		merged[i].TracksAdded = utils.SliceUniqueFunc(merged[i].TracksAdded, func(t model.Track) string { return t.SpotifyID })
+		merged[i].TracksDeleted = utils.SliceUniqueFunc(merged[i].TracksDeleted, func(t model.Track) string { return t.SpotifyID })
+	}
+
+	return utils.SliceFilter(merged, func(m model.LinkChange) bool { return len(m.TracksAdded) > 0 || len(m.TracksDeleted) > 0 })
+}
+
+// linkSourceTracks returns every track of the link's sources that passes the link filter
+func (c *client) linkSourceTracks(ctx context.Context, user model.User, link model.Link, sources []model.Playlist) ([]*model.Track, error) {
+	tracks := make([]*model.Track, 0)
+
+	if link.SourceUserID != 0 {
+		tracksSource, err := c.track.GetSavedByUser(ctx, link.SourceUserID)
+		if err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, tracksSource...)
+	}
+
+	for i := range sources {
+		tracksSource, err := c.track.GetByPlaylist(ctx, sources[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, tracksSource...)
+	}
+
+	return c.linkFilter(ctx, user, link.Filter, tracks)
+}
+
+// linkFilter returns the tracks that pass the filter
+// The track's created at should contain the time it got added to the source
+func (c *client) linkFilter(ctx context.Context, user model.User, filter model.LinkFilter, tracks []*model.Track) ([]*model.Track, error) {
+	if filter.IsZero() || len(tracks) == 0 {
+		return tracks, nil
+	}
+
+	trackIDs := utils.SliceMap(tracks, func(t *model.Track) int { return t.ID })
+
+	artists := make(map[int][]int)
+	if len(filter.ArtistIDs) > 0 {
+		trackArtists, err := c.track.GetArtistByTracks(ctx, trackIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range trackArtists {
+			artists[a.TrackID] = append(artists[a.TrackID], a.ArtistID)
+		}
+	}
+
+	plays := make(map[int]int)
+	if filter.MinPlays > 0 {
+		var err error
+		plays, err = c.history.GetPlayCountByUserTracks(ctx, user.ID, trackIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return utils.SliceFilter(tracks, func(t *model.Track) bool {
+		if !filter.AddedAfter.IsZero() && !t.CreatedAt.After(filter.AddedAfter) {
+			return false
+		}
+		if len(filter.ArtistIDs) > 0 && !slices.ContainsFunc(artists[t.ID], func(id int) bool { return slices.Contains(filter.ArtistIDs, id) }) {
+			return false
+		}
+		if filter.ExcludeExplicit && t.Explicit != nil && *t.Explicit {
+			return false
+		}
+		if filter.ExcludeUnplayable && t.SpotifyID == "" {
+			return false
+		}
+		if filter.MinPlays > 0 && plays[t.ID] < filter.MinPlays {
+			return false
+		}
+
+		return true
+	}), nil
+}
+
+// linkAppendChanges adds every source track that is missing in the targets
+func (c *client) linkAppendChanges(ctx context.Context, user model.User, link model.Link, sources, targets []model.Playlist) ([]model.LinkChange, error) {
+	changes := make([]model.LinkChange, 0)
+
+	if link.SourceUserID != 0 {
+		tracksSource, err := c.track.GetSavedByUser(ctx, link.SourceUserID)
+		if err != nil {
+			return nil, err
+		}
+		tracksSource, err = c.linkFilter(ctx, user, link.Filter, tracksSource)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range targets {
+			change, err := c.linkTracksChanges(ctx, tracksSource, targets[i])
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	for i := range sources {
+		for j := range targets {
+			change, err := c.linkOneChanges(ctx, user, link, sources[i], targets[j])
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
 }
 
-func (c *client) linkOneSync(ctx context.Context, user model.User, source, target model.Playlist) error {
+func (c *client) linkOneChanges(ctx context.Context, user model.User, link model.Link, source, target model.Playlist) (model.LinkChange, error) {
 	if source.Equal(target) {
-		return nil
+		return model.LinkChange{Playlist: target}, nil
 	}
 
 	tracksSource, err := c.track.GetByPlaylist(ctx, source.ID)
 	if err != nil {
-		return err
+		return model.LinkChange{}, err
+	}
+
+	tracksSource, err = c.linkFilter(ctx, user, link.Filter, tracksSource)
+	if err != nil {
+		return model.LinkChange{}, err
 	}
 
+	return c.linkTracksChanges(ctx, tracksSource, target)
+}
+
+// linkTracksChanges adds every source track that is missing in the target
+func (c *client) linkTracksChanges(ctx context.Context, tracksSource []*model.Track, target model.Playlist) (model.LinkChange, error) {
 	tracksTarget, err := c.track.GetByPlaylist(ctx, target.ID)
 	if err != nil {
-		return err
+		return model.LinkChange{}, err
 	}
 
-	toAdd := make([]model.Track, 0)
+	change := model.LinkChange{Playlist: target}
 
 	for _, trackSource := range tracksSource {
 		if _, ok := utils.SliceFind(tracksTarget, func(t *model.Track) bool { return t.Equal(*trackSource) }); !ok {
-			toAdd = append(toAdd, *trackSource)
+			change.TracksAdded = append(change.TracksAdded, *trackSource)
 		}
 	}
 
-	if err := spotifyapi.C.PlaylistPostTrackAll(ctx, user, target.SpotifyID, toAdd); err != nil {
-		return err
+	return change, nil
+}
+
+// linkMirrorChanges makes the targets exactly equal the union of their sources.
+// A target can be mirrored by multiple links so the sources of all of them are used.
+// Source tracks missing in the target are added and target tracks in no source are removed.
+// To protect against an incomplete read of the sources only tracks removed from a source
+// since the last synchronization are removed when
+//   - one of the links never synchronized before
+//   - the sources are empty
+func (c *client) linkMirrorChanges(ctx context.Context, user model.User, targets []model.Playlist, data linkData) ([]model.LinkChange, error) {
+	changes := make([]model.LinkChange, 0, len(targets))
+
+	for i := range targets {
+		tracksSource := make([]*model.Track, 0)
+		tracksRemoved := make([]*model.Track, 0)
+		hasSource := false
+		synced := true
+
+		for _, other := range data.links {
+			if other.Mode != model.LinkMirror {
+				continue
+			}
+
+			otherSources, otherTargets, err := model.LinkResolve(*other, data.directories, data.playlists)
+			if err != nil {
+				return nil, err
+			}
+			if !slices.ContainsFunc(otherTargets, func(p model.Playlist) bool { return p.ID == targets[i].ID }) {
+				continue
+			}
+			if len(otherSources) == 0 && other.SourceUserID == 0 {
+				continue
+			}
+			hasSource = true
+			if other.SyncedAt.IsZero() {
+				synced = false
+			}
+
+			tracks, err := c.linkSourceTracks(ctx, user, *other, otherSources)
+			if err != nil {
+				return nil, err
+			}
+			tracksSource = append(tracksSource, tracks...)
+
+			removed, err := c.linkSourceRemoved(ctx, *other, otherSources)
+			if err != nil {
+				return nil, err
+			}
+			tracksRemoved = append(tracksRemoved, removed...)
+		}
+
+		if !hasSource {
+			// Never touch a target without sources, it would remove everything
+			continue
+		}
+
+		change, err := c.linkTracksChanges(ctx, tracksSource, targets[i])
+		if err != nil {
+			return nil, err
+		}
+
+		tracksTarget, err := c.track.GetByPlaylist(ctx, targets[i].ID)
+		if err != nil {
+			return nil, err
+		}
+
+		exact := synced && len(tracksSource) > 0
+
+		for _, trackTarget := range tracksTarget {
+			if _, ok := utils.SliceFind(tracksSource, func(t *model.Track) bool { return t.Equal(*trackTarget) }); ok {
+				continue
+			}
+			if _, ok := utils.SliceFind(tracksRemoved, func(t *model.Track) bool { return t.Equal(*trackTarget) }); ok || exact {
+				change.TracksDeleted = append(change.TracksDeleted, *trackTarget)
+			}
+		}
+
+		changes = append(changes, change)
 	}
 
-	return nil
+	return changes, nil
+}
+
+// linkSourceRemoved returns the tracks removed from the link's sources since its last synchronization
+// Nothing is removed before the link synchronized for the first time.
+func (c *client) linkSourceRemoved(ctx context.Context, link model.Link, sources []model.Playlist) ([]*model.Track, error) {
+	if link.SyncedAt.IsZero() {
+		return nil, nil
+	}
+
+	tracks := make([]*model.Track, 0)
+
+	if link.SourceUserID != 0 {
+		tracksRemoved, err := c.track.GetSavedDeletedByUserAfter(ctx, link.SourceUserID, link.SyncedAt)
+		if err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, tracksRemoved...)
+	}
+
+	for i := range sources {
+		tracksRemoved, err := c.track.GetDeletedByPlaylistAfter(ctx, sources[i].ID, link.SyncedAt)
+		if err != nil {
+			return nil, err
+		}
+		tracks = append(tracks, tracksRemoved...)
+	}
+
+	return tracks, nil
+}
+
+func (c *client) linkBidirectionalAllChanges(ctx context.Context, sources, targets []model.Playlist) ([]model.LinkChange, error) {
+	changes := make([]model.LinkChange, 0)
+
+	for i := range sources {
+		for j := range targets {
+			pairChanges, err := c.linkBidirectionalChanges(ctx, sources[i], targets[j])
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, pairChanges...)
+		}
+	}
+
+	return changes, nil
+}
+
+// linkBidirectionalChanges syncs additions and removals between both playlists.
+// A track that is only in one of them was either added to that one or removed from the other.
+// The playlist track history is used to tell them apart.
+// If the track got removed from the other playlist after it was added to this one,
+// then the removal is propagated, otherwise the addition is.
+func (c *client) linkBidirectionalChanges(ctx context.Context, a, b model.Playlist) ([]model.LinkChange, error) {
+	if a.Equal(b) {
+		return nil, nil
+	}
+
+	tracksA, err := c.track.GetByPlaylist(ctx, a.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracksB, err := c.track.GetByPlaylist(ctx, b.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	playlistTracks, err := c.playlist.GetTrackByPlaylistIDs(ctx, []int{a.ID, b.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	addedAt := func(playlistID, trackID int) time.Time {
+		added := time.Time{}
+		for _, p := range playlistTracks {
+			if p.PlaylistID == playlistID && p.TrackID == trackID && p.DeletedAt.IsZero() && p.CreatedAt.After(added) {
+				added = p.CreatedAt
+			}
+		}
+		return added
+	}
+
+	deletedAt := func(playlistID, trackID int) time.Time {
+		deleted := time.Time{}
+		for _, p := range playlistTracks {
+			if p.PlaylistID == playlistID && p.TrackID == trackID && p.DeletedAt.After(deleted) {
+				deleted = p.DeletedAt
+			}
+		}
+		return deleted
+	}
+
+	// diff returns the tracks to add to the other playlist and the ones to remove from this playlist
+	diff := func(this, other model.Playlist, tracksThis, tracksOther []*model.Track) ([]model.Track, []model.Track) {
+		toAdd := make([]model.Track, 0)
+		toDelete := make([]model.Track, 0)
+
+		for _, track := range tracksThis {
+			if _, ok := utils.SliceFind(tracksOther, func(t *model.Track) bool { return t.Equal(*track) }); ok {
+				continue
+			}
+
+			if deletedAt(other.ID, track.ID).After(addedAt(this.ID, track.ID)) {
+				toDelete = append(toDelete, *track)
+			} else {
+				toAdd = append(toAdd, *track)
+			}
+		}
+
+		return toAdd, toDelete
+	}
+
+	toAddB, toDeleteA := diff(a, b, tracksA, tracksB)
+	toAddA, toDeleteB := diff(b, a, tracksB, tracksA)
+
+	return []model.LinkChange{
+		{Playlist: a, TracksAdded: toAddA, TracksDeleted: toDeleteA},
+		{Playlist: b, TracksAdded: toAddB, TracksDeleted: toDeleteB},
+	}, nil
 }
@@ -83,6 +83,8 @@ func (c *client) playlistUpdate(ctx context.Context, user model.User) error {
 		playlistsSpotify = append(playlistsSpotify, playlistSpotify)
 	}
 
+	changed := make([]model.Playlist, 0)
+
 	for i := range playlistsSpotify {
 		playlistDB, ok := utils.SliceFind(playlistsDB, func(p *model.Playlist) bool { return p.Equal(playlistsSpotify[i]) })
 		if !ok {
@@ -157,9 +159,16 @@ func (c *client) playlistUpdate(ctx context.Context, user model.User) error {
 		}); err != nil {
 			return err
 		}
+
+		changed = append(changed, playlistsSpotify[i])
+	}
+
+	// Update the smart directories first so that the links use the new playlists
+	if err := c.DirectorySmartSync(ctx, user); err != nil {
+		return err
 	}
 
-	return nil
+	return c.emitPlaylistChanged(ctx, user, changed)
 }
 
 func (c *client) playlistCoverSync(ctx context.Context, user model.User) error {
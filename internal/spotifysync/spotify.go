@@ -3,8 +3,10 @@ package spotifysync
 
 import (
 	"context"
+	"time"
 
 	"github.com/topvennie/sortifyr/internal/database/repository"
+	"github.com/topvennie/sortifyr/pkg/config"
 )
 
 // Data updates are typically split in 3 parts
@@ -23,32 +25,47 @@ import (
 // then you get an array of simplified playlist objects
 
 type client struct {
-	album     repository.Album
-	artist    repository.Artist
-	directory repository.Directory
-	history   repository.History
-	link      repository.Link
-	playlist  repository.Playlist
-	show      repository.Show
-	track     repository.Track
-	user      repository.User
+	// Interval between checks for new releases
+	releaseInterval time.Duration
+	// Plays further apart than the gap belong to a different session
+	sessionGap time.Duration
+
+	album          repository.Album
+	artist         repository.Artist
+	directory      repository.Directory
+	episode        repository.Episode
+	history        repository.History
+	historySession repository.HistorySession
+	link           repository.Link
+	playlist       repository.Playlist
+	show           repository.Show
+	track          repository.Track
+	user           repository.User
+
+	playlistChangedHandlers []playlistChangedHandler
 }
 
 var C *client
 
 func Init(repo repository.Repository) error {
 	C = &client{
-		album:     *repo.NewAlbum(),
-		artist:    *repo.NewArtist(),
-		directory: *repo.NewDirectory(),
-		history:   *repo.NewHistory(),
-		link:      *repo.NewLink(),
-		playlist:  *repo.NewPlaylist(),
-		show:      *repo.NewShow(),
-		track:     *repo.NewTrack(),
-		user:      *repo.NewUser(),
+		releaseInterval: config.GetDefaultDurationS("task.release_s", 12*60*60),
+		sessionGap:      config.GetDefaultDurationS("history.session_gap_s", 30*60),
+		album:           *repo.NewAlbum(),
+		artist:          *repo.NewArtist(),
+		directory:       *repo.NewDirectory(),
+		episode:         *repo.NewEpisode(),
+		history:         *repo.NewHistory(),
+		historySession:  *repo.NewHistorySession(),
+		link:            *repo.NewLink(),
+		playlist:        *repo.NewPlaylist(),
+		show:            *repo.NewShow(),
+		track:           *repo.NewTrack(),
+		user:            *repo.NewUser(),
 	}
 
+	C.onPlaylistChanged(C.linkPlaylistChanged)
+
 	if err := C.taskRegister(context.Background()); err != nil {
 		return err
 	}
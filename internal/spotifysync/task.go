@@ -13,9 +13,15 @@ import (
 const (
 	TaskArtistUID   = "task-artist"
 	TaskAlbumUID    = "task-album"
+	TaskFollowUID   = "task-follow"
 	TaskHistoryUID  = "task-history"
+	TaskLikedUID    = "task-liked"
 	TaskLinkUID     = "task-link"
 	TaskPlaylistUID = "task-playlist"
+	TaskRecentUID   = "task-recent"
+	TaskReleaseUID  = "task-release"
+	TaskRollupUID   = "task-rollup"
+	TaskSessionUID  = "task-session"
 	TaskShowUID     = "task-show"
 	TaskTrackUID    = "task-track"
 	TaskUserUID     = "task-user"
@@ -42,6 +48,16 @@ func (c *client) taskRegister(ctx context.Context) error {
 		return err
 	}
 
+	if err := task.Manager.Add(ctx, task.NewTask(
+		TaskLikedUID,
+		"Liked Songs",
+		config.GetDefaultDurationS("task.liked_s", 60*60),
+		false,
+		c.taskWrap(c.taskLiked),
+	)); err != nil {
+		return err
+	}
+
 	if err := task.Manager.Add(ctx, task.NewTask(
 		TaskShowUID,
 		"Show",
@@ -72,6 +88,26 @@ func (c *client) taskRegister(ctx context.Context) error {
 		return err
 	}
 
+	if err := task.Manager.Add(ctx, task.NewTask(
+		TaskFollowUID,
+		"Followed Artists",
+		config.GetDefaultDurationS("task.follow_s", 6*60*60),
+		false,
+		c.taskWrap(c.taskFollow),
+	)); err != nil {
+		return err
+	}
+
+	if err := task.Manager.Add(ctx, task.NewTask(
+		TaskReleaseUID,
+		"New Releases",
+		c.releaseInterval,
+		false,
+		c.taskWrap(c.taskRelease),
+	)); err != nil {
+		return err
+	}
+
 	if err := task.Manager.Add(ctx, task.NewTask(
 		TaskUserUID,
 		"User",
@@ -92,6 +128,36 @@ func (c *client) taskRegister(ctx context.Context) error {
 		return err
 	}
 
+	if err := task.Manager.Add(ctx, task.NewTask(
+		TaskRecentUID,
+		"Recently Played",
+		config.GetDefaultDurationS("task.recent_s", 30*60),
+		false,
+		c.taskWrap(c.taskRecent),
+	)); err != nil {
+		return err
+	}
+
+	if err := task.Manager.Add(ctx, task.NewTask(
+		TaskRollupUID,
+		"History Rollup",
+		config.GetDefaultDurationS("task.rollup_s", 60*60),
+		false,
+		c.taskWrap(c.taskRollup),
+	)); err != nil {
+		return err
+	}
+
+	if err := task.Manager.Add(ctx, task.NewTask(
+		TaskSessionUID,
+		"History Sessions",
+		config.GetDefaultDurationS("task.session_s", 60*60),
+		false,
+		c.taskWrap(c.taskSession),
+	)); err != nil {
+		return err
+	}
+
 	if err := task.Manager.Add(ctx, task.NewTask(
 		TaskLinkUID,
 		"Link",
@@ -167,6 +233,30 @@ func (c *client) taskArtist(ctx context.Context, users []model.User, results []t
 	}
 }
 
+func (c *client) taskLiked(ctx context.Context, users []model.User, results []task.TaskResult) {
+	for i, user := range users {
+		if err := c.trackSavedSync(ctx, user); err != nil {
+			results[i].Error = fmt.Errorf("synchronize liked songs %w", err)
+		}
+	}
+}
+
+func (c *client) taskFollow(ctx context.Context, users []model.User, results []task.TaskResult) {
+	for i, user := range users {
+		if err := c.artistSync(ctx, user); err != nil {
+			results[i].Error = fmt.Errorf("synchronize followed artists %w", err)
+		}
+	}
+}
+
+func (c *client) taskRelease(ctx context.Context, users []model.User, results []task.TaskResult) {
+	for i, user := range users {
+		if err := c.artistReleaseSync(ctx, user); err != nil {
+			results[i].Error = fmt.Errorf("check new releases %w", err)
+		}
+	}
+}
+
 func (c *client) taskShow(ctx context.Context, users []model.User, results []task.TaskResult) {
 	for i, user := range users {
 		if err := c.showSync(ctx, user); err != nil {
@@ -213,6 +303,30 @@ func (c *client) taskHistory(ctx context.Context, users []model.User, results []
 	}
 }
 
+func (c *client) taskRecent(ctx context.Context, users []model.User, results []task.TaskResult) {
+	for i, user := range users {
+		if err := c.historyRecentlyPlayedSync(ctx, user); err != nil {
+			results[i].Error = fmt.Errorf("synchronize recently played %w", err)
+		}
+	}
+}
+
+func (c *client) taskRollup(ctx context.Context, users []model.User, results []task.TaskResult) {
+	for i, user := range users {
+		if err := c.history.RefreshRollup(ctx, user.ID); err != nil {
+			results[i].Error = fmt.Errorf("refresh history rollup %w", err)
+		}
+	}
+}
+
+func (c *client) taskSession(ctx context.Context, users []model.User, results []task.TaskResult) {
+	for i, user := range users {
+		if err := c.historySessions(ctx, user); err != nil {
+			results[i].Error = fmt.Errorf("group history in sessions %w", err)
+		}
+	}
+}
+
 func (c *client) taskLink(ctx context.Context, users []model.User, results []task.TaskResult) {
 	for i, user := range users {
 		if err := c.linksSync(ctx, user); err != nil {
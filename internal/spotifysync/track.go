@@ -9,6 +9,41 @@ import (
 	"github.com/topvennie/sortifyr/pkg/utils"
 )
 
+// trackSavedSync will syncronize the user's saved tracks (liked songs)
+func (c *client) trackSavedSync(ctx context.Context, user model.User) error {
+	tracksDB, err := c.track.GetSavedByUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	tracksSpotifyAPI, err := spotifyapi.C.TrackGetUser(ctx, user)
+	if err != nil {
+		return err
+	}
+	tracksSpotify := utils.SliceMap(tracksSpotifyAPI, func(t spotifyapi.SavedTrack) model.Track { return t.ToModel() })
+
+	// The database item is used to create the user link
+	// so keep track of the saved at time separately
+	savedAts := make(map[string]time.Time, len(tracksSpotify))
+	for _, t := range tracksSpotify {
+		savedAts[t.SpotifyID] = t.CreatedAt
+	}
+
+	return syncUserData(syncUserDataStruct[model.Track]{
+		DB:     utils.SliceDereference(tracksDB),
+		API:    tracksSpotify,
+		Equal:  func(t1, t2 model.Track) bool { return t1.Equal(t2) },
+		Get:    func(t model.Track) (*model.Track, error) { return c.track.GetBySpotify(ctx, t.SpotifyID) },
+		Create: func(t *model.Track) error { return c.track.Create(ctx, t) },
+		CreateUserLink: func(t model.Track) error {
+			return c.track.CreateUser(ctx, &model.UserTrack{UserID: user.ID, TrackID: t.ID, SavedAt: savedAts[t.SpotifyID]})
+		},
+		DeleteUserLink: func(t model.Track) error {
+			return c.track.DeleteUserByUserTrack(ctx, model.UserTrack{UserID: user.ID, TrackID: t.ID})
+		},
+	})
+}
+
 // trackUpdate updates local track instances to match the spotify data.
 // It updates all tracks, regardless of the user given.
 // However the given user's access token is used.
@@ -43,6 +78,12 @@ func (c *client) trackUpdate(ctx context.Context, user model.User) error {
 
 		tracksSpotify[i].ID = (*trackDB).ID
 
+		albumID, err := c.trackAlbum(ctx, tracksSpotifyAPI[i].Album)
+		if err != nil {
+			return err
+		}
+		tracksSpotify[i].AlbumID = albumID
+
 		// Bring track up to date
 		t := tracksSpotify[i]
 		if (*trackDB).EqualEntry(t) {
@@ -79,3 +120,26 @@ func (c *client) trackUpdate(ctx context.Context, user model.User) error {
 
 	return nil
 }
+
+// trackAlbum returns the id of the track's album
+// The album is created if it doesn't exist yet, the album task fills in the rest
+func (c *client) trackAlbum(ctx context.Context, album spotifyapi.Album) (int, error) {
+	if album.SpotifyID == "" {
+		return 0, nil
+	}
+
+	albumDB, err := c.album.GetBySpotify(ctx, album.SpotifyID)
+	if err != nil {
+		return 0, err
+	}
+	if albumDB != nil {
+		return albumDB.ID, nil
+	}
+
+	newAlbum := album.ToModel()
+	if err := c.album.Create(ctx, &newAlbum); err != nil {
+		return 0, err
+	}
+
+	return newAlbum.ID, nil
+}
@@ -23,6 +23,7 @@ type job struct {
 	status   Status
 	interval time.Duration
 	hidden   bool
+	progress Progress
 
 	users []model.User // If it's not empty then an user triggered it and is waiting on it
 }
@@ -223,6 +224,21 @@ func (m *manager) RunRecurringByUID(taskUID string, user model.User) error {
 	return nil
 }
 
+// SetProgress updates the progress of a running task
+// It's ignored if the task doesn't exist
+func (m *manager) SetProgress(taskUID string, done, total int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	info, ok := m.jobs[taskUID]
+	if !ok {
+		return
+	}
+
+	info.progress = Progress{Done: done, Total: total}
+	m.jobs[taskUID] = info
+}
+
 // Tasks returns all scheduled tasks
 func (m *manager) Tasks() ([]Stat, error) {
 	m.mu.Lock()
@@ -258,6 +274,7 @@ func (m *manager) Tasks() ([]Stat, error) {
 				LastRun:   lastRun,
 				Interval:  j.interval,
 				Recurring: j.interval != IntervalOnce,
+				Progress:  j.progress,
 			})
 		}
 	}
@@ -286,6 +303,7 @@ func (m *manager) wrap(task Task) func(context.Context) {
 			info.users = []model.User{}
 		}
 		info.status = Running
+		info.progress = Progress{}
 
 		m.jobs[task.UID()] = info
 		m.mu.Unlock()
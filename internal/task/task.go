@@ -72,6 +72,12 @@ const (
 	Running Status = "running"
 )
 
+// Progress is an optional indication of how far a running task is
+type Progress struct {
+	Done  int
+	Total int
+}
+
 // Stat contains the information about a current running or scheduled task
 type Stat struct {
 	TaskUID   string
@@ -81,6 +87,7 @@ type Stat struct {
 	LastRun   time.Time
 	Interval  time.Duration
 	Recurring bool
+	Progress  Progress
 }
 
 type internalTask struct {
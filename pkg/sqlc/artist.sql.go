@@ -41,7 +41,7 @@ func (q *Queries) ArtistCreate(ctx context.Context, arg ArtistCreateParams) (int
 }
 
 const artistGetAll = `-- name: ArtistGetAll :many
-SELECT id, spotify_id, name, followers, popularity, cover_url, cover_id, updated_at
+SELECT id, spotify_id, name, followers, popularity, cover_url, cover_id, updated_at, releases_checked_at
 FROM artists
 `
 
@@ -63,6 +63,7 @@ func (q *Queries) ArtistGetAll(ctx context.Context) ([]Artist, error) {
 			&i.CoverUrl,
 			&i.CoverID,
 			&i.UpdatedAt,
+			&i.ReleasesCheckedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -75,7 +76,7 @@ func (q *Queries) ArtistGetAll(ctx context.Context) ([]Artist, error) {
 }
 
 const artistGetByAlbum = `-- name: ArtistGetByAlbum :many
-SELECT a.id, a.spotify_id, a.name, a.followers, a.popularity, a.cover_url, a.cover_id, a.updated_at
+SELECT a.id, a.spotify_id, a.name, a.followers, a.popularity, a.cover_url, a.cover_id, a.updated_at, a.releases_checked_at
 FROM artists a
 LEFT JOIN album_artists a_a ON a_a.artist_id = a.id
 WHERE a_a.album_id = $1
@@ -99,6 +100,7 @@ func (q *Queries) ArtistGetByAlbum(ctx context.Context, albumID int32) ([]Artist
 			&i.CoverUrl,
 			&i.CoverID,
 			&i.UpdatedAt,
+			&i.ReleasesCheckedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -111,7 +113,7 @@ func (q *Queries) ArtistGetByAlbum(ctx context.Context, albumID int32) ([]Artist
 }
 
 const artistGetBySpotify = `-- name: ArtistGetBySpotify :one
-SELECT id, spotify_id, name, followers, popularity, cover_url, cover_id, updated_at
+SELECT id, spotify_id, name, followers, popularity, cover_url, cover_id, updated_at, releases_checked_at
 FROM artists
 WHERE spotify_id = $1
 `
@@ -128,12 +130,13 @@ func (q *Queries) ArtistGetBySpotify(ctx context.Context, spotifyID string) (Art
 		&i.CoverUrl,
 		&i.CoverID,
 		&i.UpdatedAt,
+		&i.ReleasesCheckedAt,
 	)
 	return i, err
 }
 
 const artistGetByTrack = `-- name: ArtistGetByTrack :many
-SELECT a.id, a.spotify_id, a.name, a.followers, a.popularity, a.cover_url, a.cover_id, a.updated_at
+SELECT a.id, a.spotify_id, a.name, a.followers, a.popularity, a.cover_url, a.cover_id, a.updated_at, a.releases_checked_at
 FROM artists a
 LEFT JOIN track_artists t_a ON t_a.artist_id = a.id
 WHERE t_a.track_id = $1
@@ -157,6 +160,44 @@ func (q *Queries) ArtistGetByTrack(ctx context.Context, trackID int32) ([]Artist
 			&i.CoverUrl,
 			&i.CoverID,
 			&i.UpdatedAt,
+			&i.ReleasesCheckedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const artistGetByUser = `-- name: ArtistGetByUser :many
+SELECT a.id, a.spotify_id, a.name, a.followers, a.popularity, a.cover_url, a.cover_id, a.updated_at, a.releases_checked_at
+FROM artists a
+LEFT JOIN artist_users au ON au.artist_id = a.id
+WHERE au.user_id = $1 AND au.deleted_at IS NULL
+`
+
+func (q *Queries) ArtistGetByUser(ctx context.Context, userID int32) ([]Artist, error) {
+	rows, err := q.db.Query(ctx, artistGetByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Artist
+	for rows.Next() {
+		var i Artist
+		if err := rows.Scan(
+			&i.ID,
+			&i.SpotifyID,
+			&i.Name,
+			&i.Followers,
+			&i.Popularity,
+			&i.CoverUrl,
+			&i.CoverID,
+			&i.UpdatedAt,
+			&i.ReleasesCheckedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -200,3 +241,14 @@ func (q *Queries) ArtistUpdate(ctx context.Context, arg ArtistUpdateParams) erro
 	)
 	return err
 }
+
+const artistUpdateReleasesCheckedAt = `-- name: ArtistUpdateReleasesCheckedAt :exec
+UPDATE artists
+SET releases_checked_at = NOW()
+WHERE id = $1
+`
+
+func (q *Queries) ArtistUpdateReleasesCheckedAt(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, artistUpdateReleasesCheckedAt, id)
+	return err
+}
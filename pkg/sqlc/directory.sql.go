@@ -12,8 +12,8 @@ import (
 )
 
 const directoryCreate = `-- name: DirectoryCreate :one
-INSERT INTO directories (user_id, name, parent_id)
-VALUES ($1, $2, $3)
+INSERT INTO directories (user_id, name, parent_id, rules)
+VALUES ($1, $2, $3, $4)
 RETURNING id
 `
 
@@ -21,15 +21,31 @@ type DirectoryCreateParams struct {
 	UserID   int32
 	Name     string
 	ParentID pgtype.Int4
+	Rules    []byte
 }
 
 func (q *Queries) DirectoryCreate(ctx context.Context, arg DirectoryCreateParams) (int32, error) {
-	row := q.db.QueryRow(ctx, directoryCreate, arg.UserID, arg.Name, arg.ParentID)
+	row := q.db.QueryRow(ctx, directoryCreate,
+		arg.UserID,
+		arg.Name,
+		arg.ParentID,
+		arg.Rules,
+	)
 	var id int32
 	err := row.Scan(&id)
 	return id, err
 }
 
+const directoryDelete = `-- name: DirectoryDelete :exec
+DELETE FROM directories
+WHERE id = $1
+`
+
+func (q *Queries) DirectoryDelete(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, directoryDelete, id)
+	return err
+}
+
 const directoryDeleteByUser = `-- name: DirectoryDeleteByUser :exec
 DELETE FROM directories
 WHERE user_id = $1
@@ -41,7 +57,7 @@ func (q *Queries) DirectoryDeleteByUser(ctx context.Context, userID int32) error
 }
 
 const directoryGetByUser = `-- name: DirectoryGetByUser :many
-SELECT id, user_id, name, parent_id
+SELECT id, user_id, name, parent_id, rules
 FROM directories d
 WHERE d.user_id = $1
 `
@@ -60,6 +76,7 @@ func (q *Queries) DirectoryGetByUser(ctx context.Context, userID int32) ([]Direc
 			&i.UserID,
 			&i.Name,
 			&i.ParentID,
+			&i.Rules,
 		); err != nil {
 			return nil, err
 		}
@@ -73,7 +90,7 @@ func (q *Queries) DirectoryGetByUser(ctx context.Context, userID int32) ([]Direc
 
 const directoryUpdate = `-- name: DirectoryUpdate :exec
 UPDATE directories
-SET name = $2, parent_id = $3
+SET name = $2, parent_id = $3, rules = $4
 WHERE id = $1
 `
 
@@ -81,9 +98,15 @@ type DirectoryUpdateParams struct {
 	ID       int32
 	Name     string
 	ParentID pgtype.Int4
+	Rules    []byte
 }
 
 func (q *Queries) DirectoryUpdate(ctx context.Context, arg DirectoryUpdateParams) error {
-	_, err := q.db.Exec(ctx, directoryUpdate, arg.ID, arg.Name, arg.ParentID)
+	_, err := q.db.Exec(ctx, directoryUpdate,
+		arg.ID,
+		arg.Name,
+		arg.ParentID,
+		arg.Rules,
+	)
 	return err
 }
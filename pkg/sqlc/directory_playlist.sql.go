@@ -27,6 +27,21 @@ func (q *Queries) DirectoryPlaylistCreate(ctx context.Context, arg DirectoryPlay
 	return id, err
 }
 
+const directoryPlaylistDeleteByDirectoryPlaylist = `-- name: DirectoryPlaylistDeleteByDirectoryPlaylist :exec
+DELETE FROM directory_playlists
+WHERE directory_id = $1 AND playlist_id = $2
+`
+
+type DirectoryPlaylistDeleteByDirectoryPlaylistParams struct {
+	DirectoryID int32
+	PlaylistID  int32
+}
+
+func (q *Queries) DirectoryPlaylistDeleteByDirectoryPlaylist(ctx context.Context, arg DirectoryPlaylistDeleteByDirectoryPlaylistParams) error {
+	_, err := q.db.Exec(ctx, directoryPlaylistDeleteByDirectoryPlaylist, arg.DirectoryID, arg.PlaylistID)
+	return err
+}
+
 const directoryPlaylistGetByDirectory = `-- name: DirectoryPlaylistGetByDirectory :many
 SELECT id, directory_id, playlist_id
 FROM directory_playlists
@@ -77,7 +77,7 @@ func (q *Queries) GeneratorGet(ctx context.Context, id int32) (Generator, error)
 }
 
 const generatorGetAll = `-- name: GeneratorGetAll :many
-SELECT g.id, g.user_id, g.name, g.description, g.playlist_id, g.interval, g.spotify_outdated, g.parameters, g.updated_at, g.created_at, u.id, u.uid, u.name, u.display_name, u.email
+SELECT g.id, g.user_id, g.name, g.description, g.playlist_id, g.interval, g.spotify_outdated, g.parameters, g.updated_at, g.created_at, u.id, u.uid, u.name, u.display_name, u.email, u.recently_played_after, u.timezone, u.rollup_dirty_from
 FROM generators g
 LEFT JOIN users u ON u.id = g.user_id
 `
@@ -112,6 +112,9 @@ func (q *Queries) GeneratorGetAll(ctx context.Context) ([]GeneratorGetAllRow, er
 			&i.User.Name,
 			&i.User.DisplayName,
 			&i.User.Email,
+			&i.User.RecentlyPlayedAfter,
+			&i.User.Timezone,
+			&i.User.RollupDirtyFrom,
 		); err != nil {
 			return nil, err
 		}
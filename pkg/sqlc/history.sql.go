@@ -11,9 +11,46 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const historyCountExisting = `-- name: HistoryCountExisting :one
+SELECT COUNT(*)
+FROM (
+  SELECT
+    UNNEST($2::text[]) AS spotify_id,
+    UNNEST($3::timestamptz[]) AS played_at
+) i
+WHERE EXISTS (
+  SELECT 1
+  FROM history h
+  LEFT JOIN tracks t ON t.id = h.track_id
+  WHERE
+    h.user_id = $1::int AND
+    t.spotify_id = i.spotify_id AND
+    h.played_at BETWEEN i.played_at - $4::int * INTERVAL '1 second' AND i.played_at + $4::int * INTERVAL '1 second'
+)
+`
+
+type HistoryCountExistingParams struct {
+	Column1 int32
+	Column2 []string
+	Column3 []pgtype.Timestamptz
+	Column4 int32
+}
+
+func (q *Queries) HistoryCountExisting(ctx context.Context, arg HistoryCountExistingParams) (int64, error) {
+	row := q.db.QueryRow(ctx, historyCountExisting,
+		arg.Column1,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const historyCreate = `-- name: HistoryCreate :one
-INSERT INTO history (user_id, track_id, played_at, album_id, artist_id, playlist_id, show_id, skipped)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO history (user_id, track_id, played_at, album_id, artist_id, playlist_id, show_id, skipped, source)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 RETURNING id
 `
 
@@ -26,6 +63,7 @@ type HistoryCreateParams struct {
 	PlaylistID pgtype.Int4
 	ShowID     pgtype.Int4
 	Skipped    pgtype.Bool
+	Source     string
 }
 
 func (q *Queries) HistoryCreate(ctx context.Context, arg HistoryCreateParams) (int32, error) {
@@ -38,56 +76,155 @@ func (q *Queries) HistoryCreate(ctx context.Context, arg HistoryCreateParams) (i
 		arg.PlaylistID,
 		arg.ShowID,
 		arg.Skipped,
+		arg.Source,
 	)
 	var id int32
 	err := row.Scan(&id)
 	return id, err
 }
 
-const historyCreateBatch = `-- name: HistoryCreateBatch :exec
-INSERT INTO history (user_id, track_id, played_at, skipped)
-VALUES (
-  UNNEST($1::int[]),
-  UNNEST($2::int[]),
-  UNNEST($3::timestamptz[]),
-  UNNEST($4::boolean[])
-)
+const historyGetLastPlayedByPlaylist = `-- name: HistoryGetLastPlayedByPlaylist :many
+SELECT playlist_id, MAX(played_at)::timestamptz AS played_at
+FROM history
+WHERE user_id = $1 AND playlist_id IS NOT NULL
+GROUP BY playlist_id
 `
 
-type HistoryCreateBatchParams struct {
-	Column1 []int32
+type HistoryGetLastPlayedByPlaylistRow struct {
+	PlaylistID pgtype.Int4
+	PlayedAt   pgtype.Timestamptz
+}
+
+func (q *Queries) HistoryGetLastPlayedByPlaylist(ctx context.Context, userID int32) ([]HistoryGetLastPlayedByPlaylistRow, error) {
+	rows, err := q.db.Query(ctx, historyGetLastPlayedByPlaylist, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HistoryGetLastPlayedByPlaylistRow
+	for rows.Next() {
+		var i HistoryGetLastPlayedByPlaylistRow
+		if err := rows.Scan(&i.PlaylistID, &i.PlayedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const historyGetPlayCountByUserTracks = `-- name: HistoryGetPlayCountByUserTracks :many
+SELECT track_id, COUNT(*) AS play_count
+FROM history
+WHERE user_id = $1 AND track_id = ANY($2::int[])
+GROUP BY track_id
+`
+
+type HistoryGetPlayCountByUserTracksParams struct {
+	UserID  int32
 	Column2 []int32
-	Column3 []pgtype.Timestamptz
-	Column4 []bool
 }
 
-func (q *Queries) HistoryCreateBatch(ctx context.Context, arg HistoryCreateBatchParams) error {
-	_, err := q.db.Exec(ctx, historyCreateBatch,
-		arg.Column1,
-		arg.Column2,
-		arg.Column3,
-		arg.Column4,
-	)
-	return err
+type HistoryGetPlayCountByUserTracksRow struct {
+	TrackID   int32
+	PlayCount int64
 }
 
-const historyDeleteUserOlder = `-- name: HistoryDeleteUserOlder :exec
-DELETE FROM history
-WHERE user_id = $1 AND played_at < $2
+func (q *Queries) HistoryGetPlayCountByUserTracks(ctx context.Context, arg HistoryGetPlayCountByUserTracksParams) ([]HistoryGetPlayCountByUserTracksRow, error) {
+	rows, err := q.db.Query(ctx, historyGetPlayCountByUserTracks, arg.UserID, arg.Column2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HistoryGetPlayCountByUserTracksRow
+	for rows.Next() {
+		var i HistoryGetPlayCountByUserTracksRow
+		if err := rows.Scan(&i.TrackID, &i.PlayCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const historyGetPopulatedAfter = `-- name: HistoryGetPopulatedAfter :many
+SELECT h.id, h.user_id, h.track_id, h.played_at, h.album_id, h.artist_id, h.playlist_id, h.show_id, h.skipped, h.reason_start, h.reason_end, h.shuffle, h.offline, h.platform, h.conn_country, h.source, h.session_id, t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id, COALESCE(array_agg(a.name ORDER BY ta.id) FILTER (WHERE a.name IS NOT NULL), '{}')::text[] AS artists
+FROM history h
+LEFT JOIN tracks t ON t.id = h.track_id
+LEFT JOIN track_artists ta ON ta.track_id = t.id
+LEFT JOIN artists a ON a.id = ta.artist_id
+WHERE h.user_id = $1 AND h.id > $2
+GROUP BY h.id, t.id
+ORDER BY h.id
+LIMIT $3
 `
 
-type HistoryDeleteUserOlderParams struct {
-	UserID   int32
-	PlayedAt pgtype.Timestamptz
+type HistoryGetPopulatedAfterParams struct {
+	UserID int32
+	ID     int32
+	Limit  int32
 }
 
-func (q *Queries) HistoryDeleteUserOlder(ctx context.Context, arg HistoryDeleteUserOlderParams) error {
-	_, err := q.db.Exec(ctx, historyDeleteUserOlder, arg.UserID, arg.PlayedAt)
-	return err
+type HistoryGetPopulatedAfterRow struct {
+	History History
+	Track   Track
+	Artists []string
+}
+
+func (q *Queries) HistoryGetPopulatedAfter(ctx context.Context, arg HistoryGetPopulatedAfterParams) ([]HistoryGetPopulatedAfterRow, error) {
+	rows, err := q.db.Query(ctx, historyGetPopulatedAfter, arg.UserID, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HistoryGetPopulatedAfterRow
+	for rows.Next() {
+		var i HistoryGetPopulatedAfterRow
+		if err := rows.Scan(
+			&i.History.ID,
+			&i.History.UserID,
+			&i.History.TrackID,
+			&i.History.PlayedAt,
+			&i.History.AlbumID,
+			&i.History.ArtistID,
+			&i.History.PlaylistID,
+			&i.History.ShowID,
+			&i.History.Skipped,
+			&i.History.ReasonStart,
+			&i.History.ReasonEnd,
+			&i.History.Shuffle,
+			&i.History.Offline,
+			&i.History.Platform,
+			&i.History.ConnCountry,
+			&i.History.Source,
+			&i.History.SessionID,
+			&i.Track.ID,
+			&i.Track.SpotifyID,
+			&i.Track.Name,
+			&i.Track.Popularity,
+			&i.Track.UpdatedAt,
+			&i.Track.DurationMs,
+			&i.Track.Explicit,
+			&i.Track.AlbumID,
+			&i.Artists,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const historyGetPopulatedFiltered = `-- name: HistoryGetPopulatedFiltered :many
-SELECT h.id, h.user_id, h.track_id, h.played_at, h.album_id, h.artist_id, h.playlist_id, h.show_id, h.skipped, t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms
+SELECT h.id, h.user_id, h.track_id, h.played_at, h.album_id, h.artist_id, h.playlist_id, h.show_id, h.skipped, h.reason_start, h.reason_end, h.shuffle, h.offline, h.platform, h.conn_country, h.source, h.session_id, t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id
 FROM history h
 LEFT JOIN tracks t ON t.id = h.track_id
 WHERE 
@@ -140,12 +277,22 @@ func (q *Queries) HistoryGetPopulatedFiltered(ctx context.Context, arg HistoryGe
 			&i.History.PlaylistID,
 			&i.History.ShowID,
 			&i.History.Skipped,
+			&i.History.ReasonStart,
+			&i.History.ReasonEnd,
+			&i.History.Shuffle,
+			&i.History.Offline,
+			&i.History.Platform,
+			&i.History.ConnCountry,
+			&i.History.Source,
+			&i.History.SessionID,
 			&i.Track.ID,
 			&i.Track.SpotifyID,
 			&i.Track.Name,
 			&i.Track.Popularity,
 			&i.Track.UpdatedAt,
 			&i.Track.DurationMs,
+			&i.Track.Explicit,
+			&i.Track.AlbumID,
 		); err != nil {
 			return nil, err
 		}
@@ -158,14 +305,15 @@ func (q *Queries) HistoryGetPopulatedFiltered(ctx context.Context, arg HistoryGe
 }
 
 const historyGetPopulatedFilteredPaginated = `-- name: HistoryGetPopulatedFilteredPaginated :many
-SELECT h.id, h.user_id, h.track_id, h.played_at, h.album_id, h.artist_id, h.playlist_id, h.show_id, h.skipped, t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, count(*) FILTER (WHERE h.user_id = $1::int AND (h.skipped = $7::boolean OR NOT $8)) OVER  (PARTITION BY h.track_id) AS play_count
+SELECT h.id, h.user_id, h.track_id, h.played_at, h.album_id, h.artist_id, h.playlist_id, h.show_id, h.skipped, h.reason_start, h.reason_end, h.shuffle, h.offline, h.platform, h.conn_country, h.source, h.session_id, t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id, count(*) FILTER (WHERE h.user_id = $1::int AND (h.skipped = $7::boolean OR NOT $9)) OVER  (PARTITION BY h.track_id) AS play_count
 FROM history h
 LEFT JOIN tracks t ON t.id = h.track_id
 WHERE 
   h.user_id = $1::int AND
-  (h.played_at >= $4::timestamptz OR NOT $9) AND 
-  (h.played_at <= $5::timestamptz OR NOT $10) AND
-  (h.skipped = $6::boolean OR NOT $11)
+  (h.played_at >= $4::timestamptz OR NOT $10) AND 
+  (h.played_at <= $5::timestamptz OR NOT $11) AND
+  (h.skipped = $6::boolean OR NOT $12) AND
+  (h.session_id = $8::int OR NOT $13)
 ORDER BY h.played_at DESC
 LIMIT $2 OFFSET $3
 `
@@ -178,10 +326,12 @@ type HistoryGetPopulatedFilteredPaginatedParams struct {
 	Column5         pgtype.Timestamptz
 	Column6         bool
 	Column7         bool
+	Column8         int32
 	FilterPlayCount interface{}
 	FilterStart     interface{}
 	FilterEnd       interface{}
 	FilterSkipped   interface{}
+	FilterSession   interface{}
 }
 
 type HistoryGetPopulatedFilteredPaginatedRow struct {
@@ -199,10 +349,12 @@ func (q *Queries) HistoryGetPopulatedFilteredPaginated(ctx context.Context, arg
 		arg.Column5,
 		arg.Column6,
 		arg.Column7,
+		arg.Column8,
 		arg.FilterPlayCount,
 		arg.FilterStart,
 		arg.FilterEnd,
 		arg.FilterSkipped,
+		arg.FilterSession,
 	)
 	if err != nil {
 		return nil, err
@@ -221,12 +373,22 @@ func (q *Queries) HistoryGetPopulatedFilteredPaginated(ctx context.Context, arg
 			&i.History.PlaylistID,
 			&i.History.ShowID,
 			&i.History.Skipped,
+			&i.History.ReasonStart,
+			&i.History.ReasonEnd,
+			&i.History.Shuffle,
+			&i.History.Offline,
+			&i.History.Platform,
+			&i.History.ConnCountry,
+			&i.History.Source,
+			&i.History.SessionID,
 			&i.Track.ID,
 			&i.Track.SpotifyID,
 			&i.Track.Name,
 			&i.Track.Popularity,
 			&i.Track.UpdatedAt,
 			&i.Track.DurationMs,
+			&i.Track.Explicit,
+			&i.Track.AlbumID,
 			&i.PlayCount,
 		); err != nil {
 			return nil, err
@@ -240,7 +402,7 @@ func (q *Queries) HistoryGetPopulatedFilteredPaginated(ctx context.Context, arg
 }
 
 const historyGetPreviousPopulated = `-- name: HistoryGetPreviousPopulated :one
-SELECT h.id, h.user_id, h.track_id, h.played_at, h.album_id, h.artist_id, h.playlist_id, h.show_id, h.skipped, t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms
+SELECT h.id, h.user_id, h.track_id, h.played_at, h.album_id, h.artist_id, h.playlist_id, h.show_id, h.skipped, h.reason_start, h.reason_end, h.shuffle, h.offline, h.platform, h.conn_country, h.source, h.session_id, t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id
 FROM history h
 LEFT JOIN tracks t ON t.id = h.track_id
 WHERE h.played_at < $1 AND h.user_id = $2
@@ -271,18 +433,108 @@ func (q *Queries) HistoryGetPreviousPopulated(ctx context.Context, arg HistoryGe
 		&i.History.PlaylistID,
 		&i.History.ShowID,
 		&i.History.Skipped,
+		&i.History.ReasonStart,
+		&i.History.ReasonEnd,
+		&i.History.Shuffle,
+		&i.History.Offline,
+		&i.History.Platform,
+		&i.History.ConnCountry,
+		&i.History.Source,
+		&i.History.SessionID,
 		&i.Track.ID,
 		&i.Track.SpotifyID,
 		&i.Track.Name,
 		&i.Track.Popularity,
 		&i.Track.UpdatedAt,
 		&i.Track.DurationMs,
+		&i.Track.Explicit,
+		&i.Track.AlbumID,
 	)
 	return i, err
 }
 
+const historyGetSessionNullFirst = `-- name: HistoryGetSessionNullFirst :one
+SELECT played_at
+FROM history
+WHERE user_id = $1 AND session_id IS NULL
+ORDER BY played_at
+LIMIT 1
+`
+
+func (q *Queries) HistoryGetSessionNullFirst(ctx context.Context, userID int32) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, historyGetSessionNullFirst, userID)
+	var played_at pgtype.Timestamptz
+	err := row.Scan(&played_at)
+	return played_at, err
+}
+
+const historyGetSessionRebuildPopulated = `-- name: HistoryGetSessionRebuildPopulated :many
+SELECT h.id, h.user_id, h.track_id, h.played_at, h.album_id, h.artist_id, h.playlist_id, h.show_id, h.skipped, h.reason_start, h.reason_end, h.shuffle, h.offline, h.platform, h.conn_country, h.source, h.session_id, t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id
+FROM history h
+LEFT JOIN tracks t ON t.id = h.track_id
+LEFT JOIN history_sessions s ON s.id = h.session_id
+WHERE h.user_id = $1 AND (h.session_id IS NULL OR s.end_at >= $2)
+ORDER BY h.played_at
+`
+
+type HistoryGetSessionRebuildPopulatedParams struct {
+	UserID int32
+	EndAt  pgtype.Timestamptz
+}
+
+type HistoryGetSessionRebuildPopulatedRow struct {
+	History History
+	Track   Track
+}
+
+func (q *Queries) HistoryGetSessionRebuildPopulated(ctx context.Context, arg HistoryGetSessionRebuildPopulatedParams) ([]HistoryGetSessionRebuildPopulatedRow, error) {
+	rows, err := q.db.Query(ctx, historyGetSessionRebuildPopulated, arg.UserID, arg.EndAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HistoryGetSessionRebuildPopulatedRow
+	for rows.Next() {
+		var i HistoryGetSessionRebuildPopulatedRow
+		if err := rows.Scan(
+			&i.History.ID,
+			&i.History.UserID,
+			&i.History.TrackID,
+			&i.History.PlayedAt,
+			&i.History.AlbumID,
+			&i.History.ArtistID,
+			&i.History.PlaylistID,
+			&i.History.ShowID,
+			&i.History.Skipped,
+			&i.History.ReasonStart,
+			&i.History.ReasonEnd,
+			&i.History.Shuffle,
+			&i.History.Offline,
+			&i.History.Platform,
+			&i.History.ConnCountry,
+			&i.History.Source,
+			&i.History.SessionID,
+			&i.Track.ID,
+			&i.Track.SpotifyID,
+			&i.Track.Name,
+			&i.Track.Popularity,
+			&i.Track.UpdatedAt,
+			&i.Track.DurationMs,
+			&i.Track.Explicit,
+			&i.Track.AlbumID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const historyGetSkippedNullPopulated = `-- name: HistoryGetSkippedNullPopulated :many
-SELECT h.id, h.user_id, h.track_id, h.played_at, h.album_id, h.artist_id, h.playlist_id, h.show_id, h.skipped, t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms
+SELECT h.id, h.user_id, h.track_id, h.played_at, h.album_id, h.artist_id, h.playlist_id, h.show_id, h.skipped, h.reason_start, h.reason_end, h.shuffle, h.offline, h.platform, h.conn_country, h.source, h.session_id, t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id
 FROM history h
 LEFT JOIN tracks t ON t.id = h.track_id
 WHERE h.skipped IS NULL AND h.user_id = $1
@@ -313,12 +565,22 @@ func (q *Queries) HistoryGetSkippedNullPopulated(ctx context.Context, userID int
 			&i.History.PlaylistID,
 			&i.History.ShowID,
 			&i.History.Skipped,
+			&i.History.ReasonStart,
+			&i.History.ReasonEnd,
+			&i.History.Shuffle,
+			&i.History.Offline,
+			&i.History.Platform,
+			&i.History.ConnCountry,
+			&i.History.Source,
+			&i.History.SessionID,
 			&i.Track.ID,
 			&i.Track.SpotifyID,
 			&i.Track.Name,
 			&i.Track.Popularity,
 			&i.Track.UpdatedAt,
 			&i.Track.DurationMs,
+			&i.Track.Explicit,
+			&i.Track.AlbumID,
 		); err != nil {
 			return nil, err
 		}
@@ -330,6 +592,131 @@ func (q *Queries) HistoryGetSkippedNullPopulated(ctx context.Context, userID int
 	return items, nil
 }
 
+const historyMergeBatchCreate = `-- name: HistoryMergeBatchCreate :execrows
+INSERT INTO history (user_id, track_id, played_at, skipped, reason_start, reason_end, shuffle, offline, platform, conn_country, source)
+SELECT i.user_id, i.track_id, i.played_at, NULLIF(i.skipped, '')::boolean, NULLIF(i.reason_start, ''), NULLIF(i.reason_end, ''), NULLIF(i.shuffle, '')::boolean, NULLIF(i.offline, '')::boolean, NULLIF(i.platform, ''), NULLIF(i.conn_country, ''), $12::text
+FROM (
+  SELECT
+    UNNEST($1::int[]) AS user_id,
+    UNNEST($2::int[]) AS track_id,
+    UNNEST($3::timestamptz[]) AS played_at,
+    UNNEST($4::text[]) AS skipped,
+    UNNEST($5::text[]) AS reason_start,
+    UNNEST($6::text[]) AS reason_end,
+    UNNEST($7::text[]) AS shuffle,
+    UNNEST($8::text[]) AS offline,
+    UNNEST($9::text[]) AS platform,
+    UNNEST($10::text[]) AS conn_country
+) i
+WHERE NOT EXISTS (
+  SELECT 1
+  FROM history h
+  WHERE
+    h.user_id = i.user_id AND
+    h.track_id = i.track_id AND
+    h.played_at BETWEEN i.played_at - $11::int * INTERVAL '1 second' AND i.played_at + $11::int * INTERVAL '1 second'
+)
+`
+
+type HistoryMergeBatchCreateParams struct {
+	Column1  []int32
+	Column2  []int32
+	Column3  []pgtype.Timestamptz
+	Column4  []string
+	Column5  []string
+	Column6  []string
+	Column7  []string
+	Column8  []string
+	Column9  []string
+	Column10 []string
+	Column11 int32
+	Column12 string
+}
+
+func (q *Queries) HistoryMergeBatchCreate(ctx context.Context, arg HistoryMergeBatchCreateParams) (int64, error) {
+	result, err := q.db.Exec(ctx, historyMergeBatchCreate,
+		arg.Column1,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+		arg.Column6,
+		arg.Column7,
+		arg.Column8,
+		arg.Column9,
+		arg.Column10,
+		arg.Column11,
+		arg.Column12,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const historyMergeBatchUpdate = `-- name: HistoryMergeBatchUpdate :execrows
+UPDATE history h
+SET
+  skipped = coalesce(h.skipped, NULLIF(i.skipped, '')::boolean),
+  reason_start = coalesce(h.reason_start, NULLIF(i.reason_start, '')),
+  reason_end = coalesce(h.reason_end, NULLIF(i.reason_end, '')),
+  shuffle = coalesce(h.shuffle, NULLIF(i.shuffle, '')::boolean),
+  offline = coalesce(h.offline, NULLIF(i.offline, '')::boolean),
+  platform = coalesce(h.platform, NULLIF(i.platform, '')),
+  conn_country = coalesce(h.conn_country, NULLIF(i.conn_country, ''))
+FROM (
+  SELECT
+    UNNEST($1::int[]) AS user_id,
+    UNNEST($2::int[]) AS track_id,
+    UNNEST($3::timestamptz[]) AS played_at,
+    UNNEST($4::text[]) AS skipped,
+    UNNEST($5::text[]) AS reason_start,
+    UNNEST($6::text[]) AS reason_end,
+    UNNEST($7::text[]) AS shuffle,
+    UNNEST($8::text[]) AS offline,
+    UNNEST($9::text[]) AS platform,
+    UNNEST($10::text[]) AS conn_country
+) i
+WHERE
+  h.user_id = i.user_id AND
+  h.track_id = i.track_id AND
+  h.played_at BETWEEN i.played_at - $11::int * INTERVAL '1 second' AND i.played_at + $11::int * INTERVAL '1 second'
+`
+
+type HistoryMergeBatchUpdateParams struct {
+	Column1  []int32
+	Column2  []int32
+	Column3  []pgtype.Timestamptz
+	Column4  []string
+	Column5  []string
+	Column6  []string
+	Column7  []string
+	Column8  []string
+	Column9  []string
+	Column10 []string
+	Column11 int32
+}
+
+func (q *Queries) HistoryMergeBatchUpdate(ctx context.Context, arg HistoryMergeBatchUpdateParams) (int64, error) {
+	result, err := q.db.Exec(ctx, historyMergeBatchUpdate,
+		arg.Column1,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+		arg.Column6,
+		arg.Column7,
+		arg.Column8,
+		arg.Column9,
+		arg.Column10,
+		arg.Column11,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const historyUpdate = `-- name: HistoryUpdate :exec
 UPDATE history
 SET 
@@ -348,3 +735,19 @@ func (q *Queries) HistoryUpdate(ctx context.Context, arg HistoryUpdateParams) er
 	_, err := q.db.Exec(ctx, historyUpdate, arg.ID, arg.PlayedAt, arg.Skipped)
 	return err
 }
+
+const historyUpdateSessionBatch = `-- name: HistoryUpdateSessionBatch :exec
+UPDATE history
+SET session_id = $2
+WHERE id = ANY($1::int[])
+`
+
+type HistoryUpdateSessionBatchParams struct {
+	Column1   []int32
+	SessionID pgtype.Int4
+}
+
+func (q *Queries) HistoryUpdateSessionBatch(ctx context.Context, arg HistoryUpdateSessionBatchParams) error {
+	_, err := q.db.Exec(ctx, historyUpdateSessionBatch, arg.Column1, arg.SessionID)
+	return err
+}
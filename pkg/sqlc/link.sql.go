@@ -12,24 +12,32 @@ import (
 )
 
 const linkCreate = `-- name: LinkCreate :one
-INSERT INTO links (source_directory_id, source_playlist_id, target_directory_id, target_playlist_id)
-VALUES ($1, $2, $3, $4)
+INSERT INTO links (source_directory_id, source_playlist_id, source_user_id, target_directory_id, target_playlist_id, mode, filter, include_subdirectories)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING id
 `
 
 type LinkCreateParams struct {
-	SourceDirectoryID pgtype.Int4
-	SourcePlaylistID  pgtype.Int4
-	TargetDirectoryID pgtype.Int4
-	TargetPlaylistID  pgtype.Int4
+	SourceDirectoryID     pgtype.Int4
+	SourcePlaylistID      pgtype.Int4
+	SourceUserID          pgtype.Int4
+	TargetDirectoryID     pgtype.Int4
+	TargetPlaylistID      pgtype.Int4
+	Mode                  LinkMode
+	Filter                []byte
+	IncludeSubdirectories bool
 }
 
 func (q *Queries) LinkCreate(ctx context.Context, arg LinkCreateParams) (int32, error) {
 	row := q.db.QueryRow(ctx, linkCreate,
 		arg.SourceDirectoryID,
 		arg.SourcePlaylistID,
+		arg.SourceUserID,
 		arg.TargetDirectoryID,
 		arg.TargetPlaylistID,
+		arg.Mode,
+		arg.Filter,
+		arg.IncludeSubdirectories,
 	)
 	var id int32
 	err := row.Scan(&id)
@@ -46,13 +54,25 @@ func (q *Queries) LinkDelete(ctx context.Context, id int32) error {
 	return err
 }
 
+const linkDeleteBySourcePlaylistUser = `-- name: LinkDeleteBySourcePlaylistUser :exec
+DELETE FROM links l
+WHERE
+  EXISTS (SELECT 1 FROM playlist_users pu WHERE pu.playlist_id = l.source_playlist_id AND pu.user_id = $1) AND
+  NOT EXISTS (SELECT 1 FROM playlist_users pu WHERE pu.playlist_id = l.source_playlist_id AND pu.user_id != $1 AND pu.deleted_at IS NULL)
+`
+
+func (q *Queries) LinkDeleteBySourcePlaylistUser(ctx context.Context, userID int32) error {
+	_, err := q.db.Exec(ctx, linkDeleteBySourcePlaylistUser, userID)
+	return err
+}
+
 const linkGetByUser = `-- name: LinkGetByUser :many
-SELECT l.id, l.source_directory_id, l.source_playlist_id, l.target_directory_id, l.target_playlist_id
+SELECT l.id, l.source_directory_id, l.source_playlist_id, l.target_directory_id, l.target_playlist_id, l.source_user_id, l.mode, l.filter, l.last_run_at, l.last_run_added, l.last_run_deleted, l.last_run_error, l.include_subdirectories, l.synced_at
 FROM links l
 LEFT JOIN directories d ON d.id = l.source_directory_id
 LEFT JOIN playlists p ON p.id = l.source_playlist_id
 LEFT JOIN playlist_users pu ON pu.playlist_id = p.id
-WHERE d.user_id = $1 OR (pu.user_id = $1 AND pu.deleted_at IS NULL)
+WHERE d.user_id = $1 OR (pu.user_id = $1 AND pu.deleted_at IS NULL) OR l.source_user_id = $1
 `
 
 func (q *Queries) LinkGetByUser(ctx context.Context, userID int32) ([]Link, error) {
@@ -70,6 +90,15 @@ func (q *Queries) LinkGetByUser(ctx context.Context, userID int32) ([]Link, erro
 			&i.SourcePlaylistID,
 			&i.TargetDirectoryID,
 			&i.TargetPlaylistID,
+			&i.SourceUserID,
+			&i.Mode,
+			&i.Filter,
+			&i.LastRunAt,
+			&i.LastRunAdded,
+			&i.LastRunDeleted,
+			&i.LastRunError,
+			&i.IncludeSubdirectories,
+			&i.SyncedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -83,16 +112,20 @@ func (q *Queries) LinkGetByUser(ctx context.Context, userID int32) ([]Link, erro
 
 const linkUpdate = `-- name: LinkUpdate :exec
 UPDATE links
-SET source_directory_id = $2, source_playlist_id = $3, target_directory_id = $4, target_playlist_id = $5
+SET source_directory_id = $2, source_playlist_id = $3, source_user_id = $4, target_directory_id = $5, target_playlist_id = $6, mode = $7, filter = $8, include_subdirectories = $9
 WHERE id = $1
 `
 
 type LinkUpdateParams struct {
-	ID                int32
-	SourceDirectoryID pgtype.Int4
-	SourcePlaylistID  pgtype.Int4
-	TargetDirectoryID pgtype.Int4
-	TargetPlaylistID  pgtype.Int4
+	ID                    int32
+	SourceDirectoryID     pgtype.Int4
+	SourcePlaylistID      pgtype.Int4
+	SourceUserID          pgtype.Int4
+	TargetDirectoryID     pgtype.Int4
+	TargetPlaylistID      pgtype.Int4
+	Mode                  LinkMode
+	Filter                []byte
+	IncludeSubdirectories bool
 }
 
 func (q *Queries) LinkUpdate(ctx context.Context, arg LinkUpdateParams) error {
@@ -100,8 +133,39 @@ func (q *Queries) LinkUpdate(ctx context.Context, arg LinkUpdateParams) error {
 		arg.ID,
 		arg.SourceDirectoryID,
 		arg.SourcePlaylistID,
+		arg.SourceUserID,
 		arg.TargetDirectoryID,
 		arg.TargetPlaylistID,
+		arg.Mode,
+		arg.Filter,
+		arg.IncludeSubdirectories,
+	)
+	return err
+}
+
+const linkUpdateRun = `-- name: LinkUpdateRun :exec
+UPDATE links
+SET last_run_at = $2, last_run_added = $3, last_run_deleted = $4, last_run_error = $5, synced_at = $6
+WHERE id = $1
+`
+
+type LinkUpdateRunParams struct {
+	ID             int32
+	LastRunAt      pgtype.Timestamptz
+	LastRunAdded   int32
+	LastRunDeleted int32
+	LastRunError   pgtype.Text
+	SyncedAt       pgtype.Timestamptz
+}
+
+func (q *Queries) LinkUpdateRun(ctx context.Context, arg LinkUpdateRunParams) error {
+	_, err := q.db.Exec(ctx, linkUpdateRun,
+		arg.ID,
+		arg.LastRunAt,
+		arg.LastRunAdded,
+		arg.LastRunDeleted,
+		arg.LastRunError,
+		arg.SyncedAt,
 	)
 	return err
 }
@@ -11,6 +11,49 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type LinkMode string
+
+const (
+	LinkModeAppend        LinkMode = "append"
+	LinkModeMirror        LinkMode = "mirror"
+	LinkModeBidirectional LinkMode = "bidirectional"
+)
+
+func (e *LinkMode) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LinkMode(s)
+	case string:
+		*e = LinkMode(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LinkMode: %T", src)
+	}
+	return nil
+}
+
+type NullLinkMode struct {
+	LinkMode LinkMode
+	Valid    bool // Valid is true if LinkMode is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLinkMode) Scan(value interface{}) error {
+	if value == nil {
+		ns.LinkMode, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LinkMode.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLinkMode) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LinkMode), nil
+}
+
 type TaskResult string
 
 const (
@@ -78,14 +121,31 @@ type AlbumUser struct {
 }
 
 type Artist struct {
-	ID         int32
-	SpotifyID  string
-	Name       pgtype.Text
-	Followers  pgtype.Int4
-	Popularity pgtype.Int4
-	CoverUrl   pgtype.Text
-	CoverID    pgtype.Text
-	UpdatedAt  pgtype.Timestamptz
+	ID                int32
+	SpotifyID         string
+	Name              pgtype.Text
+	Followers         pgtype.Int4
+	Popularity        pgtype.Int4
+	CoverUrl          pgtype.Text
+	CoverID           pgtype.Text
+	UpdatedAt         pgtype.Timestamptz
+	ReleasesCheckedAt pgtype.Timestamptz
+}
+
+type ArtistRelease struct {
+	ID          int32
+	ArtistID    int32
+	AlbumID     int32
+	AlbumType   string
+	ReleaseDate pgtype.Date
+	CreatedAt   pgtype.Timestamptz
+}
+
+type ArtistUser struct {
+	ID        int32
+	UserID    int32
+	ArtistID  int32
+	DeletedAt pgtype.Timestamptz
 }
 
 type Directory struct {
@@ -93,6 +153,7 @@ type Directory struct {
 	UserID   int32
 	Name     string
 	ParentID pgtype.Int4
+	Rules    []byte
 }
 
 type DirectoryPlaylist struct {
@@ -101,6 +162,31 @@ type DirectoryPlaylist struct {
 	PlaylistID  int32
 }
 
+type Episode struct {
+	ID         int32
+	SpotifyID  string
+	ShowID     int32
+	Name       pgtype.Text
+	DurationMs pgtype.Int4
+	UpdatedAt  pgtype.Timestamptz
+}
+
+type EpisodeHistory struct {
+	ID          int32
+	UserID      int32
+	EpisodeID   int32
+	PlayedAt    pgtype.Timestamptz
+	PositionMs  int32
+	UpdatedAt   pgtype.Timestamptz
+	ReasonStart pgtype.Text
+	ReasonEnd   pgtype.Text
+	Shuffle     pgtype.Bool
+	Offline     pgtype.Bool
+	Platform    pgtype.Text
+	ConnCountry pgtype.Text
+	Source      string
+}
+
 type Generator struct {
 	ID              int32
 	UserID          int32
@@ -121,23 +207,60 @@ type GeneratorTrack struct {
 }
 
 type History struct {
-	ID         int32
+	ID          int32
+	UserID      int32
+	TrackID     int32
+	PlayedAt    pgtype.Timestamptz
+	AlbumID     pgtype.Int4
+	ArtistID    pgtype.Int4
+	PlaylistID  pgtype.Int4
+	ShowID      pgtype.Int4
+	Skipped     pgtype.Bool
+	ReasonStart pgtype.Text
+	ReasonEnd   pgtype.Text
+	Shuffle     pgtype.Bool
+	Offline     pgtype.Bool
+	Platform    pgtype.Text
+	ConnCountry pgtype.Text
+	Source      string
+	SessionID   pgtype.Int4
+}
+
+type HistoryRollup struct {
 	UserID     int32
+	Day        pgtype.Date
+	Hour       int32
 	TrackID    int32
-	PlayedAt   pgtype.Timestamptz
+	PlaylistID int32
+	Plays      int32
+}
+
+type HistorySession struct {
+	ID         int32
+	UserID     int32
+	StartAt    pgtype.Timestamptz
+	EndAt      pgtype.Timestamptz
+	PlaylistID pgtype.Int4
 	AlbumID    pgtype.Int4
 	ArtistID   pgtype.Int4
-	PlaylistID pgtype.Int4
 	ShowID     pgtype.Int4
-	Skipped    pgtype.Bool
 }
 
 type Link struct {
-	ID                int32
-	SourceDirectoryID pgtype.Int4
-	SourcePlaylistID  pgtype.Int4
-	TargetDirectoryID pgtype.Int4
-	TargetPlaylistID  pgtype.Int4
+	ID                    int32
+	SourceDirectoryID     pgtype.Int4
+	SourcePlaylistID      pgtype.Int4
+	TargetDirectoryID     pgtype.Int4
+	TargetPlaylistID      pgtype.Int4
+	SourceUserID          pgtype.Int4
+	Mode                  LinkMode
+	Filter                []byte
+	LastRunAt             pgtype.Timestamptz
+	LastRunAdded          int32
+	LastRunDeleted        int32
+	LastRunError          pgtype.Text
+	IncludeSubdirectories bool
+	SyncedAt              pgtype.Timestamptz
 }
 
 type Playlist struct {
@@ -170,6 +293,15 @@ type PlaylistUser struct {
 	DeletedAt  pgtype.Timestamptz
 }
 
+type Recap struct {
+	ID        int32
+	UserID    int32
+	Year      int32
+	Month     int32
+	Data      []byte
+	CreatedAt pgtype.Timestamptz
+}
+
 type Show struct {
 	ID            int32
 	SpotifyID     string
@@ -212,6 +344,8 @@ type Track struct {
 	Popularity pgtype.Int4
 	UpdatedAt  pgtype.Timestamptz
 	DurationMs pgtype.Int4
+	Explicit   pgtype.Bool
+	AlbumID    pgtype.Int4
 }
 
 type TrackArtist struct {
@@ -221,9 +355,20 @@ type TrackArtist struct {
 }
 
 type User struct {
-	ID          int32
-	Uid         string
-	Name        string
-	DisplayName pgtype.Text
-	Email       string
+	ID                  int32
+	Uid                 string
+	Name                string
+	DisplayName         pgtype.Text
+	Email               string
+	RecentlyPlayedAfter pgtype.Timestamptz
+	Timezone            string
+	RollupDirtyFrom     pgtype.Timestamptz
+}
+
+type UserTrack struct {
+	ID        int32
+	UserID    int32
+	TrackID   int32
+	SavedAt   pgtype.Timestamptz
+	DeletedAt pgtype.Timestamptz
 }
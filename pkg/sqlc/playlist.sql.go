@@ -48,6 +48,36 @@ func (q *Queries) PlaylistCreate(ctx context.Context, arg PlaylistCreateParams)
 	return id, err
 }
 
+const playlistDeleteUnused = `-- name: PlaylistDeleteUnused :many
+DELETE FROM playlists p
+WHERE
+  p.id = ANY($1::int[]) AND
+  NOT EXISTS (SELECT 1 FROM playlist_users pu WHERE pu.playlist_id = p.id) AND
+  NOT EXISTS (SELECT 1 FROM history h WHERE h.playlist_id = p.id) AND
+  NOT EXISTS (SELECT 1 FROM generators g WHERE g.playlist_id = p.id)
+RETURNING cover_id
+`
+
+func (q *Queries) PlaylistDeleteUnused(ctx context.Context, dollar_1 []int32) ([]pgtype.Text, error) {
+	rows, err := q.db.Query(ctx, playlistDeleteUnused, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.Text
+	for rows.Next() {
+		var cover_id pgtype.Text
+		if err := rows.Scan(&cover_id); err != nil {
+			return nil, err
+		}
+		items = append(items, cover_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const playlistGet = `-- name: PlaylistGet :one
 SELECT id, spotify_id, name, description, public, track_amount, collaborative, cover_id, cover_url, owner_id, updated_at, snapshot_id
 FROM playlists
@@ -142,7 +172,7 @@ func (q *Queries) PlaylistGetByUser(ctx context.Context, userID int32) ([]Playli
 }
 
 const playlistGetByUserWithOwner = `-- name: PlaylistGetByUserWithOwner :many
-SELECT p.id, p.spotify_id, p.name, p.description, p.public, p.track_amount, p.collaborative, p.cover_id, p.cover_url, p.owner_id, p.updated_at, p.snapshot_id, u.id, u.uid, u.name, u.display_name, u.email
+SELECT p.id, p.spotify_id, p.name, p.description, p.public, p.track_amount, p.collaborative, p.cover_id, p.cover_url, p.owner_id, p.updated_at, p.snapshot_id, u.id, u.uid, u.name, u.display_name, u.email, u.recently_played_after, u.timezone, u.rollup_dirty_from
 FROM playlists p
 LEFT JOIN playlist_users pu ON pu.playlist_id = p.id
 LEFT JOIN users u ON u.id = p.owner_id
@@ -182,6 +212,9 @@ func (q *Queries) PlaylistGetByUserWithOwner(ctx context.Context, userID int32)
 			&i.User.Name,
 			&i.User.DisplayName,
 			&i.User.Email,
+			&i.User.RecentlyPlayedAfter,
+			&i.User.Timezone,
+			&i.User.RollupDirtyFrom,
 		); err != nil {
 			return nil, err
 		}
@@ -194,7 +227,7 @@ func (q *Queries) PlaylistGetByUserWithOwner(ctx context.Context, userID int32)
 }
 
 const playlistGetDuplicateTracksByUser = `-- name: PlaylistGetDuplicateTracksByUser :many
-SELECT p.id, p.spotify_id, p.name, p.description, p.public, p.track_amount, p.collaborative, p.cover_id, p.cover_url, p.owner_id, p.updated_at, p.snapshot_id, t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, u.id, u.uid, u.name, u.display_name, u.email
+SELECT p.id, p.spotify_id, p.name, p.description, p.public, p.track_amount, p.collaborative, p.cover_id, p.cover_url, p.owner_id, p.updated_at, p.snapshot_id, t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id, u.id, u.uid, u.name, u.display_name, u.email, u.recently_played_after, u.timezone, u.rollup_dirty_from
 FROM playlist_tracks pt
 JOIN (
   SELECT playlist_id, track_id
@@ -247,11 +280,16 @@ func (q *Queries) PlaylistGetDuplicateTracksByUser(ctx context.Context, userID i
 			&i.Track.Popularity,
 			&i.Track.UpdatedAt,
 			&i.Track.DurationMs,
+			&i.Track.Explicit,
+			&i.Track.AlbumID,
 			&i.User.ID,
 			&i.User.Uid,
 			&i.User.Name,
 			&i.User.DisplayName,
 			&i.User.Email,
+			&i.User.RecentlyPlayedAfter,
+			&i.User.Timezone,
+			&i.User.RollupDirtyFrom,
 		); err != nil {
 			return nil, err
 		}
@@ -264,7 +302,7 @@ func (q *Queries) PlaylistGetDuplicateTracksByUser(ctx context.Context, userID i
 }
 
 const playlistGetUnplayableTracksByUser = `-- name: PlaylistGetUnplayableTracksByUser :many
-SELECT p.id, p.spotify_id, p.name, p.description, p.public, p.track_amount, p.collaborative, p.cover_id, p.cover_url, p.owner_id, p.updated_at, p.snapshot_id, t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, u.id, u.uid, u.name, u.display_name, u.email
+SELECT p.id, p.spotify_id, p.name, p.description, p.public, p.track_amount, p.collaborative, p.cover_id, p.cover_url, p.owner_id, p.updated_at, p.snapshot_id, t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id, u.id, u.uid, u.name, u.display_name, u.email, u.recently_played_after, u.timezone, u.rollup_dirty_from
 FROM playlist_tracks pt
 LEFT JOIN playlists p ON p.id = pt.playlist_id
 LEFT JOIN tracks t ON t.id = pt.track_id
@@ -308,11 +346,16 @@ func (q *Queries) PlaylistGetUnplayableTracksByUser(ctx context.Context, userID
 			&i.Track.Popularity,
 			&i.Track.UpdatedAt,
 			&i.Track.DurationMs,
+			&i.Track.Explicit,
+			&i.Track.AlbumID,
 			&i.User.ID,
 			&i.User.Uid,
 			&i.User.Name,
 			&i.User.DisplayName,
 			&i.User.Email,
+			&i.User.RecentlyPlayedAfter,
+			&i.User.Timezone,
+			&i.User.RollupDirtyFrom,
 		); err != nil {
 			return nil, err
 		}
@@ -368,3 +411,30 @@ func (q *Queries) PlaylistUpdateBySpotify(ctx context.Context, arg PlaylistUpdat
 	)
 	return err
 }
+
+const playlistUpdateOwnerClear = `-- name: PlaylistUpdateOwnerClear :many
+UPDATE playlists
+SET owner_id = NULL
+WHERE owner_id = $1
+RETURNING id
+`
+
+func (q *Queries) PlaylistUpdateOwnerClear(ctx context.Context, ownerID pgtype.Int4) ([]int32, error) {
+	rows, err := q.db.Query(ctx, playlistUpdateOwnerClear, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -74,3 +74,30 @@ func (q *Queries) PlaylistTrackGetByPlaylistIds(ctx context.Context, dollar_1 []
 	}
 	return items, nil
 }
+
+const playlistTrackGetPlaylistIdsByArtist = `-- name: PlaylistTrackGetPlaylistIdsByArtist :many
+SELECT DISTINCT pt.playlist_id
+FROM playlist_tracks pt
+JOIN track_artists ta ON ta.track_id = pt.track_id
+WHERE ta.artist_id = $1 AND pt.deleted_at IS NULL
+`
+
+func (q *Queries) PlaylistTrackGetPlaylistIdsByArtist(ctx context.Context, artistID int32) ([]int32, error) {
+	rows, err := q.db.Query(ctx, playlistTrackGetPlaylistIdsByArtist, artistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var playlist_id int32
+		if err := rows.Scan(&playlist_id); err != nil {
+			return nil, err
+		}
+		items = append(items, playlist_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -12,8 +12,8 @@ import (
 )
 
 const trackCreate = `-- name: TrackCreate :one
-INSERT INTO tracks (spotify_id, name, popularity, duration_ms)
-VALUES ($1, $2, $3, $4)
+INSERT INTO tracks (spotify_id, name, popularity, duration_ms, explicit)
+VALUES ($1, $2, $3, $4, $5)
 RETURNING id
 `
 
@@ -22,6 +22,7 @@ type TrackCreateParams struct {
 	Name       pgtype.Text
 	Popularity pgtype.Int4
 	DurationMs pgtype.Int4
+	Explicit   pgtype.Bool
 }
 
 func (q *Queries) TrackCreate(ctx context.Context, arg TrackCreateParams) (int32, error) {
@@ -30,6 +31,7 @@ func (q *Queries) TrackCreate(ctx context.Context, arg TrackCreateParams) (int32
 		arg.Name,
 		arg.Popularity,
 		arg.DurationMs,
+		arg.Explicit,
 	)
 	var id int32
 	err := row.Scan(&id)
@@ -37,7 +39,7 @@ func (q *Queries) TrackCreate(ctx context.Context, arg TrackCreateParams) (int32
 }
 
 const trackGetAll = `-- name: TrackGetAll :many
-SELECT id, spotify_id, name, popularity, updated_at, duration_ms
+SELECT id, spotify_id, name, popularity, updated_at, duration_ms, explicit, album_id
 FROM tracks
 `
 
@@ -57,6 +59,8 @@ func (q *Queries) TrackGetAll(ctx context.Context) ([]Track, error) {
 			&i.Popularity,
 			&i.UpdatedAt,
 			&i.DurationMs,
+			&i.Explicit,
+			&i.AlbumID,
 		); err != nil {
 			return nil, err
 		}
@@ -69,7 +73,7 @@ func (q *Queries) TrackGetAll(ctx context.Context) ([]Track, error) {
 }
 
 const trackGetAllById = `-- name: TrackGetAllById :many
-SELECT id, spotify_id, name, popularity, updated_at, duration_ms
+SELECT id, spotify_id, name, popularity, updated_at, duration_ms, explicit, album_id
 FROM tracks
 WHERE id = ANY($1::int[])
 `
@@ -90,6 +94,8 @@ func (q *Queries) TrackGetAllById(ctx context.Context, dollar_1 []int32) ([]Trac
 			&i.Popularity,
 			&i.UpdatedAt,
 			&i.DurationMs,
+			&i.Explicit,
+			&i.AlbumID,
 		); err != nil {
 			return nil, err
 		}
@@ -102,7 +108,7 @@ func (q *Queries) TrackGetAllById(ctx context.Context, dollar_1 []int32) ([]Trac
 }
 
 const trackGetAllBySpotify = `-- name: TrackGetAllBySpotify :many
-SELECT id, spotify_id, name, popularity, updated_at, duration_ms
+SELECT id, spotify_id, name, popularity, updated_at, duration_ms, explicit, album_id
 FROM tracks
 WHERE spotify_id = ANY($1::text[])
 `
@@ -123,6 +129,8 @@ func (q *Queries) TrackGetAllBySpotify(ctx context.Context, dollar_1 []string) (
 			&i.Popularity,
 			&i.UpdatedAt,
 			&i.DurationMs,
+			&i.Explicit,
+			&i.AlbumID,
 		); err != nil {
 			return nil, err
 		}
@@ -135,7 +143,7 @@ func (q *Queries) TrackGetAllBySpotify(ctx context.Context, dollar_1 []string) (
 }
 
 const trackGetByGenerator = `-- name: TrackGetByGenerator :many
-SELECT t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms
+SELECT t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id
 FROM tracks t
 LEFT JOIN generator_tracks gt ON gt.track_id = t.id
 WHERE gt.generator_id = $1
@@ -158,6 +166,8 @@ func (q *Queries) TrackGetByGenerator(ctx context.Context, generatorID int32) ([
 			&i.Popularity,
 			&i.UpdatedAt,
 			&i.DurationMs,
+			&i.Explicit,
+			&i.AlbumID,
 		); err != nil {
 			return nil, err
 		}
@@ -170,7 +180,7 @@ func (q *Queries) TrackGetByGenerator(ctx context.Context, generatorID int32) ([
 }
 
 const trackGetByName = `-- name: TrackGetByName :many
-SELECT id, spotify_id, name, popularity, updated_at, duration_ms
+SELECT id, spotify_id, name, popularity, updated_at, duration_ms, explicit, album_id
 FROM tracks
 WHERE name = $1
 `
@@ -191,6 +201,8 @@ func (q *Queries) TrackGetByName(ctx context.Context, name pgtype.Text) ([]Track
 			&i.Popularity,
 			&i.UpdatedAt,
 			&i.DurationMs,
+			&i.Explicit,
+			&i.AlbumID,
 		); err != nil {
 			return nil, err
 		}
@@ -203,28 +215,36 @@ func (q *Queries) TrackGetByName(ctx context.Context, name pgtype.Text) ([]Track
 }
 
 const trackGetByPlaylist = `-- name: TrackGetByPlaylist :many
-SELECT t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms
+SELECT t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id, pt.created_at AS added_at
 FROM tracks t
 LEFT JOIN playlist_tracks pt ON pt.track_id = t.id
 WHERE pt.playlist_id = $1 AND pt.deleted_at IS NULL
 `
 
-func (q *Queries) TrackGetByPlaylist(ctx context.Context, playlistID int32) ([]Track, error) {
+type TrackGetByPlaylistRow struct {
+	Track   Track
+	AddedAt pgtype.Timestamptz
+}
+
+func (q *Queries) TrackGetByPlaylist(ctx context.Context, playlistID int32) ([]TrackGetByPlaylistRow, error) {
 	rows, err := q.db.Query(ctx, trackGetByPlaylist, playlistID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Track
+	var items []TrackGetByPlaylistRow
 	for rows.Next() {
-		var i Track
+		var i TrackGetByPlaylistRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.SpotifyID,
-			&i.Name,
-			&i.Popularity,
-			&i.UpdatedAt,
-			&i.DurationMs,
+			&i.Track.ID,
+			&i.Track.SpotifyID,
+			&i.Track.Name,
+			&i.Track.Popularity,
+			&i.Track.UpdatedAt,
+			&i.Track.DurationMs,
+			&i.Track.Explicit,
+			&i.Track.AlbumID,
+			&i.AddedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -237,7 +257,7 @@ func (q *Queries) TrackGetByPlaylist(ctx context.Context, playlistID int32) ([]T
 }
 
 const trackGetBySpotify = `-- name: TrackGetBySpotify :one
-SELECT id, spotify_id, name, popularity, updated_at, duration_ms
+SELECT id, spotify_id, name, popularity, updated_at, duration_ms, explicit, album_id
 FROM tracks
 WHERE spotify_id = $1
 `
@@ -252,12 +272,14 @@ func (q *Queries) TrackGetBySpotify(ctx context.Context, spotifyID string) (Trac
 		&i.Popularity,
 		&i.UpdatedAt,
 		&i.DurationMs,
+		&i.Explicit,
+		&i.AlbumID,
 	)
 	return i, err
 }
 
 const trackGetCreatedFilteredPopulated = `-- name: TrackGetCreatedFilteredPopulated :many
-SELECT t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, pt.id, pt.playlist_id, pt.track_id, pt.deleted_at, pt.created_at, p.id, p.spotify_id, p.name, p.description, p.public, p.track_amount, p.collaborative, p.cover_id, p.cover_url, p.owner_id, p.updated_at, p.snapshot_id, u.id, u.uid, u.name, u.display_name, u.email
+SELECT t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id, pt.id, pt.playlist_id, pt.track_id, pt.deleted_at, pt.created_at, p.id, p.spotify_id, p.name, p.description, p.public, p.track_amount, p.collaborative, p.cover_id, p.cover_url, p.owner_id, p.updated_at, p.snapshot_id, u.id, u.uid, u.name, u.display_name, u.email, u.recently_played_after, u.timezone, u.rollup_dirty_from
 FROM tracks t
 LEFT JOIN playlist_tracks pt ON pt.track_id = t.id
 LEFT JOIN playlist_users pu ON pu.playlist_id = pt.playlist_id
@@ -308,6 +330,8 @@ func (q *Queries) TrackGetCreatedFilteredPopulated(ctx context.Context, arg Trac
 			&i.Track.Popularity,
 			&i.Track.UpdatedAt,
 			&i.Track.DurationMs,
+			&i.Track.Explicit,
+			&i.Track.AlbumID,
 			&i.PlaylistTrack.ID,
 			&i.PlaylistTrack.PlaylistID,
 			&i.PlaylistTrack.TrackID,
@@ -330,6 +354,50 @@ func (q *Queries) TrackGetCreatedFilteredPopulated(ctx context.Context, arg Trac
 			&i.User.Name,
 			&i.User.DisplayName,
 			&i.User.Email,
+			&i.User.RecentlyPlayedAfter,
+			&i.User.Timezone,
+			&i.User.RollupDirtyFrom,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const trackGetDeletedByPlaylistAfter = `-- name: TrackGetDeletedByPlaylistAfter :many
+SELECT DISTINCT t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id
+FROM tracks t
+JOIN playlist_tracks pt ON pt.track_id = t.id
+WHERE pt.playlist_id = $1 AND pt.deleted_at > $2
+`
+
+type TrackGetDeletedByPlaylistAfterParams struct {
+	PlaylistID int32
+	DeletedAt  pgtype.Timestamptz
+}
+
+func (q *Queries) TrackGetDeletedByPlaylistAfter(ctx context.Context, arg TrackGetDeletedByPlaylistAfterParams) ([]Track, error) {
+	rows, err := q.db.Query(ctx, trackGetDeletedByPlaylistAfter, arg.PlaylistID, arg.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Track
+	for rows.Next() {
+		var i Track
+		if err := rows.Scan(
+			&i.ID,
+			&i.SpotifyID,
+			&i.Name,
+			&i.Popularity,
+			&i.UpdatedAt,
+			&i.DurationMs,
+			&i.Explicit,
+			&i.AlbumID,
 		); err != nil {
 			return nil, err
 		}
@@ -342,7 +410,7 @@ func (q *Queries) TrackGetCreatedFilteredPopulated(ctx context.Context, arg Trac
 }
 
 const trackGetDeletedFilteredPopulated = `-- name: TrackGetDeletedFilteredPopulated :many
-SELECT t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, pt.id, pt.playlist_id, pt.track_id, pt.deleted_at, pt.created_at, p.id, p.spotify_id, p.name, p.description, p.public, p.track_amount, p.collaborative, p.cover_id, p.cover_url, p.owner_id, p.updated_at, p.snapshot_id, u.id, u.uid, u.name, u.display_name, u.email
+SELECT t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id, pt.id, pt.playlist_id, pt.track_id, pt.deleted_at, pt.created_at, p.id, p.spotify_id, p.name, p.description, p.public, p.track_amount, p.collaborative, p.cover_id, p.cover_url, p.owner_id, p.updated_at, p.snapshot_id, u.id, u.uid, u.name, u.display_name, u.email, u.recently_played_after, u.timezone, u.rollup_dirty_from
 FROM tracks t
 LEFT JOIN playlist_tracks pt ON pt.track_id = t.id
 LEFT JOIN playlist_users pu ON pu.playlist_id = pt.playlist_id
@@ -393,6 +461,8 @@ func (q *Queries) TrackGetDeletedFilteredPopulated(ctx context.Context, arg Trac
 			&i.Track.Popularity,
 			&i.Track.UpdatedAt,
 			&i.Track.DurationMs,
+			&i.Track.Explicit,
+			&i.Track.AlbumID,
 			&i.PlaylistTrack.ID,
 			&i.PlaylistTrack.PlaylistID,
 			&i.PlaylistTrack.TrackID,
@@ -415,6 +485,314 @@ func (q *Queries) TrackGetDeletedFilteredPopulated(ctx context.Context, arg Trac
 			&i.User.Name,
 			&i.User.DisplayName,
 			&i.User.Email,
+			&i.User.RecentlyPlayedAfter,
+			&i.User.Timezone,
+			&i.User.RollupDirtyFrom,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const trackGetMatchByArtistName = `-- name: TrackGetMatchByArtistName :many
+SELECT DISTINCT ON (i.idx) i.idx::int AS idx, t.id
+FROM (
+  SELECT
+    UNNEST($1::text[]) AS artist,
+    UNNEST($2::text[]) AS name,
+    UNNEST($3::int[]) AS duration_ms,
+    generate_subscripts($1::text[], 1) AS idx
+) i
+JOIN tracks t ON lower(t.name) = lower(i.name)
+JOIN track_artists ta ON ta.track_id = t.id
+JOIN artists a ON a.id = ta.artist_id
+WHERE
+  lower(a.name) = lower(i.artist) AND
+  (i.duration_ms = 0 OR t.duration_ms IS NULL OR abs(t.duration_ms - i.duration_ms) <= $4::int)
+ORDER BY i.idx, abs(coalesce(t.duration_ms, i.duration_ms) - i.duration_ms), t.id
+`
+
+type TrackGetMatchByArtistNameParams struct {
+	Column1 []string
+	Column2 []string
+	Column3 []int32
+	Column4 int32
+}
+
+type TrackGetMatchByArtistNameRow struct {
+	Idx int32
+	ID  int32
+}
+
+func (q *Queries) TrackGetMatchByArtistName(ctx context.Context, arg TrackGetMatchByArtistNameParams) ([]TrackGetMatchByArtistNameRow, error) {
+	rows, err := q.db.Query(ctx, trackGetMatchByArtistName,
+		arg.Column1,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TrackGetMatchByArtistNameRow
+	for rows.Next() {
+		var i TrackGetMatchByArtistNameRow
+		if err := rows.Scan(&i.Idx, &i.ID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const trackGetSavedByUser = `-- name: TrackGetSavedByUser :many
+SELECT t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id, ut.saved_at AS added_at
+FROM tracks t
+LEFT JOIN user_tracks ut ON ut.track_id = t.id
+WHERE ut.user_id = $1 AND ut.deleted_at IS NULL
+`
+
+type TrackGetSavedByUserRow struct {
+	Track   Track
+	AddedAt pgtype.Timestamptz
+}
+
+func (q *Queries) TrackGetSavedByUser(ctx context.Context, userID int32) ([]TrackGetSavedByUserRow, error) {
+	rows, err := q.db.Query(ctx, trackGetSavedByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TrackGetSavedByUserRow
+	for rows.Next() {
+		var i TrackGetSavedByUserRow
+		if err := rows.Scan(
+			&i.Track.ID,
+			&i.Track.SpotifyID,
+			&i.Track.Name,
+			&i.Track.Popularity,
+			&i.Track.UpdatedAt,
+			&i.Track.DurationMs,
+			&i.Track.Explicit,
+			&i.Track.AlbumID,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const trackGetSavedCreatedFiltered = `-- name: TrackGetSavedCreatedFiltered :many
+SELECT t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id, ut.id, ut.user_id, ut.track_id, ut.saved_at, ut.deleted_at
+FROM tracks t
+LEFT JOIN user_tracks ut ON ut.track_id = t.id
+WHERE ut.user_id = $3::int AND ut.deleted_at IS NULL
+ORDER BY ut.saved_at DESC
+LIMIT $1 OFFSET $2
+`
+
+type TrackGetSavedCreatedFilteredParams struct {
+	Limit   int32
+	Offset  int32
+	Column3 int32
+}
+
+type TrackGetSavedCreatedFilteredRow struct {
+	Track     Track
+	UserTrack UserTrack
+}
+
+func (q *Queries) TrackGetSavedCreatedFiltered(ctx context.Context, arg TrackGetSavedCreatedFilteredParams) ([]TrackGetSavedCreatedFilteredRow, error) {
+	rows, err := q.db.Query(ctx, trackGetSavedCreatedFiltered, arg.Limit, arg.Offset, arg.Column3)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TrackGetSavedCreatedFilteredRow
+	for rows.Next() {
+		var i TrackGetSavedCreatedFilteredRow
+		if err := rows.Scan(
+			&i.Track.ID,
+			&i.Track.SpotifyID,
+			&i.Track.Name,
+			&i.Track.Popularity,
+			&i.Track.UpdatedAt,
+			&i.Track.DurationMs,
+			&i.Track.Explicit,
+			&i.Track.AlbumID,
+			&i.UserTrack.ID,
+			&i.UserTrack.UserID,
+			&i.UserTrack.TrackID,
+			&i.UserTrack.SavedAt,
+			&i.UserTrack.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const trackGetSavedDeletedByUserAfter = `-- name: TrackGetSavedDeletedByUserAfter :many
+SELECT DISTINCT t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id
+FROM tracks t
+JOIN user_tracks ut ON ut.track_id = t.id
+WHERE ut.user_id = $1 AND ut.deleted_at > $2
+`
+
+type TrackGetSavedDeletedByUserAfterParams struct {
+	UserID    int32
+	DeletedAt pgtype.Timestamptz
+}
+
+func (q *Queries) TrackGetSavedDeletedByUserAfter(ctx context.Context, arg TrackGetSavedDeletedByUserAfterParams) ([]Track, error) {
+	rows, err := q.db.Query(ctx, trackGetSavedDeletedByUserAfter, arg.UserID, arg.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Track
+	for rows.Next() {
+		var i Track
+		if err := rows.Scan(
+			&i.ID,
+			&i.SpotifyID,
+			&i.Name,
+			&i.Popularity,
+			&i.UpdatedAt,
+			&i.DurationMs,
+			&i.Explicit,
+			&i.AlbumID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const trackGetSavedDeletedFiltered = `-- name: TrackGetSavedDeletedFiltered :many
+SELECT t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id, ut.id, ut.user_id, ut.track_id, ut.saved_at, ut.deleted_at
+FROM tracks t
+LEFT JOIN user_tracks ut ON ut.track_id = t.id
+WHERE ut.user_id = $3::int AND ut.deleted_at IS NOT NULL
+ORDER BY ut.deleted_at DESC
+LIMIT $1 OFFSET $2
+`
+
+type TrackGetSavedDeletedFilteredParams struct {
+	Limit   int32
+	Offset  int32
+	Column3 int32
+}
+
+type TrackGetSavedDeletedFilteredRow struct {
+	Track     Track
+	UserTrack UserTrack
+}
+
+func (q *Queries) TrackGetSavedDeletedFiltered(ctx context.Context, arg TrackGetSavedDeletedFilteredParams) ([]TrackGetSavedDeletedFilteredRow, error) {
+	rows, err := q.db.Query(ctx, trackGetSavedDeletedFiltered, arg.Limit, arg.Offset, arg.Column3)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TrackGetSavedDeletedFilteredRow
+	for rows.Next() {
+		var i TrackGetSavedDeletedFilteredRow
+		if err := rows.Scan(
+			&i.Track.ID,
+			&i.Track.SpotifyID,
+			&i.Track.Name,
+			&i.Track.Popularity,
+			&i.Track.UpdatedAt,
+			&i.Track.DurationMs,
+			&i.Track.Explicit,
+			&i.Track.AlbumID,
+			&i.UserTrack.ID,
+			&i.UserTrack.UserID,
+			&i.UserTrack.TrackID,
+			&i.UserTrack.SavedAt,
+			&i.UserTrack.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const trackGetSavedInPlaylistByUser = `-- name: TrackGetSavedInPlaylistByUser :many
+SELECT t.id, t.spotify_id, t.name, t.popularity, t.updated_at, t.duration_ms, t.explicit, t.album_id, p.id, p.spotify_id, p.name, p.description, p.public, p.track_amount, p.collaborative, p.cover_id, p.cover_url, p.owner_id, p.updated_at, p.snapshot_id
+FROM user_tracks ut
+JOIN tracks t ON t.id = ut.track_id
+JOIN playlist_tracks pt ON pt.track_id = t.id
+JOIN playlists p ON p.id = pt.playlist_id
+JOIN playlist_users pu ON pu.playlist_id = p.id AND pu.user_id = ut.user_id
+WHERE
+  ut.user_id = $1 AND ut.deleted_at IS NULL AND
+  pt.deleted_at IS NULL AND pu.deleted_at IS NULL AND p.owner_id = ut.user_id
+ORDER BY t.id, p.id, pt.id
+`
+
+type TrackGetSavedInPlaylistByUserRow struct {
+	Track    Track
+	Playlist Playlist
+}
+
+func (q *Queries) TrackGetSavedInPlaylistByUser(ctx context.Context, userID int32) ([]TrackGetSavedInPlaylistByUserRow, error) {
+	rows, err := q.db.Query(ctx, trackGetSavedInPlaylistByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TrackGetSavedInPlaylistByUserRow
+	for rows.Next() {
+		var i TrackGetSavedInPlaylistByUserRow
+		if err := rows.Scan(
+			&i.Track.ID,
+			&i.Track.SpotifyID,
+			&i.Track.Name,
+			&i.Track.Popularity,
+			&i.Track.UpdatedAt,
+			&i.Track.DurationMs,
+			&i.Track.Explicit,
+			&i.Track.AlbumID,
+			&i.Playlist.ID,
+			&i.Playlist.SpotifyID,
+			&i.Playlist.Name,
+			&i.Playlist.Description,
+			&i.Playlist.Public,
+			&i.Playlist.TrackAmount,
+			&i.Playlist.Collaborative,
+			&i.Playlist.CoverID,
+			&i.Playlist.CoverUrl,
+			&i.Playlist.OwnerID,
+			&i.Playlist.UpdatedAt,
+			&i.Playlist.SnapshotID,
 		); err != nil {
 			return nil, err
 		}
@@ -432,6 +810,8 @@ SET
   name = coalesce($2, name),
   popularity = coalesce($3, popularity),
   duration_ms = coalesce($4, duration_ms),
+  explicit = coalesce($5, explicit),
+  album_id = coalesce($6, album_id),
   updated_at = NOW()
 WHERE id = $1
 `
@@ -441,6 +821,8 @@ type TrackUpdateParams struct {
 	Name       pgtype.Text
 	Popularity pgtype.Int4
 	DurationMs pgtype.Int4
+	Explicit   pgtype.Bool
+	AlbumID    pgtype.Int4
 }
 
 func (q *Queries) TrackUpdate(ctx context.Context, arg TrackUpdateParams) error {
@@ -449,6 +831,8 @@ func (q *Queries) TrackUpdate(ctx context.Context, arg TrackUpdateParams) error
 		arg.Name,
 		arg.Popularity,
 		arg.DurationMs,
+		arg.Explicit,
+		arg.AlbumID,
 	)
 	return err
 }
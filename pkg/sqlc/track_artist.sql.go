@@ -41,3 +41,29 @@ func (q *Queries) TrackArtistDeleteByArtistTrack(ctx context.Context, arg TrackA
 	_, err := q.db.Exec(ctx, trackArtistDeleteByArtistTrack, arg.ArtistID, arg.TrackID)
 	return err
 }
+
+const trackArtistGetByTracks = `-- name: TrackArtistGetByTracks :many
+SELECT id, artist_id, track_id
+FROM track_artists
+WHERE track_id = ANY($1::int[])
+`
+
+func (q *Queries) TrackArtistGetByTracks(ctx context.Context, dollar_1 []int32) ([]TrackArtist, error) {
+	rows, err := q.db.Query(ctx, trackArtistGetByTracks, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TrackArtist
+	for rows.Next() {
+		var i TrackArtist
+		if err := rows.Scan(&i.ID, &i.ArtistID, &i.TrackID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -36,8 +36,18 @@ func (q *Queries) UserCreate(ctx context.Context, arg UserCreateParams) (int32,
 	return id, err
 }
 
+const userDelete = `-- name: UserDelete :exec
+DELETE FROM users
+WHERE id = $1
+`
+
+func (q *Queries) UserDelete(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, userDelete, id)
+	return err
+}
+
 const userGet = `-- name: UserGet :one
-SELECT id, uid, name, display_name, email
+SELECT id, uid, name, display_name, email, recently_played_after, timezone, rollup_dirty_from
 FROM users
 WHERE id = $1
 `
@@ -51,12 +61,15 @@ func (q *Queries) UserGet(ctx context.Context, id int32) (User, error) {
 		&i.Name,
 		&i.DisplayName,
 		&i.Email,
+		&i.RecentlyPlayedAfter,
+		&i.Timezone,
+		&i.RollupDirtyFrom,
 	)
 	return i, err
 }
 
 const userGetActualAll = `-- name: UserGetActualAll :many
-SELECT id, uid, name, display_name, email
+SELECT id, uid, name, display_name, email, recently_played_after, timezone, rollup_dirty_from
 FROM users
 WHERE email != ''
 `
@@ -76,6 +89,9 @@ func (q *Queries) UserGetActualAll(ctx context.Context) ([]User, error) {
 			&i.Name,
 			&i.DisplayName,
 			&i.Email,
+			&i.RecentlyPlayedAfter,
+			&i.Timezone,
+			&i.RollupDirtyFrom,
 		); err != nil {
 			return nil, err
 		}
@@ -88,7 +104,7 @@ func (q *Queries) UserGetActualAll(ctx context.Context) ([]User, error) {
 }
 
 const userGetAllByID = `-- name: UserGetAllByID :many
-SELECT id, uid, name, display_name, email
+SELECT id, uid, name, display_name, email, recently_played_after, timezone, rollup_dirty_from
 FROM users
 WHERE id = ANY($1::int[])
 `
@@ -108,6 +124,9 @@ func (q *Queries) UserGetAllByID(ctx context.Context, dollar_1 []int32) ([]User,
 			&i.Name,
 			&i.DisplayName,
 			&i.Email,
+			&i.RecentlyPlayedAfter,
+			&i.Timezone,
+			&i.RollupDirtyFrom,
 		); err != nil {
 			return nil, err
 		}
@@ -120,7 +139,7 @@ func (q *Queries) UserGetAllByID(ctx context.Context, dollar_1 []int32) ([]User,
 }
 
 const userGetByUID = `-- name: UserGetByUID :one
-SELECT id, uid, name, display_name, email
+SELECT id, uid, name, display_name, email, recently_played_after, timezone, rollup_dirty_from
 FROM users
 WHERE uid = $1
 `
@@ -134,6 +153,9 @@ func (q *Queries) UserGetByUID(ctx context.Context, uid string) (User, error) {
 		&i.Name,
 		&i.DisplayName,
 		&i.Email,
+		&i.RecentlyPlayedAfter,
+		&i.Timezone,
+		&i.RollupDirtyFrom,
 	)
 	return i, err
 }
@@ -160,3 +182,74 @@ func (q *Queries) UserUpdate(ctx context.Context, arg UserUpdateParams) error {
 	)
 	return err
 }
+
+const userUpdateRecentlyPlayedAfter = `-- name: UserUpdateRecentlyPlayedAfter :exec
+UPDATE users
+SET recently_played_after = $2
+WHERE id = $1
+`
+
+type UserUpdateRecentlyPlayedAfterParams struct {
+	ID                  int32
+	RecentlyPlayedAfter pgtype.Timestamptz
+}
+
+func (q *Queries) UserUpdateRecentlyPlayedAfter(ctx context.Context, arg UserUpdateRecentlyPlayedAfterParams) error {
+	_, err := q.db.Exec(ctx, userUpdateRecentlyPlayedAfter, arg.ID, arg.RecentlyPlayedAfter)
+	return err
+}
+
+const userUpdateRollupClean = `-- name: UserUpdateRollupClean :exec
+UPDATE users
+SET rollup_dirty_from = NULL
+WHERE id = $1
+`
+
+func (q *Queries) UserUpdateRollupClean(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, userUpdateRollupClean, id)
+	return err
+}
+
+const userUpdateRollupDirtyFrom = `-- name: UserUpdateRollupDirtyFrom :exec
+UPDATE users
+SET rollup_dirty_from = LEAST(rollup_dirty_from, $2)
+WHERE id = $1
+`
+
+type UserUpdateRollupDirtyFromParams struct {
+	ID              int32
+	RollupDirtyFrom pgtype.Timestamptz
+}
+
+func (q *Queries) UserUpdateRollupDirtyFrom(ctx context.Context, arg UserUpdateRollupDirtyFromParams) error {
+	_, err := q.db.Exec(ctx, userUpdateRollupDirtyFrom, arg.ID, arg.RollupDirtyFrom)
+	return err
+}
+
+const userUpdateRollupDirtyFromHistory = `-- name: UserUpdateRollupDirtyFromHistory :exec
+UPDATE users u
+SET rollup_dirty_from = LEAST(u.rollup_dirty_from, h.played_at)
+FROM history h
+WHERE h.id = $1 AND u.id = h.user_id
+`
+
+func (q *Queries) UserUpdateRollupDirtyFromHistory(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, userUpdateRollupDirtyFromHistory, id)
+	return err
+}
+
+const userUpdateTimezone = `-- name: UserUpdateTimezone :exec
+UPDATE users
+SET timezone = $2, rollup_dirty_from = '-infinity'
+WHERE id = $1
+`
+
+type UserUpdateTimezoneParams struct {
+	ID       int32
+	Timezone string
+}
+
+func (q *Queries) UserUpdateTimezone(ctx context.Context, arg UserUpdateTimezoneParams) error {
+	_, err := q.db.Exec(ctx, userUpdateTimezone, arg.ID, arg.Timezone)
+	return err
+}
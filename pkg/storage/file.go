@@ -0,0 +1,219 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// chunkSize is the size of the parts a file is split in
+// Only one chunk is kept in memory at a time
+const chunkSize = 4 * 1024 * 1024
+
+// filePrefix is the prefix of every key that belongs to a file
+// A file is stored as files/<key>/info and its chunks as files/<key>/<write id>/<index>
+const filePrefix = "files/"
+
+type fileInfo struct {
+	// Base is the key the chunks are written under
+	// Every write uses a new one so that an existing file is only replaced after a successful write
+	Base      string    `json:"base"`
+	Size      int64     `json:"size"`
+	Chunks    int       `json:"chunks"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+func (f fileInfo) expired() bool {
+	return !f.ExpiresAt.IsZero() && time.Now().After(f.ExpiresAt)
+}
+
+func infoKey(key string) string {
+	return filePrefix + key + "/info"
+}
+
+func chunkKey(base string, idx int) string {
+	return fmt.Sprintf("%s%s/%d", filePrefix, base, idx)
+}
+
+// getInfo returns nil if the file doesn't exist
+func getInfo(key string) (*fileInfo, error) {
+	data, err := Get(infoKey(key))
+	if err != nil {
+		return nil, fmt.Errorf("get file info %s | %w", key, err)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var info fileInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("unmarshal file info %s | %w", key, err)
+	}
+
+	return &info, nil
+}
+
+// Write streams the content of r to the storage
+// The content is split in chunks so it never has to fit in memory.
+// An existing file with the same key is only replaced once all content is stored.
+// An expiration of 0 keeps the file until it is removed.
+// Use Open to read it again and Remove to delete it.
+func Write(key string, r io.Reader, exp time.Duration) (int64, error) {
+	previous, err := getInfo(key)
+	if err != nil {
+		return 0, err
+	}
+
+	info := fileInfo{Base: key + "/" + uuid.NewString(), CreatedAt: time.Now()}
+	if exp > 0 {
+		info.ExpiresAt = info.CreatedAt.Add(exp)
+	}
+
+	buf := make([]byte, chunkSize)
+
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if err := S.Set(chunkKey(info.Base, info.Chunks), buf[:n], exp); err != nil {
+				_ = removeChunks(info.Base, info.Chunks+1) // nolint:errcheck // Too bad if it fails
+				return 0, fmt.Errorf("store chunk %d of %s | %w", info.Chunks, key, err)
+			}
+
+			info.Size += int64(n)
+			info.Chunks++
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			_ = removeChunks(info.Base, info.Chunks) // nolint:errcheck // Too bad if it fails
+			return 0, fmt.Errorf("read content for %s | %w", key, err)
+		}
+	}
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		_ = removeChunks(info.Base, info.Chunks) // nolint:errcheck // Too bad if it fails
+		return 0, fmt.Errorf("marshal file info %+v | %w", info, err)
+	}
+
+	if err := S.Set(infoKey(key), data, exp); err != nil {
+		_ = removeChunks(info.Base, info.Chunks) // nolint:errcheck // Too bad if it fails
+		return 0, fmt.Errorf("store file info %s | %w", key, err)
+	}
+
+	if previous != nil {
+		// The previous content is no longer referenced
+		_ = removeChunks(previous.Base, previous.Chunks) // nolint:errcheck // Left for the garbage collection
+	}
+
+	return info.Size, nil
+}
+
+// Remove deletes a file created with Write
+// Removing a file that doesn't exist is not an error
+func Remove(key string) error {
+	info, err := getInfo(key)
+	if err != nil {
+		return err
+	}
+	if info == nil {
+		return nil
+	}
+
+	// Delete the info first so that the file is gone even if a chunk can't be deleted
+	if err := S.Delete(infoKey(key)); err != nil {
+		return fmt.Errorf("delete file info %s | %w", key, err)
+	}
+
+	return removeChunks(info.Base, info.Chunks)
+}
+
+func removeChunks(base string, chunks int) error {
+	var errs []error
+
+	for i := range chunks {
+		if err := S.Delete(chunkKey(base, i)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// File gives random access to a file created with Write
+type File struct {
+	key  string
+	info fileInfo
+
+	mu       sync.Mutex
+	chunkIdx int
+	chunk    []byte
+}
+
+var _ io.ReaderAt = (*File)(nil)
+
+// Open returns a file created with Write
+// It returns nil if the file doesn't exist or is expired
+func Open(key string) (*File, error) {
+	info, err := getInfo(key)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil || info.expired() {
+		return nil, nil
+	}
+
+	return &File{
+		key:      key,
+		info:     *info,
+		chunkIdx: -1,
+	}, nil
+}
+
+func (f *File) Size() int64 {
+	return f.info.Size
+}
+
+func (f *File) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("negative offset %d", off)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	read := 0
+	for read < len(p) {
+		pos := off + int64(read)
+		if pos >= f.info.Size {
+			return read, io.EOF
+		}
+
+		idx := int(pos / chunkSize)
+		if idx != f.chunkIdx {
+			chunk, err := S.Get(chunkKey(f.info.Base, idx))
+			if err != nil {
+				return read, fmt.Errorf("get chunk %d of %s | %w", idx, f.key, err)
+			}
+
+			f.chunkIdx = idx
+			f.chunk = chunk
+		}
+
+		start := int(pos % chunkSize)
+		if start >= len(f.chunk) {
+			return read, io.ErrUnexpectedEOF
+		}
+
+		read += copy(p[read:], f.chunk[start:])
+	}
+
+	return read, nil
+}
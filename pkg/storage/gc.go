@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/storage/minio"
+	miniogo "github.com/minio/minio-go/v7"
+	"go.uber.org/zap"
+)
+
+// gcOrphanAge is how old chunks without a file info have to be before they are removed
+// A file that is still being written has no info yet
+const gcOrphanAge = 24 * time.Hour
+
+// gc periodically removes the expired files created with Write
+// The postgres provider removes expired keys itself
+func gc(m *minio.Storage, bucket string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := gcRun(context.Background(), m, bucket); err != nil {
+			zap.S().Error(err)
+		}
+	}
+}
+
+// gcRun removes the expired files and the chunks no file refers to
+func gcRun(ctx context.Context, m *minio.Storage, bucket string) error {
+	type chunks struct {
+		keys     []string
+		modified time.Time
+	}
+
+	files := make([]string, 0)
+	bases := make(map[string]*chunks)
+
+	for object := range m.Conn().ListObjects(ctx, bucket, miniogo.ListObjectsOptions{Prefix: filePrefix, Recursive: true}) {
+		if object.Err != nil {
+			return fmt.Errorf("list files | %w", object.Err)
+		}
+
+		name := strings.TrimPrefix(object.Key, filePrefix)
+		if key, ok := strings.CutSuffix(name, "/info"); ok {
+			files = append(files, key)
+			continue
+		}
+
+		idx := strings.LastIndex(name, "/")
+		if idx == -1 {
+			continue
+		}
+
+		base := name[:idx]
+		if _, ok := bases[base]; !ok {
+			bases[base] = &chunks{}
+		}
+		bases[base].keys = append(bases[base].keys, object.Key)
+		if object.LastModified.After(bases[base].modified) {
+			bases[base].modified = object.LastModified
+		}
+	}
+
+	var errs []error
+
+	for _, key := range files {
+		info, err := getInfo(key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if info == nil {
+			continue
+		}
+
+		if info.expired() {
+			if err := Remove(key); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		delete(bases, info.Base)
+	}
+
+	// Chunks of failed writes or of a previous version of a file
+	for _, c := range bases {
+		if time.Since(c.modified) < gcOrphanAge {
+			continue
+		}
+
+		for _, key := range c.keys {
+			if err := S.Delete(key); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/gofiber/storage/minio"
 	"github.com/gofiber/storage/postgres/v3"
 	"github.com/jackc/pgx/v5/pgxpool"
+	miniogo "github.com/minio/minio-go/v7"
 	"github.com/topvennie/sortifyr/pkg/config"
 )
 
@@ -20,8 +21,9 @@ func New(pool *pgxpool.Pool) error {
 	switch provider {
 
 	case "minio":
-		S = minio.New(minio.Config{
-			Bucket:   config.GetDefaultString("minio.bucket", "sortifyr"),
+		bucket := config.GetDefaultString("minio.bucket", "sortifyr")
+		m := minio.New(minio.Config{
+			Bucket:   bucket,
 			Endpoint: config.GetDefaultString("minio.endpoint", "minio:9000"),
 			Secure:   config.GetDefaultBool("minio.secure", false),
 			Credentials: minio.Credentials{
@@ -29,6 +31,10 @@ func New(pool *pgxpool.Pool) error {
 				SecretAccessKey: config.GetDefaultString("minio.password", "miniominio"),
 			},
 		})
+		S = m
+
+		// Minio ignores the expiration of a key
+		go gc(m, bucket, config.GetDefaultDurationS("minio.gc_interval_s", 60*60))
 
 	case "postgres":
 		S = postgres.New(postgres.Config{
@@ -44,3 +50,27 @@ func New(pool *pgxpool.Pool) error {
 
 	return nil
 }
+
+// Get returns the value of a key
+// Unlike S.Get it returns nil for a key that doesn't exist for every provider
+func Get(key string) ([]byte, error) {
+	data, err := S.Get(key)
+	if err != nil {
+		if miniogo.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Delete removes a key
+// Unlike S.Delete it ignores a key that doesn't exist for every provider
+func Delete(key string) error {
+	if err := S.Delete(key); err != nil && miniogo.ToErrorResponse(err).Code != "NoSuchKey" {
+		return err
+	}
+
+	return nil
+}